@@ -0,0 +1,159 @@
+package gofin
+
+import (
+	"fmt"
+	"math"
+)
+
+// AmortizationMethod selects how a loan's principal is repaid.
+type AmortizationMethod int
+
+const (
+	// FixedPayment repays the loan with a level payment each period, so the
+	// principal portion grows as the interest portion shrinks.
+	FixedPayment AmortizationMethod = iota
+
+	// FixedPrincipal repays the same amount of principal each period plus
+	// the interest on the outstanding balance, so payments decline.
+	FixedPrincipal
+)
+
+// LoanSpec describes a loan to amortize.
+type LoanSpec struct {
+	// Principal is the amount borrowed.
+	Principal float64
+
+	// Rate is the interest rate per period, for example 0.06 / 12 for a
+	// monthly loan at 6% a year.
+	Rate float64
+
+	// Periods is the term of the loan, including any interest-only periods.
+	Periods int
+
+	// Method selects fixed payment or fixed principal amortization.
+	Method AmortizationMethod
+
+	// InterestOnlyPeriods is the number of leading periods in which only
+	// interest is paid.
+	InterestOnlyPeriods int
+
+	// Balloon is the balance left unamortized by the regular payments and
+	// repaid together with the last one.
+	Balloon float64
+
+	// ExtraPayments holds extra principal prepayments keyed by period
+	// number, starting at 1. Prepayments shorten the loan; they do not
+	// reduce the regular payment.
+	ExtraPayments map[int]float64
+}
+
+// AmortizationRow is one period of an amortization schedule.
+type AmortizationRow struct {
+	Period             int
+	Payment            float64
+	Interest           float64
+	Principal          float64
+	ExtraPrincipal     float64
+	Balance            float64
+	CumulativeInterest float64
+}
+
+// Amortize returns the period-by-period repayment schedule of a loan. The
+// schedule ends early if extra payments clear the balance before the term.
+// Payment includes ExtraPrincipal, and Principal excludes it.
+func Amortize(spec LoanSpec) ([]AmortizationRow, error) {
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+
+	amortizingPeriods := spec.Periods - spec.InterestOnlyPeriods
+	var regularPayment, regularPrincipal float64
+	switch spec.Method {
+	case FixedPayment:
+		regularPayment = levelPayment(spec.Principal, spec.Rate, amortizingPeriods, spec.Balloon)
+	case FixedPrincipal:
+		regularPrincipal = (spec.Principal - spec.Balloon) / float64(amortizingPeriods)
+	}
+
+	// Balances below this are treated as fully repaid to absorb rounding.
+	paidOff := spec.Principal * 1e-12
+
+	schedule := make([]AmortizationRow, 0, spec.Periods)
+	balance := spec.Principal
+	cumulativeInterest := 0.0
+	for period := 1; period <= spec.Periods && balance > paidOff; period++ {
+		interest := balance * spec.Rate
+
+		var principal float64
+		switch {
+		case period <= spec.InterestOnlyPeriods:
+			principal = 0
+		case period == spec.Periods:
+			principal = balance
+		case spec.Method == FixedPayment:
+			principal = regularPayment - interest
+		default:
+			principal = regularPrincipal
+		}
+		principal = math.Min(principal, balance)
+
+		extra := math.Min(math.Max(spec.ExtraPayments[period], 0), balance-principal)
+
+		balance -= principal + extra
+		if balance <= paidOff {
+			balance = 0
+		}
+		cumulativeInterest += interest
+
+		schedule = append(schedule, AmortizationRow{
+			Period:             period,
+			Payment:            interest + principal + extra,
+			Interest:           interest,
+			Principal:          principal,
+			ExtraPrincipal:     extra,
+			Balance:            balance,
+			CumulativeInterest: cumulativeInterest,
+		})
+	}
+
+	return schedule, nil
+}
+
+// validate checks that the loan specification is consistent.
+func (spec LoanSpec) validate() error {
+	switch {
+	case spec.Principal <= 0:
+		return fmt.Errorf("%w: principal must be positive", ErrInvalidLoan)
+	case spec.Rate <= -1:
+		return ErrInvalidRate
+	case spec.Periods <= 0:
+		return ErrInvalidPeriods
+	case spec.InterestOnlyPeriods < 0 || spec.InterestOnlyPeriods >= spec.Periods:
+		return fmt.Errorf("%w: interest-only periods must be fewer than the term", ErrInvalidLoan)
+	case spec.Balloon < 0 || spec.Balloon > spec.Principal:
+		return fmt.Errorf("%w: balloon must be between zero and the principal", ErrInvalidLoan)
+	case spec.Method != FixedPayment && spec.Method != FixedPrincipal:
+		return fmt.Errorf("%w: unknown amortization method %d", ErrInvalidLoan, spec.Method)
+	}
+	return nil
+}
+
+// levelPayment returns the payment per period that amortizes principal down
+// to balloon over periods. It discounts the balloon and sums the payment
+// discount factors exactly as PresentValue and PresentValueAnnuity do.
+// PMT = (P - B / (1 + r)^n) / a(n)
+// P is the principal,
+// B is the balloon,
+// a(n) is the annuity factor for n periods.
+func levelPayment(principal, rate float64, periods int, balloon float64) float64 {
+	return (principal - PresentValue(balloon, rate, periods)) / annuityFactor(rate, periods)
+}
+
+// annuityFactor returns the present value of 1 paid at the end of each of periods periods.
+// a(n) = (1 - 1 / (1 + r)^n) / r
+func annuityFactor(rate float64, periods int) float64 {
+	if rate == 0 {
+		return float64(periods)
+	}
+	return (1 - PresentValue(1, rate, periods)) / rate
+}
@@ -0,0 +1,106 @@
+package gofin
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAmortizeFixedPayment(t *testing.T) {
+	spec := LoanSpec{Principal: 100000, Rate: 0.06 / 12, Periods: 360}
+	var expectedPayment float64 = 599.5505251527569
+
+	schedule, err := Amortize(spec)
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	if len(schedule) != 360 {
+		t.Fatalf("Test failed, expected: '%d' rows, got: '%d'", 360, len(schedule))
+	}
+	for _, row := range schedule {
+		if notWithin(row.Payment, expectedPayment, 1e-8) {
+			t.Fatalf("Test failed, period %d expected: '%f', got: '%f'", row.Period, expectedPayment, row.Payment)
+		}
+	}
+	if notWithin(schedule[0].Interest, 500, 1e-9) || notWithin(schedule[0].Principal, expectedPayment-500, 1e-9) {
+		t.Errorf("Test failed, unexpected first row: %+v", schedule[0])
+	}
+	last := schedule[len(schedule)-1]
+	if last.Balance != 0 || notWithin(last.CumulativeInterest, 360*expectedPayment-100000, 1e-6) {
+		t.Errorf("Test failed, unexpected last row: %+v", last)
+	}
+}
+
+func TestAmortizeFixedPrincipal(t *testing.T) {
+	spec := LoanSpec{Principal: 1200, Rate: 0.01, Periods: 12, Method: FixedPrincipal}
+
+	schedule, err := Amortize(spec)
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	for i, row := range schedule {
+		expectedInterest := float64(1200-100*i) * 0.01
+		if notWithin(row.Principal, 100, 1e-9) || notWithin(row.Interest, expectedInterest, 1e-9) {
+			t.Errorf("Test failed, period %d expected: '%f' + '%f', got: '%f' + '%f'", row.Period, 100.0, expectedInterest, row.Principal, row.Interest)
+		}
+	}
+	if notWithin(schedule[11].CumulativeInterest, 78, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", 78.0, schedule[11].CumulativeInterest)
+	}
+}
+
+func TestAmortizeInterestOnlyAndBalloon(t *testing.T) {
+	spec := LoanSpec{Principal: 10000, Rate: 0.01, Periods: 24, InterestOnlyPeriods: 12, Balloon: 4000}
+
+	schedule, err := Amortize(spec)
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	for _, row := range schedule[:12] {
+		if row.Principal != 0 || notWithin(row.Payment, 100, 1e-9) || row.Balance != 10000 {
+			t.Errorf("Test failed, expected an interest-only row, got: %+v", row)
+		}
+	}
+
+	payment := levelPayment(10000, 0.01, 12, 4000)
+	if notWithin(schedule[12].Payment, payment, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", payment, schedule[12].Payment)
+	}
+	last := schedule[23]
+	if notWithin(last.Payment, payment+4000, 1e-6) || last.Balance != 0 {
+		t.Errorf("Test failed, expected a final payment of '%f', got: %+v", payment+4000, last)
+	}
+}
+
+func TestAmortizeExtraPayments(t *testing.T) {
+	spec := LoanSpec{Principal: 10000, Rate: 0.01, Periods: 12, ExtraPayments: map[int]float64{1: 5000}}
+
+	schedule, err := Amortize(spec)
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	if len(schedule) >= 12 {
+		t.Errorf("Test failed, expected the prepayment to shorten the loan, got: '%d' rows", len(schedule))
+	}
+	if schedule[0].ExtraPrincipal != 5000 || schedule[len(schedule)-1].Balance != 0 {
+		t.Errorf("Test failed, unexpected schedule: %+v", schedule)
+	}
+	if notWithin(schedule[1].Payment, schedule[2].Payment, 1e-9) {
+		t.Errorf("Test failed, expected the regular payment to stay level, got: '%f' and '%f'", schedule[1].Payment, schedule[2].Payment)
+	}
+}
+
+func TestAmortizeErrors(t *testing.T) {
+	specs := []LoanSpec{
+		{Principal: 0, Rate: 0.01, Periods: 12},
+		{Principal: 1000, Rate: 0.01, Periods: 12, InterestOnlyPeriods: 12},
+		{Principal: 1000, Rate: 0.01, Periods: 12, Balloon: 2000},
+	}
+	for _, spec := range specs {
+		if _, err := Amortize(spec); !errors.Is(err, ErrInvalidLoan) {
+			t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidLoan, err)
+		}
+	}
+	if _, err := Amortize(LoanSpec{Principal: 1000, Rate: 0.01}); !errors.Is(err, ErrInvalidPeriods) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidPeriods, err)
+	}
+}
@@ -0,0 +1,142 @@
+package gofin
+
+import "math"
+
+// FutureValueGrowingAnnuity returns the future value of an annuity whose payments grow at a constant rate.
+// FV = C * ((1 + r)^n - (1 + g)^n) / (r - g)
+// FV = n * C * (1 + r)^(n - 1) when r == g
+// FV is the future value,
+// C is the cash flow at the end of the first period,
+// r is the interest rate,
+// g is the growth rate,
+// n is the number of periods.
+func FutureValueGrowingAnnuity(payment, interestRate, growthRate float64, periods int) float64 {
+	fv, _ := FutureValueGrowingAnnuityE(payment, interestRate, growthRate, periods)
+	return fv
+}
+
+// FutureValueGrowingAnnuityE is like FutureValueGrowingAnnuity but returns an error for a rate
+// or growth rate at or below -1 or a negative number of periods.
+func FutureValueGrowingAnnuityE(payment, interestRate, growthRate float64, periods int) (float64, error) {
+	if err := checkGrowingAnnuity(interestRate, growthRate, periods); err != nil {
+		return 0.0, err
+	}
+	return payment * growingAnnuityFactor(interestRate, growthRate, periods) * math.Pow(1+interestRate, float64(periods)), nil
+}
+
+// FutureValueGrowingAnnuityDue returns the future value of a growing annuity whose payments are
+// made at the beginning of each period.
+// FV = C * ((1 + r)^n - (1 + g)^n) / (r - g) * (1 + r)
+// FV = n * C * (1 + r)^n when r == g
+// FV is the future value,
+// C is the cash flow at the beginning of the first period,
+// r is the interest rate,
+// g is the growth rate,
+// n is the number of periods.
+func FutureValueGrowingAnnuityDue(payment, interestRate, growthRate float64, periods int) float64 {
+	fv, _ := FutureValueGrowingAnnuityDueE(payment, interestRate, growthRate, periods)
+	return fv
+}
+
+// FutureValueGrowingAnnuityDueE is like FutureValueGrowingAnnuityDue but returns an error for a
+// rate or growth rate at or below -1 or a negative number of periods.
+func FutureValueGrowingAnnuityDueE(payment, interestRate, growthRate float64, periods int) (float64, error) {
+	fv, err := FutureValueGrowingAnnuityE(payment, interestRate, growthRate, periods)
+	if err != nil {
+		return 0.0, err
+	}
+	return fv * (1 + interestRate), nil
+}
+
+// ImpliedRateAnnuity returns the periodic discount rate at which periods level payments, paid
+// at the end or beginning of each period according to timing, are worth presentValue.
+// The rate is found numerically with the IRR solver.
+func ImpliedRateAnnuity(presentValue, payment float64, periods int, timing PaymentTiming) (float64, error) {
+	return ImpliedRateGrowingAnnuity(presentValue, payment, 0, periods, timing)
+}
+
+// ImpliedRateGrowingAnnuity returns the periodic discount rate at which periods payments,
+// starting at payment and growing by growthRate each period, are worth presentValue. The
+// payments are made at the end or beginning of each period according to timing.
+// The rate is found numerically with the IRR solver.
+func ImpliedRateGrowingAnnuity(presentValue, payment, growthRate float64, periods int, timing PaymentTiming) (float64, error) {
+	if err := checkGrowingAnnuity(0, growthRate, periods); err != nil {
+		return 0.0, err
+	}
+	if timing != EndOfPeriod && timing != BeginningOfPeriod {
+		return 0.0, ErrInvalidTiming
+	}
+	if periods == 0 {
+		return 0.0, ErrInvalidPeriods
+	}
+
+	// Lay the payments out as cash flows received for paying presentValue now.
+	cashFlows := make([]float64, periods+1)
+	cashFlows[0] = -presentValue
+	amount := payment
+	for t := 1; t <= periods; t++ {
+		cashFlows[t-int(timing)] += amount
+		amount *= 1 + growthRate
+	}
+
+	result, err := IRRSolver{}.Solve(cashFlows[:len(cashFlows)-int(timing)])
+	if err != nil {
+		return 0.0, err
+	}
+	return result.Rate, nil
+}
+
+// ImpliedRatePerpetuity returns the discount rate at which a perpetuity of payment, paid at the
+// end or beginning of each period according to timing, is worth presentValue.
+func ImpliedRatePerpetuity(presentValue, payment float64, timing PaymentTiming) (float64, error) {
+	return ImpliedRateGrowingPerpetuity(presentValue, payment, 0, timing)
+}
+
+// ImpliedRateGrowingPerpetuity returns the discount rate at which a perpetuity starting at
+// payment and growing by growthRate each period is worth presentValue. The payments are made at
+// the end or beginning of each period according to timing.
+func ImpliedRateGrowingPerpetuity(presentValue, payment, growthRate float64, timing PaymentTiming) (float64, error) {
+	switch timing {
+	case EndOfPeriod:
+		return InterestRateGrowingPerpetuityE(presentValue, payment, growthRate)
+	case BeginningOfPeriod:
+		return InterestRateGrowingPerpetuityDueE(presentValue, payment, growthRate)
+	default:
+		return 0.0, ErrInvalidTiming
+	}
+}
+
+// growingAnnuityFactor returns the present value of periods payments that start at 1 at the end
+// of the first period and grow by growthRate each period. It is computed through log1p and
+// expm1 of (1 + g) / (1 + r) - 1 so that it stays accurate as the interest rate approaches the
+// growth rate.
+func growingAnnuityFactor(interestRate, growthRate float64, periods int) float64 {
+	if interestRate == growthRate {
+		return float64(periods) / (1 + interestRate)
+	}
+	logRatio := math.Log1p((growthRate - interestRate) / (1 + interestRate))
+	return -math.Expm1(float64(periods)*logRatio) / (interestRate - growthRate)
+}
+
+// checkGrowingAnnuity validates the inputs shared by the growing annuity formulas.
+func checkGrowingAnnuity(interestRate, growthRate float64, periods int) error {
+	if err := checkRateAndPeriods(interestRate, periods); err != nil {
+		return err
+	}
+	if growthRate <= -1 {
+		return ErrInvalidRate
+	}
+	return nil
+}
+
+// checkRateAndPeriods validates the rate and period count shared by the
+// growing annuity formulas.
+func checkRateAndPeriods(interestRate float64, periods int) error {
+	if interestRate <= -1 {
+		return ErrInvalidRate
+	}
+	if periods < 0 {
+		return ErrInvalidPeriods
+	}
+	return nil
+}
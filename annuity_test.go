@@ -0,0 +1,214 @@
+package gofin
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// growingAnnuityBySum discounts each growing payment separately.
+func growingAnnuityBySum(payment, interestRate, growthRate float64, periods int) float64 {
+	pv := 0.0
+	for t := 1; t <= periods; t++ {
+		pv += payment * math.Pow(1+growthRate, float64(t-1)) / math.Pow(1+interestRate, float64(t))
+	}
+	return pv
+}
+
+func TestPresentValueGrowingAnnuityClosedForm(t *testing.T) {
+	tests := []struct {
+		interestRate, growthRate float64
+		periods                  int
+	}{
+		{0.08, 0.03, 10},
+		{0.05, 0.05, 20},
+		{0.03, 0.06, 15},
+		{0, 0.02, 12},
+		{0.07, -0.04, 30},
+		{0.05, 0, 1},
+	}
+
+	for _, tt := range tests {
+		expected := growingAnnuityBySum(100, tt.interestRate, tt.growthRate, tt.periods)
+
+		actual, err := PresentValueGrowingAnnuityE(tt.interestRate, tt.growthRate, tt.periods, []float64{100})
+		if err != nil || notWithin(actual, expected, 1e-9) {
+			t.Errorf("Test failed for %+v, expected: '%f', got: '%f' (%v)", tt, expected, actual, err)
+		}
+
+		due, err := PresentValueGrowingAnnuityDueE(tt.interestRate, tt.growthRate, tt.periods, []float64{100})
+		if err != nil || notWithin(due, expected*(1+tt.interestRate), 1e-9) {
+			t.Errorf("Test failed for %+v, expected: '%f', got: '%f' (%v)", tt, expected*(1+tt.interestRate), due, err)
+		}
+	}
+}
+
+func TestPresentValueGrowingAnnuityEqualRates(t *testing.T) {
+	// r == g: every payment is worth C / (1 + r) today.
+	var expected float64 = 10 * 100 / 1.05
+	actual := PresentValueGrowingAnnuity(0.05, 0.05, 10, []float64{100})
+
+	if notWithin(actual, expected, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", expected, actual)
+	}
+
+	// The closed form is continuous as r approaches g.
+	near := PresentValueGrowingAnnuity(0.05+1e-12, 0.05, 10, []float64{100})
+	if notWithin(near, expected, 1e-7) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", expected, near)
+	}
+
+	if actual := PresentValueGrowingAnnuityDue(0.05, 0.05, 10, []float64{100}); notWithin(actual, 1000, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", 1000.0, actual)
+	}
+}
+
+func TestPresentValueGrowingAnnuityErrors(t *testing.T) {
+	if _, err := PresentValueGrowingAnnuityE(0.05, 0.02, 10, nil); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrEmptyInput, err)
+	}
+	if _, err := PresentValueGrowingAnnuityDueE(0.05, 0.02, -1, []float64{100}); !errors.Is(err, ErrInvalidPeriods) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidPeriods, err)
+	}
+	if _, err := FutureValueGrowingAnnuityE(100, 0.05, -1, 10); !errors.Is(err, ErrInvalidRate) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidRate, err)
+	}
+}
+
+func TestFutureValueGrowingAnnuity(t *testing.T) {
+	// 100, 103 and 106.09 compounded at 8% to the end of year 3.
+	var expected float64 = 100*1.08*1.08 + 103*1.08 + 106.09
+	actual := FutureValueGrowingAnnuity(100, 0.08, 0.03, 3)
+
+	if notWithin(actual, expected, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", expected, actual)
+	}
+
+	if actual := FutureValueGrowingAnnuityDue(100, 0.08, 0.03, 3); notWithin(actual, expected*1.08, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", expected*1.08, actual)
+	}
+
+	// r == g: FV = n * C * (1 + r)^(n - 1)
+	expected = 3 * 100 * 1.05 * 1.05
+	if actual := FutureValueGrowingAnnuity(100, 0.05, 0.05, 3); notWithin(actual, expected, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", expected, actual)
+	}
+
+	// With no growth it matches the level annuity.
+	expected = FutureValueAnnuity(100, 0.06, 12)
+	if actual := FutureValueGrowingAnnuity(100, 0.06, 0, 12); notWithin(actual, expected, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", expected, actual)
+	}
+}
+
+func TestPresentValueAnnuityTiming(t *testing.T) {
+	cashFlows := []float64{100, 100}
+
+	var expected float64 = 100/1.1 + 100/1.21
+	if actual := PresentValueAnnuity(0.1, 2, cashFlows); notWithin(actual, expected, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", expected, actual)
+	}
+
+	expected = 100 + 100/1.1
+	if actual := PresentValueAnnuityDue(0.1, 2, cashFlows); notWithin(actual, expected, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", expected, actual)
+	}
+}
+
+func TestPresentValuePerpetuityDueFormulas(t *testing.T) {
+	var expected float64 = 1100
+	if actual := PresentValuePerpetuityDue(0.1, 100); notWithin(actual, expected, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", expected, actual)
+	}
+
+	expected = 2200
+	if actual := PresentValueGrowingPerpetuityDue(0.1, 0.05, 100); notWithin(actual, expected, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", expected, actual)
+	}
+}
+
+func TestInterestRatePerpetuityDueClosedForm(t *testing.T) {
+	var expected float64 = 0.1
+	if actual := InterestRatePerpetuityDue(1100, 100); notWithin(actual, expected, 1e-12) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", expected, actual)
+	}
+	if actual := InterestRateGrowingPerpetuityDue(2200, 100, 0.05); notWithin(actual, expected, 1e-12) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", expected, actual)
+	}
+	if _, err := InterestRatePerpetuityDueE(100, 100); !errors.Is(err, ErrNoSolution) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrNoSolution, err)
+	}
+}
+
+func TestImpliedRateAnnuityRoundTrip(t *testing.T) {
+	tests := []struct {
+		interestRate, growthRate float64
+		periods                  int
+	}{
+		{0.08, 0, 10},
+		{0.005, 0, 360},
+		{0.08, 0.03, 10},
+		{0.05, 0.05, 20},
+		{0.03, 0.06, 15},
+		{0.12, -0.02, 40},
+		{-0.02, 0.01, 8},
+	}
+
+	for _, tt := range tests {
+		for _, timing := range []PaymentTiming{EndOfPeriod, BeginningOfPeriod} {
+			var pv float64
+			var err error
+			if timing == EndOfPeriod {
+				pv, err = PresentValueGrowingAnnuityE(tt.interestRate, tt.growthRate, tt.periods, []float64{250})
+			} else {
+				pv, err = PresentValueGrowingAnnuityDueE(tt.interestRate, tt.growthRate, tt.periods, []float64{250})
+			}
+			if err != nil {
+				t.Fatalf("Test failed for %+v: %v", tt, err)
+			}
+
+			actual, err := ImpliedRateGrowingAnnuity(pv, 250, tt.growthRate, tt.periods, timing)
+			if err != nil || notWithin(actual, tt.interestRate, 1e-9) {
+				t.Errorf("Test failed for %+v timing %d, expected: '%f', got: '%f' (%v)", tt, timing, tt.interestRate, actual, err)
+			}
+		}
+	}
+
+	pv := PresentValueGrowingAnnuity(0.07, 0, 25, []float64{80})
+	if actual, err := ImpliedRateAnnuity(pv, 80, 25, EndOfPeriod); err != nil || notWithin(actual, 0.07, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", 0.07, actual, err)
+	}
+}
+
+func TestImpliedRatePerpetuityRoundTrip(t *testing.T) {
+	for _, interestRate := range []float64{0.02, 0.07, 0.15} {
+		for _, growthRate := range []float64{0, 0.01, -0.03} {
+			pv, _ := PresentValueGrowingPerpetuityE(interestRate, growthRate, 40)
+			if actual, err := ImpliedRateGrowingPerpetuity(pv, 40, growthRate, EndOfPeriod); err != nil || notWithin(actual, interestRate, 1e-12) {
+				t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", interestRate, actual, err)
+			}
+
+			pv, _ = PresentValueGrowingPerpetuityDueE(interestRate, growthRate, 40)
+			if actual, err := ImpliedRateGrowingPerpetuity(pv, 40, growthRate, BeginningOfPeriod); err != nil || notWithin(actual, interestRate, 1e-12) {
+				t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", interestRate, actual, err)
+			}
+		}
+
+		pv, _ := PresentValuePerpetuityDueE(interestRate, 40)
+		if actual, err := ImpliedRatePerpetuity(pv, 40, BeginningOfPeriod); err != nil || notWithin(actual, interestRate, 1e-12) {
+			t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", interestRate, actual, err)
+		}
+	}
+}
+
+func TestImpliedRateAnnuityErrors(t *testing.T) {
+	if _, err := ImpliedRateAnnuity(1000, 100, 0, EndOfPeriod); !errors.Is(err, ErrInvalidPeriods) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidPeriods, err)
+	}
+	if _, err := ImpliedRateAnnuity(1000, 100, 10, PaymentTiming(2)); !errors.Is(err, ErrInvalidTiming) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidTiming, err)
+	}
+	if _, err := ImpliedRateAnnuity(-1000, 100, 10, EndOfPeriod); !errors.Is(err, ErrNoSignChange) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrNoSignChange, err)
+	}
+}
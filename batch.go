@@ -0,0 +1,134 @@
+package gofin
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// Batch evaluates NPV and IRR over many cash flow scenarios at once, spread
+// over a pool of goroutines. Scenarios follow the NetPresentValue convention:
+// flows[i][0] occurs now and flows[i][t] at the end of period t.
+//
+// The zero value is ready to use.
+type Batch struct {
+	// Workers is the number of goroutines used. Defaults to GOMAXPROCS.
+	Workers int
+
+	// Solver finds each scenario's IRR. Its zero value uses the IRRSolver defaults.
+	Solver IRRSolver
+}
+
+// NPVBatch returns the NPV of every scenario in flows at every rate in rates,
+// using the default Batch. See Batch.NPV.
+func NPVBatch(rates []float64, flows [][]float64) ([][]float64, error) {
+	return Batch{}.NPV(rates, flows)
+}
+
+// IRRBatch returns the IRR of every scenario in flows, using the default
+// Batch. See Batch.IRR.
+func IRRBatch(flows [][]float64) ([]float64, []error) {
+	return Batch{}.IRR(flows)
+}
+
+// NPV returns npv[i][j], the net present value of flows[i] at rates[j]. The
+// discount factors of each rate are computed once, by repeated division
+// rather than math.Pow, and shared by every scenario, and all the results
+// are stored in a single allocation. It returns ErrInvalidRate when a rate is
+// at or below -1.
+func (b Batch) NPV(rates []float64, flows [][]float64) ([][]float64, error) {
+	periods := 0
+	for _, cashFlows := range flows {
+		if len(cashFlows) > periods {
+			periods = len(cashFlows)
+		}
+	}
+
+	// factors[j*periods+t] is the discount factor of rates[j] at period t.
+	factors := make([]float64, len(rates)*periods)
+	for j, rate := range rates {
+		if rate <= -1 {
+			return nil, fmt.Errorf("%w: rate %d is %g", ErrInvalidRate, j, rate)
+		}
+		discount := 1 / (1 + rate)
+		factor := 1.0
+		for t := 0; t < periods; t++ {
+			factors[j*periods+t] = factor
+			factor *= discount
+		}
+	}
+
+	values := make([]float64, len(flows)*len(rates))
+	npv := make([][]float64, len(flows))
+	for i := range npv {
+		npv[i] = values[i*len(rates) : (i+1)*len(rates) : (i+1)*len(rates)]
+	}
+
+	b.run(len(flows), func(i int) {
+		cashFlows := flows[i]
+		for j := range rates {
+			discount := factors[j*periods : j*periods+len(cashFlows)]
+			sum := 0.0
+			for t, cashFlow := range cashFlows {
+				sum += cashFlow * discount[t]
+			}
+			npv[i][j] = sum
+		}
+	})
+	return npv, nil
+}
+
+// IRR returns the IRR of every scenario in flows. It gives the same rate as
+// b.Solver.Solve for each scenario, without looking for other roots even when
+// the solver has AllRoots set. errs is nil when every
+// scenario succeeded; otherwise errs[i] holds the error of scenario i, whose
+// rate is then 0.0.
+func (b Batch) IRR(flows [][]float64) (rates []float64, errs []error) {
+	solver := b.Solver.withDefaults()
+	rates = make([]float64, len(flows))
+	scenarioErrs := make([]error, len(flows))
+
+	b.run(len(flows), func(i int) {
+		rates[i], scenarioErrs[i] = solver.rate(flows[i])
+	})
+
+	for _, err := range scenarioErrs {
+		if err != nil {
+			return rates, scenarioErrs
+		}
+	}
+	return rates, nil
+}
+
+// rate returns the IRR of cashFlows as Solve would.
+func (s IRRSolver) rate(cashFlows []float64) (float64, error) {
+	s.AllRoots = false
+	result, err := s.Solve(cashFlows)
+	if err != nil {
+		return 0.0, err
+	}
+	return result.Rate, nil
+}
+
+// run calls fn for every index below n, spread over the batch's workers.
+func (b Batch) run(n int, fn func(i int)) {
+	workers := b.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > n {
+		workers = n
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(first int) {
+			defer wg.Done()
+			for i := first; i < n; i += workers {
+				fn(i)
+			}
+		}(w)
+	}
+	wg.Wait()
+}
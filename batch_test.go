@@ -0,0 +1,118 @@
+package gofin
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestNPVBatch(t *testing.T) {
+	rates := []float64{0, 0.05, 0.1, -0.5}
+	flows := scenarios(200, 30)
+	flows = append(flows, nil, []float64{42})
+
+	npv, err := NPVBatch(rates, flows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(npv) != len(flows) {
+		t.Fatalf("Test failed, expected %d scenarios, got: %d", len(flows), len(npv))
+	}
+	for i, cashFlows := range flows {
+		for j, rate := range rates {
+			expected := NetPresentValue(rate, len(cashFlows), cashFlows)
+			if notWithin(npv[i][j], expected, 1e-9) {
+				t.Errorf("Test failed for scenario %d at rate %f, expected: '%f', got: '%f'", i, rate, expected, npv[i][j])
+			}
+		}
+	}
+
+	if _, err := NPVBatch([]float64{0.1, -1}, flows); !errors.Is(err, ErrInvalidRate) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidRate, err)
+	}
+}
+
+func TestIRRBatch(t *testing.T) {
+	flows := scenarios(200, 30)
+	for _, workers := range []int{1, 3, 0} {
+		rates, errs := Batch{Workers: workers}.IRR(flows)
+		if errs != nil {
+			t.Fatalf("Test failed, unexpected errors: %v", errs)
+		}
+		for i, cashFlows := range flows {
+			expected, _ := IRRSolver{}.Solve(cashFlows)
+			if notWithin(rates[i], expected.Rate, 1e-9) {
+				t.Errorf("Test failed for scenario %d, expected: '%f', got: '%f'", i, expected.Rate, rates[i])
+			}
+		}
+	}
+
+	// Failures are reported per scenario.
+	rates, errs := IRRBatch([][]float64{{-100, 110}, {100, 100}, {-100}})
+	if notWithin(rates[0], 0.1, 1e-9) || errs[0] != nil {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", 0.1, rates[0], errs[0])
+	}
+	if !errors.Is(errs[1], ErrNoSignChange) || !errors.Is(errs[2], ErrEmptyInput) {
+		t.Errorf("Test failed, got: %v", errs)
+	}
+
+	// A guess past the only root makes Newton fail and Brent take over.
+	rates, errs = Batch{Solver: IRRSolver{Guess: 9}}.IRR([][]float64{{-100, 0, 0, 0, 0, 0, 0, 0, 0, 0, 150}})
+	expected, _ := IRRSolver{Guess: 9}.Solve([]float64{-100, 0, 0, 0, 0, 0, 0, 0, 0, 0, 150})
+	if errs != nil || notWithin(rates[0], expected.Rate, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", expected.Rate, rates[0], errs)
+	}
+}
+
+func BenchmarkNPVScalar(b *testing.B) {
+	rates, flows := []float64{0.04, 0.06, 0.08, 0.1}, scenarios(10000, 40)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, cashFlows := range flows {
+			for _, rate := range rates {
+				NetPresentValue(rate, len(cashFlows), cashFlows)
+			}
+		}
+	}
+}
+
+func BenchmarkNPVBatch(b *testing.B) {
+	rates, flows := []float64{0.04, 0.06, 0.08, 0.1}, scenarios(10000, 40)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		NPVBatch(rates, flows)
+	}
+}
+
+func BenchmarkIRRScalar(b *testing.B) {
+	flows := scenarios(1000, 40)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, cashFlows := range flows {
+			InternalRateOfReturn(-cashFlows[0], cashFlows[1:])
+		}
+	}
+}
+
+func BenchmarkIRRBatch(b *testing.B) {
+	flows := scenarios(1000, 40)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		IRRBatch(flows)
+	}
+}
+
+// scenarios returns n reproducible cash flow vectors of the given length: an
+// initial investment followed by noisy inflows.
+func scenarios(n, length int) [][]float64 {
+	rng := rand.New(rand.NewSource(1))
+	flows := make([][]float64, n)
+	for i := range flows {
+		flows[i] = make([]float64, length)
+		flows[i][0] = -1000
+		for t := 1; t < length; t++ {
+			flows[i][t] = 80 + 40*rng.NormFloat64()
+		}
+	}
+	return flows
+}
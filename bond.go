@@ -0,0 +1,289 @@
+package gofin
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/lazarospsa/gofin/daycount"
+)
+
+// Bond is a fixed-rate coupon bond.
+//
+// Prices and accrued interest are in the same units as Face, so a bond with a
+// Face of 100 is quoted per 100 of par. Yields are annual rates compounded
+// Frequency times a year, following the street convention used by
+// spreadsheet PRICE and YIELD.
+type Bond struct {
+	// Face is the par amount repaid at maturity.
+	Face float64
+
+	// CouponRate is the annual coupon rate, for example 0.05 for 5%.
+	CouponRate float64
+
+	// Frequency is the number of coupons a year: 1, 2, 4 or 12.
+	Frequency int
+
+	// Maturity is the date the bond is redeemed at Face.
+	Maturity time.Time
+
+	// DayCount is the basis used to accrue interest within a coupon period.
+	// A nil DayCount uses daycount.ActualActualICMA, which counts actual
+	// days over the actual days in the period.
+	DayCount DayCounter
+
+	// CallDate and CallPrice describe an optional call. They are only used
+	// by YieldToCall.
+	CallDate  time.Time
+	CallPrice float64
+}
+
+// bondCashFlows is the remaining coupon schedule of a bond seen from a settlement date.
+type bondCashFlows struct {
+	coupon     float64
+	redemption float64
+	// periods is the number of coupons left.
+	periods int
+	// accrued is the fraction of the current coupon period already elapsed.
+	accrued float64
+	// stub is the fraction of a coupon period from the last coupon to a
+	// redemption between coupon dates, zero when it falls on one.
+	stub float64
+}
+
+// AccruedInterest returns the coupon interest accrued from the last coupon date to settlement.
+func (b Bond) AccruedInterest(settlement time.Time) (float64, error) {
+	cf, err := b.cashFlows(settlement, b.Maturity, b.Face)
+	if err != nil {
+		return 0.0, err
+	}
+	return cf.coupon * cf.accrued, nil
+}
+
+// DirtyPrice returns the full price of the bond, including accrued interest, at an annual yield.
+// P = sum(C / (1 + y/f)^(k - 1 + w)) + F / (1 + y/f)^(N - 1 + w)
+// C is the coupon per period,
+// F is the face value,
+// y is the yield,
+// f is the coupon frequency,
+// N is the number of coupons left,
+// w is the fraction of a period from settlement to the next coupon.
+func (b Bond) DirtyPrice(settlement time.Time, yield float64) (float64, error) {
+	cf, err := b.cashFlows(settlement, b.Maturity, b.Face)
+	if err != nil {
+		return 0.0, err
+	}
+	if err := b.checkYield(yield); err != nil {
+		return 0.0, err
+	}
+	price, _, _ := cf.price(yield, b.Frequency)
+	return price, nil
+}
+
+// CleanPrice returns the quoted price of the bond, excluding accrued interest, at an annual yield.
+func (b Bond) CleanPrice(settlement time.Time, yield float64) (float64, error) {
+	cf, err := b.cashFlows(settlement, b.Maturity, b.Face)
+	if err != nil {
+		return 0.0, err
+	}
+	if err := b.checkYield(yield); err != nil {
+		return 0.0, err
+	}
+	price, _, _ := cf.price(yield, b.Frequency)
+	return price - cf.coupon*cf.accrued, nil
+}
+
+// YieldToMaturity returns the annual yield at which the bond's clean price equals cleanPrice.
+func (b Bond) YieldToMaturity(settlement time.Time, cleanPrice float64) (float64, error) {
+	cf, err := b.cashFlows(settlement, b.Maturity, b.Face)
+	if err != nil {
+		return 0.0, err
+	}
+	return cf.yield(cleanPrice, b.Frequency, b.CouponRate)
+}
+
+// YieldToCall returns the annual yield at which the bond's clean price equals cleanPrice,
+// assuming it is redeemed at CallPrice on CallDate. A call date between coupon
+// dates also pays the coupon interest accrued since the last one.
+func (b Bond) YieldToCall(settlement time.Time, cleanPrice float64) (float64, error) {
+	if b.CallDate.IsZero() || b.CallPrice <= 0 {
+		return 0.0, fmt.Errorf("%w: bond has no call", ErrInvalidBond)
+	}
+	if b.CallDate.After(b.Maturity) {
+		return 0.0, fmt.Errorf("%w: call date is after maturity", ErrInvalidBond)
+	}
+	cf, err := b.cashFlows(settlement, b.CallDate, b.CallPrice)
+	if err != nil {
+		return 0.0, err
+	}
+	return cf.yield(cleanPrice, b.Frequency, b.CouponRate)
+}
+
+// CurrentYield returns the annual coupon divided by the clean price.
+func (b Bond) CurrentYield(cleanPrice float64) (float64, error) {
+	if cleanPrice <= 0 {
+		return 0.0, ErrZeroValue
+	}
+	return b.Face * b.CouponRate / cleanPrice, nil
+}
+
+// MacaulayDuration returns the present-value weighted average time to the bond's cash flows, in years.
+func (b Bond) MacaulayDuration(settlement time.Time, yield float64) (float64, error) {
+	cf, err := b.cashFlows(settlement, b.Maturity, b.Face)
+	if err != nil {
+		return 0.0, err
+	}
+	if err := b.checkYield(yield); err != nil {
+		return 0.0, err
+	}
+	price, weightedTime, _ := cf.price(yield, b.Frequency)
+	return weightedTime / price / float64(b.Frequency), nil
+}
+
+// ModifiedDuration returns the percentage change in price for a unit change in yield.
+// ModD = MacD / (1 + y/f)
+func (b Bond) ModifiedDuration(settlement time.Time, yield float64) (float64, error) {
+	macaulay, err := b.MacaulayDuration(settlement, yield)
+	if err != nil {
+		return 0.0, err
+	}
+	return macaulay / (1 + yield/float64(b.Frequency)), nil
+}
+
+// Convexity returns the second derivative of the dirty price with respect to yield divided by the price, in years squared.
+func (b Bond) Convexity(settlement time.Time, yield float64) (float64, error) {
+	cf, err := b.cashFlows(settlement, b.Maturity, b.Face)
+	if err != nil {
+		return 0.0, err
+	}
+	if err := b.checkYield(yield); err != nil {
+		return 0.0, err
+	}
+	f := float64(b.Frequency)
+	price, _, curvature := cf.price(yield, b.Frequency)
+	return curvature / (price * f * f * math.Pow(1+yield/f, 2)), nil
+}
+
+// DV01 returns the change in dirty price for a one basis point fall in yield.
+// DV01 = ModD * P * 0.0001
+func (b Bond) DV01(settlement time.Time, yield float64) (float64, error) {
+	modified, err := b.ModifiedDuration(settlement, yield)
+	if err != nil {
+		return 0.0, err
+	}
+	price, err := b.DirtyPrice(settlement, yield)
+	if err != nil {
+		return 0.0, err
+	}
+	return modified * price * 0.0001, nil
+}
+
+// cashFlows returns the coupons left between settlement and redemption. The
+// coupon schedule always runs back from Maturity, so an earlier redemption,
+// such as a call, truncates it: the coupons up to the redemption date are paid,
+// and a redemption between coupon dates also pays the interest accrued since
+// the last one.
+func (b Bond) cashFlows(settlement, redemption time.Time, redemptionAmount float64) (bondCashFlows, error) {
+	switch b.Frequency {
+	case 1, 2, 4, 12:
+	default:
+		return bondCashFlows{}, fmt.Errorf("%w: frequency must be 1, 2, 4 or 12", ErrInvalidBond)
+	}
+	if b.Face <= 0 {
+		return bondCashFlows{}, fmt.Errorf("%w: face value must be positive", ErrInvalidBond)
+	}
+	if !settlement.Before(redemption) {
+		return bondCashFlows{}, fmt.Errorf("%w: settlement must be before redemption", ErrInvalidBond)
+	}
+
+	// Step back from maturity one coupon at a time until the coupon period
+	// containing settlement is found.
+	months := 12 / b.Frequency
+	coupon := func(k int) time.Time {
+		return daycount.AddMonths(b.Maturity, -months*k)
+	}
+	periods := 1
+	for coupon(periods).After(settlement) {
+		periods++
+	}
+	previous, next := coupon(periods), coupon(periods-1)
+
+	dayCount := b.DayCount
+	if dayCount == nil {
+		dayCount = daycount.ActualActualICMA{Frequency: b.Frequency, Anchor: b.Maturity}
+	}
+	accrued := dayCount.YearFraction(previous, settlement) / dayCount.YearFraction(previous, next)
+
+	// Keep the coupons paid on or before the redemption date.
+	paid := 0
+	for paid < periods && !coupon(periods-paid-1).After(redemption) {
+		paid++
+	}
+	stub := 0.0
+	if last := coupon(periods - paid); last.Before(redemption) {
+		stub = dayCount.YearFraction(last, redemption) / dayCount.YearFraction(last, coupon(periods-paid-1))
+	}
+
+	return bondCashFlows{
+		coupon:     b.Face * b.CouponRate / float64(b.Frequency),
+		redemption: redemptionAmount,
+		periods:    paid,
+		accrued:    accrued,
+		stub:       stub,
+	}, nil
+}
+
+// checkYield rejects yields at or below -f, where 1 + y/f is not positive.
+func (b Bond) checkYield(yield float64) error {
+	if yield <= -float64(b.Frequency) {
+		return ErrInvalidRate
+	}
+	return nil
+}
+
+// price returns the dirty price at yield, the sum of each discounted cash flow
+// weighted by its time in periods, and the sum weighted by t(t+1), which give
+// the duration and convexity.
+func (cf bondCashFlows) price(yield float64, frequency int) (float64, float64, float64) {
+	discount := 1 / (1 + yield/float64(frequency))
+	w := 1 - cf.accrued
+
+	price, weightedTime, curvature := 0.0, 0.0, 0.0
+	add := func(amount, t float64) {
+		pv := amount * math.Pow(discount, t)
+		price += pv
+		weightedTime += t * pv
+		curvature += t * (t + 1) * pv
+	}
+	for k := 1; k <= cf.periods; k++ {
+		add(cf.coupon, float64(k-1)+w)
+	}
+	add(cf.redemption+cf.coupon*cf.stub, float64(cf.periods-1)+w+cf.stub)
+	return price, weightedTime, curvature
+}
+
+// yield solves for the yield that prices the cash flows at cleanPrice.
+func (cf bondCashFlows) yield(cleanPrice float64, frequency int, guess float64) (float64, error) {
+	if cleanPrice <= 0 {
+		return 0.0, ErrZeroValue
+	}
+	target := cleanPrice + cf.coupon*cf.accrued
+	f := float64(frequency)
+
+	// Solve in the periodic rate y/f so the IRR search bounds apply.
+	s := IRRSolver{Guess: guess / f, Tolerance: 1e-15}.withDefaults()
+	fn := func(rate float64) float64 {
+		price, _, _ := cf.price(rate*f, frequency)
+		return price - target
+	}
+	dfn := func(rate float64) float64 {
+		_, weightedTime, _ := cf.price(rate*f, frequency)
+		return -weightedTime / (1 + rate)
+	}
+
+	result, err := s.solve(fn, dfn)
+	if err != nil {
+		return 0.0, err
+	}
+	return result.Rate * f, nil
+}
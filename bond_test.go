@@ -0,0 +1,153 @@
+package gofin
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBondCleanPrice(t *testing.T) {
+	// =PRICE("2008-02-15", "2017-11-15", 5.75%, 6.5%, 100, 2, 0)
+	bond := Bond{Face: 100, CouponRate: 0.0575, Frequency: 2, Maturity: date(2017, 11, 15), DayCount: Thirty360{}}
+	var expected float64 = 94.63436162
+
+	actual, err := bond.CleanPrice(date(2008, 2, 15), 0.065)
+	if err != nil || notWithin(actual, expected, 5e-9) {
+		t.Errorf("Test failed, expected: '%.8f', got: '%.8f' (%v)", expected, actual, err)
+	}
+
+	accrued, _ := bond.AccruedInterest(date(2008, 2, 15))
+	dirty, _ := bond.DirtyPrice(date(2008, 2, 15), 0.065)
+	if notWithin(accrued, 2.875*90/180, 1e-12) || notWithin(dirty-accrued, actual, 1e-12) {
+		t.Errorf("Test failed, unexpected accrued interest: '%f'", accrued)
+	}
+}
+
+func TestBondYieldToMaturity(t *testing.T) {
+	// =YIELD("2008-02-15", "2016-11-15", 5.75%, 95.04287, 100, 2, 0)
+	bond := Bond{Face: 100, CouponRate: 0.0575, Frequency: 2, Maturity: date(2016, 11, 15), DayCount: Thirty360{}}
+	var expected float64 = 0.065
+
+	actual, err := bond.YieldToMaturity(date(2008, 2, 15), 95.04287)
+	if err != nil || notWithin(actual, expected, 5e-8) {
+		t.Errorf("Test failed, expected: '%.8f', got: '%.8f' (%v)", expected, actual, err)
+	}
+}
+
+func TestBondPriceYieldRoundTrip(t *testing.T) {
+	bonds := []Bond{
+		{Face: 100, CouponRate: 0.0575, Frequency: 2, Maturity: date(2017, 11, 15), DayCount: Thirty360{}},
+		{Face: 1000, CouponRate: 0.03, Frequency: 1, Maturity: date(2040, 3, 31)},
+		{Face: 100, CouponRate: 0.12, Frequency: 4, Maturity: date(2029, 8, 31), DayCount: Actual365Fixed{}},
+		{Face: 100, CouponRate: 0, Frequency: 2, Maturity: date(2030, 6, 30)},
+	}
+	settlements := []time.Time{date(2009, 1, 1), date(2010, 2, 28), date(2012, 12, 17)}
+
+	for _, bond := range bonds {
+		for _, settlement := range settlements {
+			for _, yield := range []float64{-0.005, 0.01, 0.0575, 0.15} {
+				price, err := bond.CleanPrice(settlement, yield)
+				if err != nil {
+					t.Fatalf("Test failed, unexpected error: %v", err)
+				}
+				actual, err := bond.YieldToMaturity(settlement, price)
+				if err != nil || notWithin(actual, yield, 1e-10) {
+					t.Errorf("Test failed, expected: '%.12f', got: '%.12f' (%v)", yield, actual, err)
+				}
+				back, _ := bond.CleanPrice(settlement, actual)
+				if notWithin(back, price, 1e-10*bond.Face) {
+					t.Errorf("Test failed, expected: '%.12f', got: '%.12f'", price, back)
+				}
+			}
+		}
+	}
+}
+
+func TestBondDuration(t *testing.T) {
+	// =DURATION("2018-07-01", "2048-01-01", 8%, 9%, 2, 1)
+	bond := Bond{Face: 100, CouponRate: 0.08, Frequency: 2, Maturity: date(2048, 1, 1)}
+	var expected float64 = 10.9191453
+
+	actual, err := bond.MacaulayDuration(date(2018, 7, 1), 0.09)
+	if err != nil || notWithin(actual, expected, 5e-8) {
+		t.Errorf("Test failed, expected: '%.8f', got: '%.8f' (%v)", expected, actual, err)
+	}
+
+	// =MDURATION("2008-01-01", "2016-01-01", 8%, 9%, 2, 1)
+	bond = Bond{Face: 100, CouponRate: 0.08, Frequency: 2, Maturity: date(2016, 1, 1)}
+	expected = 5.99377496 / 1.045
+
+	actual, err = bond.ModifiedDuration(date(2008, 1, 1), 0.09)
+	if err != nil || notWithin(actual, expected, 5e-8) {
+		t.Errorf("Test failed, expected: '%.8f', got: '%.8f' (%v)", expected, actual, err)
+	}
+}
+
+func TestBondConvexityAndDV01(t *testing.T) {
+	bond := Bond{Face: 100, CouponRate: 0.05, Frequency: 2, Maturity: date(2035, 5, 15)}
+	settlement := date(2025, 9, 3)
+	yield := 0.045
+	const h = 1e-4
+
+	price, _ := bond.DirtyPrice(settlement, yield)
+	up, _ := bond.DirtyPrice(settlement, yield+h)
+	down, _ := bond.DirtyPrice(settlement, yield-h)
+
+	expected := (up + down - 2*price) / (h * h) / price
+	actual, err := bond.Convexity(settlement, yield)
+	if err != nil || notWithin(actual, expected, 1e-3) {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", expected, actual, err)
+	}
+
+	expected = (down - up) / 2
+	actual, err = bond.DV01(settlement, yield)
+	if err != nil || notWithin(actual, expected, 1e-6) {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", expected, actual, err)
+	}
+}
+
+func TestBondYieldToCallAndCurrentYield(t *testing.T) {
+	bond := Bond{Face: 100, CouponRate: 0.06, Frequency: 2, Maturity: date(2040, 6, 1), CallDate: date(2030, 6, 1), CallPrice: 102}
+	settlement := date(2025, 6, 1)
+
+	// Price the bond as if it matured at the call date, plus the 2 call
+	// premium discounted over the ten half-years to the call.
+	callable := Bond{Face: 100, CouponRate: 0.06, Frequency: 2, Maturity: bond.CallDate}
+	price, _ := callable.CleanPrice(settlement, 0.05)
+	price += PresentValue(2, 0.025, 10)
+
+	actual, err := bond.YieldToCall(settlement, price)
+	if err != nil || notWithin(actual, 0.05, 1e-10) {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", 0.05, actual, err)
+	}
+
+	// A call between coupon dates keeps the bond's own coupon schedule and
+	// pays the interest accrued since 15 June 2027 with the call price.
+	offCycle := Bond{Face: 100, CouponRate: 0.06, Frequency: 2, Maturity: date(2035, 12, 15), CallDate: date(2027, 9, 15), CallPrice: 101}
+	settlement = date(2025, 8, 1)
+	accrued, _ := offCycle.AccruedInterest(settlement)
+	w := 1 - accrued/3
+	stub := 92.0 / 183.0
+	dirty := (101 + 3*stub) / math.Pow(1.025, 3+w+stub)
+	for k := 1; k <= 4; k++ {
+		dirty += 3 / math.Pow(1.025, float64(k-1)+w)
+	}
+
+	actual, err = offCycle.YieldToCall(settlement, dirty-accrued)
+	if err != nil || notWithin(actual, 0.05, 1e-10) {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", 0.05, actual, err)
+	}
+
+	current, _ := bond.CurrentYield(120)
+	if notWithin(current, 0.05, 1e-12) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", 0.05, current)
+	}
+
+	if _, err := (Bond{Face: 100, CouponRate: 0.06, Frequency: 2, Maturity: date(2040, 6, 1)}).YieldToCall(settlement, 100); !errors.Is(err, ErrInvalidBond) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidBond, err)
+	}
+	if _, err := bond.CleanPrice(date(2041, 1, 1), 0.05); !errors.Is(err, ErrInvalidBond) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidBond, err)
+	}
+}
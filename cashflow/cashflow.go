@@ -0,0 +1,167 @@
+// Package cashflow holds series of cash flows read from, and written to, CSV
+// exports, and feeds them to the gofin NPV, IRR and payback functions.
+//
+// Amounts follow the gofin sign convention: money paid out is negative and
+// money received is positive. Format converts other conventions on the way
+// in and out.
+package cashflow
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/lazarospsa/gofin"
+)
+
+// Errors returned by the package.
+var (
+	// ErrInvalidRow is wrapped by the ParseError of a row that cannot be read.
+	ErrInvalidRow = errors.New("cashflow: invalid row")
+
+	// ErrInvalidFormat is returned for a Format with clashing separators or a
+	// header that lacks the columns it needs.
+	ErrInvalidFormat = errors.New("cashflow: invalid format")
+
+	// ErrUndated is returned by the dated calculations when a flow has no date.
+	ErrUndated = errors.New("cashflow: cash flow has no date")
+)
+
+// Flow is one cash flow.
+type Flow struct {
+	// Date is the date of the flow, or the zero time for a series of
+	// flows one period apart.
+	Date time.Time
+
+	Amount float64
+
+	// Category labels the flow, for example "capex" or "rent".
+	Category string
+}
+
+// Series is a list of cash flows. The periodic calculations treat the flows
+// as one period apart, the first one now; use Net first when several flows
+// fall on the same date.
+type Series struct {
+	Flows []Flow
+}
+
+// Amounts returns the amounts of the flows in order.
+func (s Series) Amounts() []float64 {
+	amounts := make([]float64, len(s.Flows))
+	for i, f := range s.Flows {
+		amounts[i] = f.Amount
+	}
+	return amounts
+}
+
+// Dated returns the flows as gofin dated cash flows, or ErrUndated if a flow
+// has no date.
+func (s Series) Dated() ([]gofin.DatedCashFlow, error) {
+	flows := make([]gofin.DatedCashFlow, len(s.Flows))
+	for i, f := range s.Flows {
+		if f.Date.IsZero() {
+			return nil, ErrUndated
+		}
+		flows[i] = gofin.DatedCashFlow{Date: f.Date, Amount: f.Amount}
+	}
+	return flows, nil
+}
+
+// Category returns the flows with the given category.
+func (s Series) Category(category string) Series {
+	var filtered Series
+	for _, f := range s.Flows {
+		if f.Category == category {
+			filtered.Flows = append(filtered.Flows, f)
+		}
+	}
+	return filtered
+}
+
+// Totals returns the sum of the amounts of each category.
+func (s Series) Totals() map[string]float64 {
+	totals := make(map[string]float64)
+	for _, f := range s.Flows {
+		totals[f.Category] += f.Amount
+	}
+	return totals
+}
+
+// Net returns one flow per date, with the sum of the amounts on that date and
+// no category, in date order.
+func (s Series) Net() Series {
+	byDate := make(map[time.Time]int)
+	var net Series
+	for _, f := range s.Flows {
+		if i, ok := byDate[f.Date]; ok {
+			net.Flows[i].Amount += f.Amount
+			continue
+		}
+		byDate[f.Date] = len(net.Flows)
+		net.Flows = append(net.Flows, Flow{Date: f.Date, Amount: f.Amount})
+	}
+	sort.SliceStable(net.Flows, func(i, j int) bool { return net.Flows[i].Date.Before(net.Flows[j].Date) })
+	return net
+}
+
+// NPV returns the net present value of the flows at a rate per period, with
+// the first flow undiscounted, like gofin.NetPresentValueE.
+func (s Series) NPV(rate float64) (float64, error) {
+	amounts := s.Amounts()
+	return gofin.NetPresentValueE(rate, len(amounts), amounts)
+}
+
+// XNPV returns the net present value of the dated flows at an annual rate,
+// like gofin.XNPV.
+func (s Series) XNPV(rate float64) (float64, error) {
+	flows, err := s.Dated()
+	if err != nil {
+		return 0.0, err
+	}
+	return gofin.XNPV(rate, flows)
+}
+
+// IRR returns the internal rate of return per period of the flows, using the
+// default gofin.IRRSolver.
+func (s Series) IRR() (float64, error) {
+	result, err := gofin.IRRSolver{}.Solve(s.Amounts())
+	if err != nil {
+		return 0.0, err
+	}
+	return result.Rate, nil
+}
+
+// XIRR returns the annual internal rate of return of the dated flows, like
+// gofin.XIRR with the default options.
+func (s Series) XIRR() (float64, error) {
+	flows, err := s.Dated()
+	if err != nil {
+		return 0.0, err
+	}
+	result, err := gofin.XIRR(flows, gofin.XIRROptions{})
+	if err != nil {
+		return 0.0, err
+	}
+	return result.Rate, nil
+}
+
+// Payback returns the number of periods after the first flow, taken as the
+// investment, until the cumulative flows recover it, like gofin.PaybackPeriodE.
+func (s Series) Payback() (int, error) {
+	if len(s.Flows) == 0 {
+		return -1, gofin.ErrEmptyInput
+	}
+	amounts := s.Amounts()
+	return gofin.PaybackPeriodE(-amounts[0], amounts[1:])
+}
+
+// DiscountedPayback is like Payback but discounts the flows at a rate per
+// period, like gofin.DiscountedPaybackPeriodE.
+func (s Series) DiscountedPayback(rate float64) (int, error) {
+	if len(s.Flows) == 0 {
+		return -1, gofin.ErrEmptyInput
+	}
+	amounts := s.Amounts()
+	return gofin.DiscountedPaybackPeriodE(-amounts[0], amounts[1:], rate)
+}
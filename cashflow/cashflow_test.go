@@ -0,0 +1,211 @@
+package cashflow
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lazarospsa/gofin"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		format Format
+		flows  []Flow
+	}{
+		{
+			name:  "default",
+			input: "date,amount,category\n2021-01-01,-1000,capex\n2021-07-01,250.5,rent\n",
+			flows: []Flow{{date(2021, 1, 1), -1000, "capex"}, {date(2021, 7, 1), 250.5, "rent"}},
+		},
+		{
+			name:   "european",
+			input:  "Datum;Betrag\n01.01.2021;-1.000,00\n01.07.2021;250,50\n",
+			format: Format{Delimiter: ';', DecimalSeparator: ',', ThousandsSeparator: '.', DateLayout: "02.01.2006", Columns: Columns{Date: "datum", Amount: "betrag"}},
+			flows:  []Flow{{date(2021, 1, 1), -1000, ""}, {date(2021, 7, 1), 250.5, ""}},
+		},
+		{
+			name:   "inverted with parentheses",
+			input:  "amount\n\"1,000\"\n(250.50)\n",
+			format: Format{Sign: Inverted, ThousandsSeparator: ','},
+			flows:  []Flow{{Amount: -1000}, {Amount: 250.5}},
+		},
+		{
+			name:   "debit and credit",
+			input:  "Date,Description,Debit,Credit\n2021-01-01,machine,1000,\n2021-07-01,sales,,250.5\n",
+			format: Format{Sign: DebitCredit},
+			flows:  []Flow{{date(2021, 1, 1), -1000, ""}, {date(2021, 7, 1), 250.5, ""}},
+		},
+	}
+
+	for _, test := range tests {
+		s, err := Read(strings.NewReader(test.input), test.format)
+		if err != nil {
+			t.Errorf("Test %s failed: %v", test.name, err)
+			continue
+		}
+		if len(s.Flows) != len(test.flows) {
+			t.Errorf("Test %s failed, expected: %v, got: %v", test.name, test.flows, s.Flows)
+			continue
+		}
+		for i, flow := range s.Flows {
+			if !flow.Date.Equal(test.flows[i].Date) || flow.Amount != test.flows[i].Amount || flow.Category != test.flows[i].Category {
+				t.Errorf("Test %s failed in row %d, expected: %v, got: %v", test.name, i, test.flows[i], flow)
+			}
+		}
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		format Format
+		line   int
+		column string
+	}{
+		{"date,amount\n2021-01-01,-1000\n2021-02-30,5\n", Format{}, 3, "date"},
+		{"amount\n1\n\n2\nabc\n", Format{}, 5, "amount"},
+		{"amount,category\n1,a\n,b\n", Format{}, 3, "amount"},
+		{"amount\n1.5\n", Format{Delimiter: ';', DecimalSeparator: ','}, 2, "amount"},
+		{"debit,credit\n1,\nx,2\n", Format{Sign: DebitCredit}, 3, "debit"},
+		{"amount\n1\n\"2\n", Format{}, 3, ""},
+	}
+
+	for _, test := range tests {
+		_, err := Read(strings.NewReader(test.input), test.format)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) || !errors.Is(err, ErrInvalidRow) {
+			t.Errorf("Test failed for %q, expected a ParseError, got: %v", test.input, err)
+			continue
+		}
+		if parseErr.Line != test.line || parseErr.Column != test.column {
+			t.Errorf("Test failed for %q, expected line %d column '%s', got: %v", test.input, test.line, test.column, err)
+		}
+	}
+
+	formats := []struct {
+		input  string
+		format Format
+	}{
+		{"", Format{}},
+		{"date,value\n", Format{}},
+		{"date,amount\n", Format{Sign: DebitCredit}},
+		{"amount\n", Format{Delimiter: ';', DecimalSeparator: ';'}},
+		{"amount\n", Format{ThousandsSeparator: '.'}},
+	}
+	for _, test := range formats {
+		if _, err := Read(strings.NewReader(test.input), test.format); !errors.Is(err, ErrInvalidFormat) {
+			t.Errorf("Test failed for %+v, expected: '%v', got: '%v'", test.format, ErrInvalidFormat, err)
+		}
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	s := Series{Flows: []Flow{
+		{date(2021, 1, 1), -1000, "capex"},
+		{date(2021, 7, 1), 250.5, "rent"},
+		{date(2022, 1, 1), 0, ""},
+	}}
+	formats := []Format{
+		{},
+		{Delimiter: ';', DecimalSeparator: ',', DateLayout: "02/01/2006"},
+		{Sign: Inverted, Delimiter: '\t'},
+		{Sign: DebitCredit, Columns: Columns{Debit: "out", Credit: "in"}},
+	}
+
+	for _, format := range formats {
+		var buf bytes.Buffer
+		if err := s.Write(&buf, format); err != nil {
+			t.Fatal(err)
+		}
+		read, err := Read(&buf, format)
+		if err != nil {
+			t.Fatalf("Test failed for %+v: %v", format, err)
+		}
+		for i, flow := range read.Flows {
+			if flow != s.Flows[i] {
+				t.Errorf("Test failed for %+v, expected: %v, got: %v", format, s.Flows[i], flow)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	(Series{Flows: []Flow{{Amount: -1000}, {Amount: 0.5}}}).Write(&buf, Format{DecimalSeparator: ',', Delimiter: ';'})
+	if buf.String() != "amount\n-1000\n0,5\n" {
+		t.Errorf("Test failed, got: %q", buf.String())
+	}
+}
+
+func TestCalculations(t *testing.T) {
+	s, err := Read(strings.NewReader("date,amount,category\n2021-01-01,-1000,capex\n2022-01-01,300,rent\n2023-01-01,400,rent\n2024-01-01,500,rent\n"), Format{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	amounts := []float64{-1000, 300, 400, 500}
+
+	npv, err := s.NPV(0.1)
+	if err != nil || npv != gofin.NetPresentValue(0.1, 4, amounts) {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", gofin.NetPresentValue(0.1, 4, amounts), npv, err)
+	}
+	irr, err := s.IRR()
+	if expected := gofin.InternalRateOfReturn(1000, amounts[1:]); err != nil || math.Abs(irr-expected) > 1e-12 {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", expected, irr, err)
+	}
+	if period, err := s.Payback(); err != nil || period != 3 {
+		t.Errorf("Test failed, expected: '%d', got: '%d' (%v)", 3, period, err)
+	}
+	if period, err := s.DiscountedPayback(0.05); err != nil || period != 3 {
+		t.Errorf("Test failed, expected: '%d', got: '%d' (%v)", 3, period, err)
+	}
+	if _, err := s.DiscountedPayback(0.1); !errors.Is(err, gofin.ErrNoPayback) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", gofin.ErrNoPayback, err)
+	}
+
+	dated, _ := s.Dated()
+	xnpv, err := s.XNPV(0.1)
+	if expected, _ := gofin.XNPV(0.1, dated); err != nil || xnpv != expected {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", expected, xnpv, err)
+	}
+	xirr, err := s.XIRR()
+	if result, _ := gofin.XIRR(dated, gofin.XIRROptions{}); err != nil || xirr != result.Rate {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", result.Rate, xirr, err)
+	}
+
+	if _, err := (Series{Flows: []Flow{{Amount: -1}, {Amount: 2}}}).XIRR(); !errors.Is(err, ErrUndated) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrUndated, err)
+	}
+	if _, err := (Series{}).Payback(); !errors.Is(err, gofin.ErrEmptyInput) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", gofin.ErrEmptyInput, err)
+	}
+}
+
+func TestNetAndCategories(t *testing.T) {
+	s := Series{Flows: []Flow{
+		{date(2021, 2, 1), 300, "rent"},
+		{date(2021, 1, 1), -1000, "capex"},
+		{date(2021, 2, 1), -50, "repairs"},
+		{date(2021, 1, 1), -200, "fees"},
+	}}
+
+	net := s.Net()
+	expected := []Flow{{date(2021, 1, 1), -1200, ""}, {date(2021, 2, 1), 250, ""}}
+	if len(net.Flows) != 2 || net.Flows[0] != expected[0] || net.Flows[1] != expected[1] {
+		t.Errorf("Test failed, expected: %v, got: %v", expected, net.Flows)
+	}
+
+	if rent := s.Category("rent"); len(rent.Flows) != 1 || rent.Flows[0].Amount != 300 {
+		t.Errorf("Test failed, got: %v", rent.Flows)
+	}
+	if totals := s.Totals(); totals["capex"] != -1000 || totals["repairs"] != -50 || len(totals) != 4 {
+		t.Errorf("Test failed, got: %v", totals)
+	}
+}
@@ -0,0 +1,379 @@
+package cashflow
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignConvention says how the amounts of a CSV file map to the gofin sign
+// convention.
+type SignConvention int
+
+const (
+	// Signed amounts are negative for money paid out and positive for money
+	// received, as in gofin.
+	Signed SignConvention = iota
+
+	// Inverted amounts are positive for money paid out and negative for
+	// money received, as in many expense and accounting exports.
+	Inverted
+
+	// DebitCredit splits amounts into a debit column for money paid out and
+	// a credit column for money received, both positive.
+	DebitCredit
+)
+
+// Format describes the layout of a CSV file. The zero value reads and writes
+// comma-separated files with a header, a date column in YYYY-MM-DD layout
+// and signed amounts with a decimal point.
+type Format struct {
+	// Delimiter separates the fields. Defaults to ','.
+	Delimiter rune
+
+	// DecimalSeparator separates the integer and fractional parts of an
+	// amount. Defaults to '.'.
+	DecimalSeparator rune
+
+	// ThousandsSeparator groups the digits of an amount, for example ',' in
+	// "1,234.50". It is optional when reading and unused when 0.
+	ThousandsSeparator rune
+
+	// DateLayout is the layout of the dates, in Go time format. Defaults to
+	// "2006-01-02".
+	DateLayout string
+
+	Sign SignConvention
+
+	// Columns names the header columns. Missing names take the defaults
+	// "date", "amount", "category", "debit" and "credit". Header names are
+	// matched without regard to case. The date and category columns are
+	// optional; a file without dates is a series of flows one period apart.
+	Columns Columns
+}
+
+// Columns names the columns of a CSV file.
+type Columns struct {
+	Date     string
+	Amount   string
+	Category string
+	Debit    string
+	Credit   string
+}
+
+// ParseError reports the line and column of a row that cannot be read.
+type ParseError struct {
+	// Line is the line of the file, counting from 1.
+	Line int
+
+	// Column is the name of the column at fault, if any.
+	Column string
+
+	Err error
+}
+
+// Error returns the error with its position.
+func (e *ParseError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d, column %s: %v", e.Line, e.Column, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// withDefaults returns a copy of f with its zero fields replaced by the defaults.
+func (f Format) withDefaults() Format {
+	if f.Delimiter == 0 {
+		f.Delimiter = ','
+	}
+	if f.DecimalSeparator == 0 {
+		f.DecimalSeparator = '.'
+	}
+	if f.DateLayout == "" {
+		f.DateLayout = "2006-01-02"
+	}
+	defaults := []struct {
+		name     *string
+		fallback string
+	}{
+		{&f.Columns.Date, "date"},
+		{&f.Columns.Amount, "amount"},
+		{&f.Columns.Category, "category"},
+		{&f.Columns.Debit, "debit"},
+		{&f.Columns.Credit, "credit"},
+	}
+	for _, d := range defaults {
+		if *d.name == "" {
+			*d.name = d.fallback
+		}
+	}
+	return f
+}
+
+// check validates the separators and sign convention.
+func (f Format) check() error {
+	switch {
+	case f.Delimiter == f.DecimalSeparator:
+		return fmt.Errorf("%w: delimiter and decimal separator are both %q", ErrInvalidFormat, f.Delimiter)
+	case f.ThousandsSeparator == f.DecimalSeparator:
+		return fmt.Errorf("%w: thousands and decimal separators are both %q", ErrInvalidFormat, f.DecimalSeparator)
+	case f.Sign < Signed || f.Sign > DebitCredit:
+		return fmt.Errorf("%w: unknown sign convention %d", ErrInvalidFormat, f.Sign)
+	}
+	return nil
+}
+
+// columns holds the positions of the columns in a file, or -1 when absent.
+type columns struct {
+	date, amount, category, debit, credit int
+}
+
+// locate finds the columns of the format in a header.
+func (f Format) locate(header []string) (columns, error) {
+	find := func(name string) int {
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), name) {
+				return i
+			}
+		}
+		return -1
+	}
+	c := columns{
+		date:     find(f.Columns.Date),
+		amount:   find(f.Columns.Amount),
+		category: find(f.Columns.Category),
+		debit:    find(f.Columns.Debit),
+		credit:   find(f.Columns.Credit),
+	}
+
+	if f.Sign == DebitCredit {
+		if c.debit < 0 || c.credit < 0 {
+			return c, fmt.Errorf("%w: header needs %q and %q columns", ErrInvalidFormat, f.Columns.Debit, f.Columns.Credit)
+		}
+		c.amount = -1
+	} else {
+		if c.amount < 0 {
+			return c, fmt.Errorf("%w: header needs an %q column", ErrInvalidFormat, f.Columns.Amount)
+		}
+		c.debit, c.credit = -1, -1
+	}
+	return c, nil
+}
+
+// Read reads a series from a CSV file with a header line. It stops at the
+// first invalid row and returns a *ParseError with its line.
+func Read(r io.Reader, f Format) (Series, error) {
+	f = f.withDefaults()
+	if err := f.check(); err != nil {
+		return Series{}, err
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = f.Delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return Series{}, fmt.Errorf("%w: no header", ErrInvalidFormat)
+	}
+	if err != nil {
+		return Series{}, csvError(err)
+	}
+	c, err := f.locate(header)
+	if err != nil {
+		return Series{}, err
+	}
+
+	var s Series
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return s, nil
+		}
+		if err != nil {
+			return Series{}, csvError(err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		flow, parseErr := f.parse(record, c)
+		if parseErr != nil {
+			parseErr.Line = line
+			return Series{}, parseErr
+		}
+		s.Flows = append(s.Flows, flow)
+	}
+}
+
+// parse reads one row.
+func (f Format) parse(record []string, c columns) (Flow, *ParseError) {
+	field := func(i int) string {
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var flow Flow
+	if c.date >= 0 {
+		date, err := time.Parse(f.DateLayout, field(c.date))
+		if err != nil {
+			return flow, &ParseError{Column: f.Columns.Date, Err: fmt.Errorf("%w: invalid date %q", ErrInvalidRow, field(c.date))}
+		}
+		flow.Date = date
+	}
+	flow.Category = field(c.category)
+
+	switch f.Sign {
+	case DebitCredit:
+		debit, err := f.parseAmount(field(c.debit), true)
+		if err != nil {
+			return flow, &ParseError{Column: f.Columns.Debit, Err: err}
+		}
+		credit, err := f.parseAmount(field(c.credit), true)
+		if err != nil {
+			return flow, &ParseError{Column: f.Columns.Credit, Err: err}
+		}
+		flow.Amount = credit - debit
+	default:
+		amount, err := f.parseAmount(field(c.amount), false)
+		if err != nil {
+			return flow, &ParseError{Column: f.Columns.Amount, Err: err}
+		}
+		if f.Sign == Inverted {
+			amount = -amount
+		}
+		flow.Amount = amount
+	}
+	return flow, nil
+}
+
+// parseAmount parses a number in the format's separators. Parentheses mark a
+// negative number, as in spreadsheet exports. An empty field is zero when
+// optional and an error otherwise.
+func (f Format) parseAmount(s string, optional bool) (float64, error) {
+	if s == "" {
+		if optional {
+			return 0.0, nil
+		}
+		return 0.0, fmt.Errorf("%w: missing amount", ErrInvalidRow)
+	}
+
+	text := s
+	negative := false
+	if strings.HasPrefix(text, "(") && strings.HasSuffix(text, ")") {
+		text, negative = text[1:len(text)-1], true
+	}
+	if f.ThousandsSeparator != 0 {
+		text = strings.ReplaceAll(text, string(f.ThousandsSeparator), "")
+	}
+	if f.DecimalSeparator != '.' {
+		if strings.ContainsRune(text, '.') {
+			return 0.0, fmt.Errorf("%w: invalid number %q", ErrInvalidRow, s)
+		}
+		text = strings.ReplaceAll(text, string(f.DecimalSeparator), ".")
+	}
+
+	v, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0.0, fmt.Errorf("%w: invalid number %q", ErrInvalidRow, s)
+	}
+	if negative {
+		v = -v
+	}
+	return v, nil
+}
+
+// csvError converts an error of the CSV reader into a ParseError.
+func csvError(err error) error {
+	var csvErr *csv.ParseError
+	if errors.As(err, &csvErr) {
+		return &ParseError{Line: csvErr.Line, Err: fmt.Errorf("%w: %v", ErrInvalidRow, csvErr.Err)}
+	}
+	return err
+}
+
+// Write writes the series as a CSV file with a header line. The date column
+// is written when a flow has a date, and the category column when a flow has
+// a category. Amounts are written in their shortest exact form, without
+// thousands separators.
+func (s Series) Write(w io.Writer, f Format) error {
+	f = f.withDefaults()
+	if err := f.check(); err != nil {
+		return err
+	}
+
+	dated, categorized := false, false
+	for _, flow := range s.Flows {
+		dated = dated || !flow.Date.IsZero()
+		categorized = categorized || flow.Category != ""
+	}
+
+	var header []string
+	if dated {
+		header = append(header, f.Columns.Date)
+	}
+	if f.Sign == DebitCredit {
+		header = append(header, f.Columns.Debit, f.Columns.Credit)
+	} else {
+		header = append(header, f.Columns.Amount)
+	}
+	if categorized {
+		header = append(header, f.Columns.Category)
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = f.Delimiter
+	writer.Write(header)
+	for _, flow := range s.Flows {
+		var record []string
+		if dated {
+			date := ""
+			if !flow.Date.IsZero() {
+				date = flow.Date.Format(f.DateLayout)
+			}
+			record = append(record, date)
+		}
+		switch f.Sign {
+		case DebitCredit:
+			debit, credit := "", ""
+			if flow.Amount < 0 {
+				debit = f.formatAmount(-flow.Amount)
+			} else {
+				credit = f.formatAmount(flow.Amount)
+			}
+			record = append(record, debit, credit)
+		case Inverted:
+			record = append(record, f.formatAmount(-flow.Amount))
+		default:
+			record = append(record, f.formatAmount(flow.Amount))
+		}
+		if categorized {
+			record = append(record, flow.Category)
+		}
+		writer.Write(record)
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatAmount formats a number with the format's decimal separator.
+func (f Format) formatAmount(v float64) string {
+	if v == 0 {
+		v = 0 // no negative zero
+	}
+	text := strconv.FormatFloat(v, 'f', -1, 64)
+	if f.DecimalSeparator != '.' {
+		text = strings.Replace(text, ".", string(f.DecimalSeparator), 1)
+	}
+	return text
+}
@@ -0,0 +1,33 @@
+// Command gofin-server serves the gofin calculations as JSON over HTTP.
+//
+// Usage:
+//
+//	gofin-server [-addr :8080]
+//
+// The OpenAPI document of the endpoints is served at /openapi.json.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/lazarospsa/gofin/server"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "`address` to listen on")
+	flag.Parse()
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           server.New(),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       time.Minute,
+	}
+	log.Printf("gofin-server listening on %s", *addr)
+	log.Fatal(srv.ListenAndServe())
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/lazarospsa/gofin"
+	"github.com/lazarospsa/gofin/daycount"
+	"github.com/lazarospsa/gofin/depreciation"
+)
+
+// command is a gofin subcommand. setup defines the command's flags on fs and
+// returns the function that runs it once the flags are parsed.
+type command struct {
+	name    string
+	summary string
+
+	// values describes the series of values the command reads, or is empty
+	// when it takes none.
+	values string
+
+	setup func(fs *flag.FlagSet) func(in *input) (*table, error)
+}
+
+// commands lists the subcommands in the order shown by gofin help.
+var commands = []command{
+	{name: "fv", summary: "Future value of an investment with periodic payments", setup: setupFV},
+	{name: "pv", summary: "Present value of an investment with periodic payments", setup: setupPV},
+	{name: "pmt", summary: "Payment per period of a loan or investment", setup: setupPMT},
+	{name: "nper", summary: "Number of periods of a loan or investment", setup: setupNPER},
+	{name: "rate", summary: "Interest rate per period of a loan or investment", setup: setupRATE},
+	{name: "npv", summary: "Net present value of cash flows, the first one undiscounted", values: "[cash flows...]", setup: setupNPV},
+	{name: "irr", summary: "Internal rate of return of cash flows", values: "[cash flows...]", setup: setupIRR},
+	{name: "xirr", summary: "Internal rate of return of dated cash flows", values: "[date,amount...]", setup: setupXIRR},
+	{name: "payback", summary: "Payback period of an investment followed by its inflows", values: "[cash flows...]", setup: setupPayback},
+	{name: "amortize", summary: "Amortization schedule of a loan", setup: setupAmortize},
+	{name: "convert", summary: "Convert an annual rate between compounding frequencies", setup: setupConvert},
+	{name: "depreciate", summary: "Depreciation schedule of an asset", setup: setupDepreciate},
+	{name: "option", summary: "Black-Scholes-Merton price and Greeks of a European option", setup: setupOption},
+}
+
+// lookup returns the command with the given name.
+func lookup(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// timingFlag defines the -due flag and returns the payment timing it selects.
+func timingFlag(fs *flag.FlagSet) func() gofin.PaymentTiming {
+	due := fs.Bool("due", false, "payments are due at the beginning of each period")
+	return func() gofin.PaymentTiming {
+		if *due {
+			return gofin.BeginningOfPeriod
+		}
+		return gofin.EndOfPeriod
+	}
+}
+
+func setupFV(fs *flag.FlagSet) func(*input) (*table, error) {
+	rate := fs.Float64("rate", 0, "interest `rate` per period")
+	nper := fs.Float64("nper", 0, "number of periods")
+	pmt := fs.Float64("pmt", 0, "payment per period")
+	pv := fs.Float64("pv", 0, "present value")
+	timing := timingFlag(fs)
+	return func(*input) (*table, error) {
+		fv, err := gofin.FV(*rate, *nper, *pmt, *pv, timing())
+		return scalar("fv", fv), err
+	}
+}
+
+func setupPV(fs *flag.FlagSet) func(*input) (*table, error) {
+	rate := fs.Float64("rate", 0, "interest `rate` per period")
+	nper := fs.Float64("nper", 0, "number of periods")
+	pmt := fs.Float64("pmt", 0, "payment per period")
+	fv := fs.Float64("fv", 0, "future value")
+	timing := timingFlag(fs)
+	return func(*input) (*table, error) {
+		pv, err := gofin.PV(*rate, *nper, *pmt, *fv, timing())
+		return scalar("pv", pv), err
+	}
+}
+
+func setupPMT(fs *flag.FlagSet) func(*input) (*table, error) {
+	rate := fs.Float64("rate", 0, "interest `rate` per period")
+	nper := fs.Float64("nper", 0, "number of periods")
+	pv := fs.Float64("pv", 0, "present value")
+	fv := fs.Float64("fv", 0, "future value")
+	timing := timingFlag(fs)
+	return func(*input) (*table, error) {
+		pmt, err := gofin.PMT(*rate, *nper, *pv, *fv, timing())
+		return scalar("pmt", pmt), err
+	}
+}
+
+func setupNPER(fs *flag.FlagSet) func(*input) (*table, error) {
+	rate := fs.Float64("rate", 0, "interest `rate` per period")
+	pmt := fs.Float64("pmt", 0, "payment per period")
+	pv := fs.Float64("pv", 0, "present value")
+	fv := fs.Float64("fv", 0, "future value")
+	timing := timingFlag(fs)
+	return func(*input) (*table, error) {
+		nper, err := gofin.NPER(*rate, *pmt, *pv, *fv, timing())
+		return scalar("nper", nper), err
+	}
+}
+
+func setupRATE(fs *flag.FlagSet) func(*input) (*table, error) {
+	nper := fs.Float64("nper", 0, "number of periods")
+	pmt := fs.Float64("pmt", 0, "payment per period")
+	pv := fs.Float64("pv", 0, "present value")
+	fv := fs.Float64("fv", 0, "future value")
+	guess := fs.Float64("guess", 0.1, "starting `rate` of the search")
+	timing := timingFlag(fs)
+	return func(*input) (*table, error) {
+		rate, err := gofin.RATE(*nper, *pmt, *pv, *fv, timing(), *guess)
+		return scalar("rate", rate), err
+	}
+}
+
+func setupNPV(fs *flag.FlagSet) func(*input) (*table, error) {
+	rate := fs.Float64("rate", 0, "discount `rate` per period")
+	return func(in *input) (*table, error) {
+		flows, err := in.values()
+		if err != nil {
+			return nil, err
+		}
+		npv, err := gofin.NetPresentValueE(*rate, len(flows), flows)
+		return scalar("npv", npv), err
+	}
+}
+
+func setupIRR(fs *flag.FlagSet) func(*input) (*table, error) {
+	guess := fs.Float64("guess", 0.1, "starting `rate` of the search")
+	all := fs.Bool("all", false, "list every rate found, for cash flows that change sign more than once")
+	return func(in *input) (*table, error) {
+		flows, err := in.values()
+		if err != nil {
+			return nil, err
+		}
+		result, err := gofin.IRRSolver{Guess: *guess, AllRoots: *all}.Solve(flows)
+		if err != nil {
+			return nil, err
+		}
+		if *all {
+			return rootsTable(result.Roots), nil
+		}
+		return scalar("irr", result.Rate), nil
+	}
+}
+
+func setupXIRR(fs *flag.FlagSet) func(*input) (*table, error) {
+	guess := fs.Float64("guess", 0.1, "starting `rate` of the search")
+	layout := fs.String("layout", "2006-01-02", "date `layout`, in Go time format")
+	basis := fs.String("basis", "act365", "day count `basis`: act365, act360, actact or 30360")
+	all := fs.Bool("all", false, "list every rate found, for cash flows that change sign more than once")
+	return func(in *input) (*table, error) {
+		dayCount, err := parseBasis(*basis)
+		if err != nil {
+			return nil, err
+		}
+		flows, err := in.datedCashFlows(*layout)
+		if err != nil {
+			return nil, err
+		}
+		result, err := gofin.XIRR(flows, gofin.XIRROptions{Solver: gofin.IRRSolver{Guess: *guess, AllRoots: *all}, DayCount: dayCount})
+		if err != nil {
+			return nil, err
+		}
+		if *all {
+			return rootsTable(result.Roots), nil
+		}
+		return scalar("xirr", result.Rate), nil
+	}
+}
+
+func setupPayback(fs *flag.FlagSet) func(*input) (*table, error) {
+	rate := fs.Float64("rate", 0, "discount `rate` per period, for the discounted payback period")
+	return func(in *input) (*table, error) {
+		flows, err := in.values()
+		if err != nil {
+			return nil, err
+		}
+		period, err := gofin.DiscountedPaybackPeriodE(-flows[0], flows[1:], *rate)
+		return scalar("payback", period), err
+	}
+}
+
+func setupAmortize(fs *flag.FlagSet) func(*input) (*table, error) {
+	var spec gofin.LoanSpec
+	fs.Float64Var(&spec.Principal, "principal", 0, "amount borrowed")
+	fs.Float64Var(&spec.Rate, "rate", 0, "interest `rate` per period, for example 0.005 for 6% a year paid monthly")
+	fs.IntVar(&spec.Periods, "periods", 0, "term of the loan in periods")
+	fs.IntVar(&spec.InterestOnlyPeriods, "interest-only", 0, "number of leading interest-only `periods`")
+	fs.Float64Var(&spec.Balloon, "balloon", 0, "balance repaid with the last payment")
+	method := fs.String("method", "payment", "amortization `method`: payment for a fixed payment or principal for fixed principal")
+	return func(*input) (*table, error) {
+		switch *method {
+		case "payment":
+			spec.Method = gofin.FixedPayment
+		case "principal":
+			spec.Method = gofin.FixedPrincipal
+		default:
+			return nil, fmt.Errorf("unknown method %q", *method)
+		}
+
+		rows, err := gofin.Amortize(spec)
+		if err != nil {
+			return nil, err
+		}
+		t := &table{columns: []string{"period", "payment", "interest", "principal", "extra", "balance", "cumulative_interest"}}
+		for _, row := range rows {
+			t.rows = append(t.rows, []interface{}{row.Period, row.Payment, row.Interest, row.Principal, row.ExtraPrincipal, row.Balance, row.CumulativeInterest})
+		}
+		return t, nil
+	}
+}
+
+func setupConvert(fs *flag.FlagSet) func(*input) (*table, error) {
+	nominal := fs.Float64("rate", 0, "annual nominal `rate`")
+	from := fs.String("from", "annual", "compounding `frequency` of the rate: annual, semiannual, quarterly, monthly, weekly, daily or continuous")
+	to := fs.String("to", "annual", "compounding `frequency` to convert to")
+	return func(*input) (*table, error) {
+		fromCompounding, err := parseCompounding(*from)
+		if err != nil {
+			return nil, err
+		}
+		toCompounding, err := parseCompounding(*to)
+		if err != nil {
+			return nil, err
+		}
+
+		rate := gofin.Rate{Nominal: *nominal, Compounding: fromCompounding}
+		converted, err := rate.Convert(toCompounding)
+		if err != nil {
+			return nil, err
+		}
+		effective, err := rate.Effective()
+		return &table{columns: []string{"rate", "effective"}, rows: [][]interface{}{{converted.Nominal, effective}}}, err
+	}
+}
+
+func setupDepreciate(fs *flag.FlagSet) func(*input) (*table, error) {
+	var asset depreciation.Asset
+	fs.Float64Var(&asset.Cost, "cost", 0, "depreciable basis of the asset")
+	fs.Float64Var(&asset.Salvage, "salvage", 0, "salvage value at the end of the asset's life")
+	fs.IntVar(&asset.Life, "life", 0, "useful life in `years`")
+	fs.IntVar(&asset.FirstYearMonths, "months", 0, "`months` in service in the first year, or 0 for a full year")
+	method := fs.String("method", "sl", "`method`: sl, db, syd or macrs")
+	factor := fs.Float64("factor", 2, "declining balance factor, for example 1.5 for 150% declining balance")
+	class := fs.Int("class", 0, "MACRS property class in `years`")
+	quarter := fs.Int("quarter", 0, "quarter placed in service, 1 to 4, for the MACRS mid-quarter convention")
+	return func(*input) (*table, error) {
+		var rows []depreciation.Row
+		var err error
+		switch *method {
+		case "sl":
+			rows, err = depreciation.StraightLine(asset)
+		case "db":
+			rows, err = depreciation.DecliningBalance(asset, *factor)
+		case "syd":
+			rows, err = depreciation.SumOfYearsDigits(asset)
+		case "macrs":
+			property := depreciation.MACRS{Class: *class}
+			if *quarter != 0 {
+				property.Convention, property.Quarter = depreciation.MidQuarter, *quarter
+			}
+			rows, err = property.Schedule(asset.Cost)
+		default:
+			return nil, fmt.Errorf("unknown method %q", *method)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		t := &table{columns: []string{"period", "depreciation", "accumulated", "book_value"}}
+		for _, row := range rows {
+			t.rows = append(t.rows, []interface{}{row.Period, row.Depreciation, row.Accumulated, row.BookValue})
+		}
+		return t, nil
+	}
+}
+
+func setupOption(fs *flag.FlagSet) func(*input) (*table, error) {
+	var o gofin.EuropeanOption
+	kind := fs.String("type", "call", "option `type`: call or put")
+	fs.Float64Var(&o.Spot, "spot", 0, "price of the underlying")
+	fs.Float64Var(&o.Strike, "strike", 0, "strike price")
+	fs.Float64Var(&o.Expiry, "expiry", 0, "time to expiry in `years`")
+	fs.Float64Var(&o.Rate, "rate", 0, "continuously compounded risk-free `rate`")
+	fs.Float64Var(&o.DividendYield, "dividend", 0, "continuous dividend `yield`")
+	fs.Float64Var(&o.Volatility, "vol", 0, "annual `volatility`")
+	return func(*input) (*table, error) {
+		switch *kind {
+		case "call":
+			o.Type = gofin.Call
+		case "put":
+			o.Type = gofin.Put
+		default:
+			return nil, fmt.Errorf("unknown option type %q", *kind)
+		}
+
+		price, err := o.Price()
+		if err != nil {
+			return nil, err
+		}
+		g, err := o.Greeks()
+		if err != nil {
+			return nil, err
+		}
+		return &table{
+			columns: []string{"price", "delta", "gamma", "vega", "theta", "rho"},
+			rows:    [][]interface{}{{price, g.Delta, g.Gamma, g.Vega, g.Theta, g.Rho}},
+		}, nil
+	}
+}
+
+// rootsTable lists rates in a single irr column.
+func rootsTable(roots []float64) *table {
+	t := &table{columns: []string{"irr"}}
+	for _, root := range roots {
+		t.rows = append(t.rows, []interface{}{root})
+	}
+	return t
+}
+
+// compoundings maps the names accepted by -from and -to to frequencies.
+var compoundings = map[string]gofin.Compounding{
+	"annual":     gofin.CompoundAnnually,
+	"semiannual": gofin.CompoundSemiannually,
+	"quarterly":  gofin.CompoundQuarterly,
+	"monthly":    gofin.CompoundMonthly,
+	"weekly":     gofin.CompoundWeekly,
+	"daily":      gofin.CompoundDaily,
+	"continuous": gofin.CompoundContinuously,
+}
+
+// parseCompounding returns the compounding frequency with the given name.
+func parseCompounding(name string) (gofin.Compounding, error) {
+	c, ok := compoundings[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown compounding frequency %q", name)
+	}
+	return c, nil
+}
+
+// parseBasis returns the day count basis with the given name.
+func parseBasis(name string) (gofin.DayCounter, error) {
+	switch strings.ToLower(name) {
+	case "act365":
+		return gofin.Actual365Fixed{}, nil
+	case "act360":
+		return gofin.Actual360{}, nil
+	case "actact":
+		return daycount.ActualActualISDA{}, nil
+	case "30360":
+		return gofin.Thirty360{}, nil
+	default:
+		return nil, fmt.Errorf("unknown day count basis %q", name)
+	}
+}
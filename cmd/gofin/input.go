@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lazarospsa/gofin"
+)
+
+// input is where a command reads its series of values from: the arguments,
+// a CSV file or standard input, in that order of preference.
+type input struct {
+	args   []string
+	stdin  io.Reader
+	file   string
+	column int
+}
+
+// fromArgs reports whether the values come from the arguments.
+func (in *input) fromArgs() bool {
+	return in.file == "" && len(in.args) > 0
+}
+
+// position names row i of the input in error messages.
+func (in *input) position(i int) string {
+	if in.fromArgs() {
+		return fmt.Sprintf("argument %d", i+1)
+	}
+	return fmt.Sprintf("line %d", i+1)
+}
+
+// records returns the values as rows of fields. Each argument is a row.
+func (in *input) records() ([][]string, error) {
+	if in.fromArgs() {
+		records := make([][]string, len(in.args))
+		for i, arg := range in.args {
+			records[i] = splitFields(arg)
+		}
+		return in.selectColumn(records)
+	}
+
+	r := in.stdin
+	if in.file != "" {
+		f, err := os.Open(in.file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	for i, record := range records {
+		// Plain lists of values may be separated by spaces too.
+		if len(record) == 1 {
+			records[i] = splitFields(record[0])
+		}
+	}
+	return in.selectColumn(records)
+}
+
+// selectColumn keeps only the chosen column of each record, if any.
+func (in *input) selectColumn(records [][]string) ([][]string, error) {
+	if in.column == 0 {
+		return records, nil
+	}
+	if in.column < 0 {
+		return nil, fmt.Errorf("invalid column %d", in.column)
+	}
+	for i, record := range records {
+		if in.column > len(record) {
+			return nil, fmt.Errorf("%s has no column %d", in.position(i), in.column)
+		}
+		records[i] = record[in.column-1 : in.column]
+	}
+	return records, nil
+}
+
+// values returns every value of the input in order. A first line of a file
+// that is not numeric is taken as a header and skipped; arguments have no
+// header.
+func (in *input) values() ([]float64, error) {
+	records, err := in.records()
+	if err != nil {
+		return nil, err
+	}
+
+	var values []float64
+	for i, record := range records {
+		for _, field := range record {
+			if field == "" {
+				continue
+			}
+			v, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				if i == 0 && len(values) == 0 && !in.fromArgs() {
+					break
+				}
+				return nil, fmt.Errorf("%s: invalid number %q", in.position(i), field)
+			}
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return nil, errors.New("no values given")
+	}
+	return values, nil
+}
+
+// datedCashFlows returns the input as date, amount pairs, one per line, with
+// dates in the given layout. A first line of a file that is not a date is
+// taken as a header and skipped; arguments have no header.
+func (in *input) datedCashFlows(layout string) ([]gofin.DatedCashFlow, error) {
+	records, err := in.records()
+	if err != nil {
+		return nil, err
+	}
+
+	var flows []gofin.DatedCashFlow
+	for i, record := range records {
+		if len(record) == 0 {
+			continue
+		}
+		if len(record) != 2 {
+			return nil, fmt.Errorf("%s: expected a date and an amount, got %d fields", in.position(i), len(record))
+		}
+		date, err := time.Parse(layout, record[0])
+		if err != nil {
+			if i == 0 && !in.fromArgs() {
+				continue
+			}
+			return nil, fmt.Errorf("%s: invalid date %q", in.position(i), record[0])
+		}
+		amount, err := strconv.ParseFloat(record[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid number %q", in.position(i), record[1])
+		}
+		flows = append(flows, gofin.DatedCashFlow{Date: date, Amount: amount})
+	}
+	if len(flows) == 0 {
+		return nil, errors.New("no cash flows given")
+	}
+	return flows, nil
+}
+
+// splitFields splits s at commas and white space.
+func splitFields(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+}
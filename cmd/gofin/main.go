@@ -0,0 +1,105 @@
+// Command gofin is a command-line calculator for the gofin library.
+//
+// Usage:
+//
+//	gofin <command> [flags] [values]
+//
+// Commands that take a series of values, such as npv and irr, read them from
+// the arguments, from a CSV file given with -csv, or from standard input.
+// Values may be separated by commas, spaces or newlines. Put -- before the
+// values when the first one is negative, so it is not read as a flag:
+//
+//	gofin npv -rate 0.1 -- -1000 300 400 500
+//	gofin irr -format json < flows.csv
+//
+// Every command accepts -format text, json or csv. Run gofin help for the
+// list of commands and gofin <command> -h for a command's flags.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command line args and returns the exit status: 0 on
+// success, 1 when the calculation fails and 2 for a usage error.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+	if name := args[0]; name == "help" || name == "-h" || name == "-help" || name == "--help" {
+		usage(stdout)
+		return 0
+	}
+
+	cmd, ok := lookup(args[0])
+	if !ok {
+		fmt.Fprintf(stderr, "gofin: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("gofin "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "text", "output `format`: text, json or csv")
+	precision := fs.Int("precision", -1, "`digits` after the decimal point, or -1 for the shortest exact form")
+	in := &input{stdin: stdin}
+	if cmd.values != "" {
+		fs.StringVar(&in.file, "csv", "", "read the values from CSV `file` instead of the arguments or standard input")
+		fs.IntVar(&in.column, "column", 0, "read only CSV column `n`, counting from 1")
+	}
+	exec := cmd.setup(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: %s\n\n%s.\n\nFlags:\n", strings.TrimSpace("gofin "+cmd.name+" [flags] "+cmd.values), cmd.summary)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if cmd.values == "" && fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "gofin %s: unexpected arguments %q\n", cmd.name, fs.Args())
+		return 2
+	}
+	write, ok := writers[*format]
+	if !ok {
+		fmt.Fprintf(stderr, "gofin %s: unknown format %q\n", cmd.name, *format)
+		return 2
+	}
+	in.args = fs.Args()
+
+	result, err := exec(in)
+	if err != nil {
+		fmt.Fprintf(stderr, "gofin %s: %v\n", cmd.name, err)
+		return 1
+	}
+	if err := write(stdout, result, *precision); err != nil {
+		fmt.Fprintf(stderr, "gofin %s: %v\n", cmd.name, err)
+		return 1
+	}
+	return 0
+}
+
+// usage lists the commands.
+func usage(w io.Writer) {
+	fmt.Fprint(w, "usage: gofin <command> [flags] [values]\n\nCommands:\n")
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, cmd := range commands {
+		fmt.Fprintf(tw, "  %s\t%s\n", cmd.name, cmd.summary)
+	}
+	tw.Flush()
+	fmt.Fprint(w, "\nRun gofin <command> -h for the flags of a command.\n")
+}
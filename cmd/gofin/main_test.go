@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// execute runs the command line args with stdin and returns the exit status,
+// standard output and standard error.
+func execute(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	status := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return status, stdout.String(), stderr.String()
+}
+
+func TestScalarCommands(t *testing.T) {
+	tests := []struct {
+		args     []string
+		stdin    string
+		expected float64
+	}{
+		{[]string{"fv", "-rate", "0.05", "-nper", "10", "-pv", "-1000"}, "", 1628.894626777442},
+		{[]string{"pv", "-rate", "0.05", "-nper", "10", "-pmt", "-100", "-due"}, "", 810.7821675644},
+		{[]string{"pmt", "-rate", "0.005", "-nper", "360", "-pv", "200000"}, "", -1199.101050305},
+		{[]string{"nper", "-rate", "0.01", "-pmt", "-100", "-pv", "1000"}, "", 10.588644459},
+		{[]string{"rate", "-nper", "10", "-pmt", "-100", "-pv", "700"}, "", 0.0707282084},
+		{[]string{"npv", "-rate", "0.1", "--", "-1000", "300", "400", "500"}, "", -21.0368144252443},
+		{[]string{"npv", "-rate", "0.1"}, "-1000,300\n400 500\n", -21.0368144252443},
+		{[]string{"irr"}, "-100\n110\n", 0.1},
+		{[]string{"irr", "-column", "2"}, "year,flow\n0,-100\n1,110\n", 0.1},
+		{[]string{"xirr"}, "date,amount\n2021-01-01,-1000\n2022-01-01,1100\n", 0.1},
+		{[]string{"xirr", "-layout", "02/01/2006", "01/01/2021,-1000", "01/01/2022,1100"}, "", 0.1},
+		{[]string{"payback", "--", "-1000", "400", "400", "400"}, "", 3},
+		{[]string{"payback", "-rate", "0.1", "--", "-1000", "600", "600"}, "", 2},
+	}
+
+	for _, test := range tests {
+		status, stdout, stderr := execute(test.stdin, test.args...)
+		if status != 0 {
+			t.Errorf("Test failed for %v, exit status %d: %s", test.args, status, stderr)
+			continue
+		}
+		actual, err := strconv.ParseFloat(strings.TrimSpace(stdout), 64)
+		if err != nil || notWithin(actual, test.expected, 1e-6) {
+			t.Errorf("Test failed for %v, expected: '%f', got: '%s'", test.args, test.expected, stdout)
+		}
+	}
+}
+
+func TestCSVFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flows.csv")
+	if err := os.WriteFile(path, []byte("# project A\n-1000\n300\n400\n500\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	status, stdout, stderr := execute("", "npv", "-rate", "0.1", "-csv", path, "-precision", "2")
+	if status != 0 || stdout != "-21.04\n" {
+		t.Errorf("Test failed, expected: '-21.04', got: '%s' (%s)", stdout, stderr)
+	}
+}
+
+func TestTableFormats(t *testing.T) {
+	args := []string{"amortize", "-principal", "1000", "-rate", "0.01", "-periods", "3", "-precision", "2"}
+
+	_, stdout, _ := execute("", append(args, "-format", "csv")...)
+	expected := "period,payment,interest,principal,extra,balance,cumulative_interest\n" +
+		"1,340.02,10.00,330.02,0.00,669.98,10.00\n" +
+		"2,340.02,6.70,333.32,0.00,336.66,16.70\n" +
+		"3,340.02,3.37,336.66,0.00,0.00,20.07\n"
+	if stdout != expected {
+		t.Errorf("Test failed, expected:\n%s\ngot:\n%s", expected, stdout)
+	}
+
+	_, stdout, _ = execute("", append(args, "-format", "json")...)
+	var rows []map[string]float64
+	if err := json.Unmarshal([]byte(stdout), &rows); err != nil || len(rows) != 3 || rows[2]["balance"] != 0 || rows[0]["interest"] != 10 {
+		t.Errorf("Test failed, got: %s (%v)", stdout, err)
+	}
+
+	_, stdout, _ = execute("", args...)
+	if lines := strings.Split(strings.TrimSpace(stdout), "\n"); len(lines) != 4 || !strings.Contains(lines[0], "cumulative_interest") {
+		t.Errorf("Test failed, got:\n%s", stdout)
+	}
+}
+
+func TestRecordCommands(t *testing.T) {
+	status, stdout, stderr := execute("", "option", "-spot", "42", "-strike", "40", "-expiry", "0.5", "-rate", "0.1", "-vol", "0.2", "-format", "json")
+	var greeks map[string]float64
+	if err := json.Unmarshal([]byte(stdout), &greeks); status != 0 || err != nil {
+		t.Fatalf("Test failed, got: %s (%s)", stdout, stderr)
+	}
+	if notWithin(greeks["price"], 4.7594, 1e-4) || len(greeks) != 6 {
+		t.Errorf("Test failed, expected a price of 4.7594, got: %v", greeks)
+	}
+
+	_, stdout, _ = execute("", "convert", "-rate", "0.06", "-from", "monthly", "-to", "continuous", "-format", "json")
+	var rate map[string]float64
+	if err := json.Unmarshal([]byte(stdout), &rate); err != nil || notWithin(rate["rate"], 0.05985049875, 1e-9) || notWithin(rate["effective"], 0.0616778118645, 1e-9) {
+		t.Errorf("Test failed, got: %s", stdout)
+	}
+
+	_, stdout, _ = execute("", "depreciate", "-method", "macrs", "-class", "5", "-cost", "10000", "-format", "csv", "-precision", "0")
+	if lines := strings.Split(strings.TrimSpace(stdout), "\n"); len(lines) != 7 || lines[1] != "1,2000,2000,8000" {
+		t.Errorf("Test failed, got:\n%s", stdout)
+	}
+
+	_, stdout, _ = execute("", "irr", "-all", "-precision", "4", "-format", "csv", "--", "-1000", "3600", "-4310", "1716")
+	if stdout != "irr\n0.1000\n0.2000\n0.3000\n" {
+		t.Errorf("Test failed, expected the rates 0.1, 0.2 and 0.3, got:\n%s", stdout)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		args   []string
+		stdin  string
+		status int
+		stderr string
+	}{
+		{nil, "", 2, "usage"},
+		{[]string{"nope"}, "", 2, "unknown command"},
+		{[]string{"fv", "-bogus"}, "", 2, "not defined"},
+		{[]string{"fv", "-format", "xml"}, "", 2, "unknown format"},
+		{[]string{"fv", "extra"}, "", 2, "unexpected arguments"},
+		{[]string{"fv", "-rate", "-2"}, "", 1, "greater than -1"},
+		{[]string{"irr"}, "", 1, "no values"},
+		{[]string{"irr"}, "100\n200\n", 1, "both positive and negative"},
+		{[]string{"npv"}, "-100\nabc\n", 1, "line 2"},
+		{[]string{"npv", "-rate", "0.1", "--", "-1OOO", "300", "400"}, "", 1, "argument 1: invalid number"},
+		{[]string{"payback", "--", "x100", "50", "60"}, "", 1, "argument 1: invalid number"},
+		{[]string{"xirr", "--", "date,amount", "2021-01-01,-100", "2022-01-01,110"}, "", 1, "argument 1: invalid date"},
+		{[]string{"xirr"}, "2021-01-01,-100\n2021-13-01,110\n", 1, "invalid date"},
+		{[]string{"amortize", "-method", "other"}, "", 1, "unknown method"},
+	}
+
+	for _, test := range tests {
+		status, _, stderr := execute(test.stdin, test.args...)
+		if status != test.status || !strings.Contains(stderr, test.stderr) {
+			t.Errorf("Test failed for %v, expected status %d and '%s', got: %d and '%s'", test.args, test.status, test.stderr, status, stderr)
+		}
+	}
+
+	if status, stdout, _ := execute("", "help"); status != 0 || !strings.Contains(stdout, "amortize") {
+		t.Errorf("Test failed, got: %d and '%s'", status, stdout)
+	}
+}
+
+func notWithin(a, b, tolerance float64) bool {
+	return a-b > tolerance || b-a > tolerance
+}
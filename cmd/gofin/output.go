@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// table is the result of a command: named columns and rows of float64, int
+// or string cells. A table with a single row is written as one record.
+type table struct {
+	columns []string
+	rows    [][]interface{}
+}
+
+// scalar returns a one-cell table.
+func scalar(name string, value interface{}) *table {
+	return &table{columns: []string{name}, rows: [][]interface{}{{value}}}
+}
+
+// writers holds the writer of each output format.
+var writers = map[string]func(w io.Writer, t *table, precision int) error{
+	"text": writeText,
+	"json": writeJSON,
+	"csv":  writeCSV,
+}
+
+// writeText writes a single value on its own, a single record as name and
+// value lines, and other tables as aligned columns under a header.
+func writeText(w io.Writer, t *table, precision int) error {
+	if len(t.rows) == 1 && len(t.columns) == 1 {
+		_, err := fmt.Fprintln(w, format(t.rows[0][0], precision))
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+	if len(t.rows) == 1 {
+		for i, name := range t.columns {
+			fmt.Fprintf(tw, "%s\t%s\t\n", name, format(t.rows[0][i], precision))
+		}
+		return tw.Flush()
+	}
+
+	fmt.Fprintf(tw, "%s\t\n", strings.Join(t.columns, "\t"))
+	for _, row := range t.rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = format(cell, precision)
+		}
+		fmt.Fprintf(tw, "%s\t\n", strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// writeJSON writes a single record as an object and other tables as an
+// array of objects.
+func writeJSON(w io.Writer, t *table, precision int) error {
+	records := make([]map[string]json.RawMessage, len(t.rows))
+	for r, row := range t.rows {
+		records[r] = make(map[string]json.RawMessage, len(t.columns))
+		for i, name := range t.columns {
+			cell, err := jsonCell(row[i], precision)
+			if err != nil {
+				return err
+			}
+			records[r][name] = cell
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if len(records) == 1 {
+		return encoder.Encode(records[0])
+	}
+	return encoder.Encode(records)
+}
+
+// writeCSV writes a header line and one line per row.
+func writeCSV(w io.Writer, t *table, precision int) error {
+	cw := csv.NewWriter(w)
+	cw.Write(t.columns)
+	for _, row := range t.rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = format(cell, precision)
+		}
+		cw.Write(cells)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// jsonCell encodes a cell, writing numbers with the given precision. JSON has
+// no infinities or NaN, so those become null.
+func jsonCell(cell interface{}, precision int) (json.RawMessage, error) {
+	if v, ok := cell.(float64); ok {
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return json.RawMessage("null"), nil
+		}
+		return json.RawMessage(format(v, precision)), nil
+	}
+	return json.Marshal(cell)
+}
+
+// format formats a cell. Floats are rounded to precision digits after the
+// decimal point, or written in their shortest exact form for a negative
+// precision.
+func format(cell interface{}, precision int) string {
+	switch v := cell.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', precision, 64)
+	case int:
+		return strconv.Itoa(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,18 @@
+package gofin
+
+import "github.com/lazarospsa/gofin/daycount"
+
+// DayCounter converts the time between two dates into a fraction of a year
+// under a day-count basis. Any convention from the daycount package can be
+// used wherever gofin takes a DayCounter.
+type DayCounter = daycount.Convention
+
+// Actual365Fixed counts actual days over a 365-day year. It is the basis used
+// by spreadsheet XNPV and XIRR.
+type Actual365Fixed = daycount.Actual365Fixed
+
+// Actual360 counts actual days over a 360-day year, as used by money markets.
+type Actual360 = daycount.Actual360
+
+// Thirty360 is the US (NASD) 30/360 bond basis of spreadsheet basis 0.
+type Thirty360 = daycount.Thirty360US
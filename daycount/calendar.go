@@ -0,0 +1,215 @@
+package daycount
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// maxRollDays is the number of days searched for a business day before a
+// calendar is taken to have none.
+const maxRollDays = 366
+
+// ErrNoBusinessDay is returned when a calendar has no business day within a
+// year of a date, for example when its weekend rule covers every day.
+var ErrNoBusinessDay = errors.New("daycount: no business day found")
+
+// Calendar reports which dates are business days.
+type Calendar interface {
+	IsBusinessDay(t time.Time) bool
+}
+
+// WeekendRule lists the days of the week that are not business days.
+type WeekendRule []time.Weekday
+
+// Common weekend rules.
+var (
+	SaturdaySunday = WeekendRule{time.Saturday, time.Sunday}
+	FridaySaturday = WeekendRule{time.Friday, time.Saturday}
+	SundayOnly     = WeekendRule{time.Sunday}
+	NoWeekend      = WeekendRule{}
+)
+
+// IsWeekend reports whether t falls on a weekend day.
+func (w WeekendRule) IsWeekend(t time.Time) bool {
+	weekday := t.Weekday()
+	for _, day := range w {
+		if day == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// HolidayCalendar is a Calendar made of a weekend rule and a list of holidays.
+type HolidayCalendar struct {
+	Weekend  WeekendRule
+	holidays map[time.Time]string
+}
+
+// NewHolidayCalendar returns a calendar with the given weekend rule and holidays.
+func NewHolidayCalendar(weekend WeekendRule, holidays ...time.Time) *HolidayCalendar {
+	c := &HolidayCalendar{Weekend: weekend, holidays: make(map[time.Time]string)}
+	for _, holiday := range holidays {
+		c.AddHoliday(holiday, "")
+	}
+	return c
+}
+
+// AddHoliday marks the calendar date of t as a holiday.
+func (c *HolidayCalendar) AddHoliday(t time.Time, name string) {
+	if c.holidays == nil {
+		c.holidays = make(map[time.Time]string)
+	}
+	c.holidays[civil(t)] = name
+}
+
+// IsHoliday reports whether t is a holiday, and returns its name.
+func (c *HolidayCalendar) IsHoliday(t time.Time) (string, bool) {
+	name, ok := c.holidays[civil(t)]
+	return name, ok
+}
+
+// IsBusinessDay reports whether t is neither a weekend day nor a holiday.
+func (c *HolidayCalendar) IsBusinessDay(t time.Time) bool {
+	if c.Weekend.IsWeekend(t) {
+		return false
+	}
+	_, holiday := c.holidays[civil(t)]
+	return !holiday
+}
+
+// LoadHolidays reads holidays into a new calendar with the given weekend rule.
+// Each line holds a date in YYYY-MM-DD form, optionally followed by a comma
+// and the holiday's name. Blank lines and lines starting with # are ignored.
+func LoadHolidays(r io.Reader, weekend WeekendRule) (*HolidayCalendar, error) {
+	c := NewHolidayCalendar(weekend)
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		value, name, _ := strings.Cut(text, ",")
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("daycount: line %d: %w", line, err)
+		}
+		c.AddHoliday(date, strings.TrimSpace(name))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// LoadHolidayFile is like LoadHolidays but reads the holidays from the named file.
+func LoadHolidayFile(path string, weekend WeekendRule) (*HolidayCalendar, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return LoadHolidays(f, weekend)
+}
+
+// RollConvention says how a date that is not a business day is moved onto one.
+type RollConvention int
+
+const (
+	// Unadjusted leaves the date as it is.
+	Unadjusted RollConvention = iota
+
+	// Following moves to the next business day.
+	Following
+
+	// ModifiedFollowing moves to the next business day unless that falls in
+	// the next month, in which case it moves to the previous business day.
+	ModifiedFollowing
+
+	// Preceding moves to the previous business day.
+	Preceding
+
+	// ModifiedPreceding moves to the previous business day unless that falls
+	// in the previous month, in which case it moves to the next business day.
+	ModifiedPreceding
+)
+
+// Adjust moves t onto a business day of calendar according to convention. It
+// returns ErrNoBusinessDay when there is none within a year.
+func Adjust(calendar Calendar, t time.Time, convention RollConvention) (time.Time, error) {
+	switch convention {
+	case Following:
+		return roll(calendar, t, 1)
+	case ModifiedFollowing:
+		adjusted, err := roll(calendar, t, 1)
+		if err != nil || adjusted.Month() == t.Month() {
+			return adjusted, err
+		}
+		return roll(calendar, t, -1)
+	case Preceding:
+		return roll(calendar, t, -1)
+	case ModifiedPreceding:
+		adjusted, err := roll(calendar, t, -1)
+		if err != nil || adjusted.Month() == t.Month() {
+			return adjusted, err
+		}
+		return roll(calendar, t, 1)
+	default:
+		return t, nil
+	}
+}
+
+// AddBusinessDays moves t forward by n business days, or backward when n is
+// negative. A t that is not a business day is first rolled in the direction
+// of travel. It returns ErrNoBusinessDay when a business day is more than a
+// year away.
+func AddBusinessDays(calendar Calendar, t time.Time, n int) (time.Time, error) {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	t, err := roll(calendar, t, step)
+	for ; err == nil && n > 0; n-- {
+		t, err = roll(calendar, t.AddDate(0, 0, step), step)
+	}
+	return t, err
+}
+
+// BusinessDaysBetween counts the business days from start (inclusive) to end
+// (exclusive). It is negative when end is before start.
+func BusinessDaysBetween(calendar Calendar, start, end time.Time) int {
+	if end.Before(start) {
+		return -BusinessDaysBetween(calendar, end, start)
+	}
+	count := 0
+	for d, last := civil(start), civil(end); d.Before(last); d = d.AddDate(0, 0, 1) {
+		if calendar.IsBusinessDay(d) {
+			count++
+		}
+	}
+	return count
+}
+
+// roll steps from t one day at a time in direction step until it reaches a
+// business day, giving up after maxRollDays.
+func roll(calendar Calendar, t time.Time, step int) (time.Time, error) {
+	d := t
+	for i := 0; i <= maxRollDays; i++ {
+		if calendar.IsBusinessDay(d) {
+			return d, nil
+		}
+		d = d.AddDate(0, 0, step)
+	}
+	return time.Time{}, fmt.Errorf("%w within %d days of %s", ErrNoBusinessDay, maxRollDays, t.Format("2006-01-02"))
+}
@@ -0,0 +1,203 @@
+// Package daycount provides day-count conventions, which turn the time
+// between two dates into a fraction of a year, and business-day calendars
+// with the usual date roll conventions.
+package daycount
+
+import "time"
+
+// Convention converts the time between two dates into a fraction of a year.
+type Convention interface {
+	YearFraction(start, end time.Time) float64
+}
+
+// Actual360 counts actual days over a 360-day year, as used by money markets.
+type Actual360 struct{}
+
+// YearFraction returns the actual number of days between start and end divided by 360.
+func (Actual360) YearFraction(start, end time.Time) float64 {
+	return float64(Days(start, end)) / 360
+}
+
+// Actual365Fixed counts actual days over a 365-day year. It is the basis used
+// by spreadsheet XNPV and XIRR.
+type Actual365Fixed struct{}
+
+// YearFraction returns the actual number of days between start and end divided by 365.
+func (Actual365Fixed) YearFraction(start, end time.Time) float64 {
+	return float64(Days(start, end)) / 365
+}
+
+// ActualActualISDA splits the period by calendar year and divides the days
+// falling in leap years by 366 and the rest by 365.
+type ActualActualISDA struct{}
+
+// YearFraction returns the Actual/Actual ISDA year fraction between start and end.
+func (ActualActualISDA) YearFraction(start, end time.Time) float64 {
+	if end.Before(start) {
+		return -ActualActualISDA{}.YearFraction(end, start)
+	}
+
+	from, to := civil(start), civil(end)
+	fraction := 0.0
+	for from.Before(to) {
+		nextYear := time.Date(from.Year()+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+		if nextYear.After(to) {
+			nextYear = to
+		}
+		fraction += float64(Days(from, nextYear)) / float64(daysInYear(from.Year()))
+		from = nextYear
+	}
+	return fraction
+}
+
+// ActualActualICMA counts actual days over the actual days in each coupon
+// period times the coupon frequency, as used for government and Eurobond
+// accrued interest. The coupon periods run in steps of 12/Frequency months
+// from Anchor, normally the bond's maturity date; a zero Anchor uses the end
+// date of each calculation. A whole regular coupon period is 1/Frequency years.
+type ActualActualICMA struct {
+	Frequency int
+	Anchor    time.Time
+}
+
+// YearFraction returns the Actual/Actual ICMA year fraction between start and end.
+func (c ActualActualICMA) YearFraction(start, end time.Time) float64 {
+	if end.Before(start) {
+		return -c.YearFraction(end, start)
+	}
+	frequency := c.Frequency
+	if frequency <= 0 || 12%frequency != 0 {
+		frequency = 1
+	}
+	months := 12 / frequency
+	anchor := civil(c.Anchor)
+	if c.Anchor.IsZero() {
+		anchor = civil(end)
+	}
+	from, to := civil(start), civil(end)
+
+	// Find the reference period containing start.
+	k := 0
+	for periodStart(anchor, months, k).After(from) {
+		k++
+	}
+	for !periodStart(anchor, months, k-1).After(from) {
+		k--
+	}
+
+	fraction := 0.0
+	for ; from.Before(to); k-- {
+		pStart, pEnd := periodStart(anchor, months, k), periodStart(anchor, months, k-1)
+		segmentEnd := pEnd
+		if segmentEnd.After(to) {
+			segmentEnd = to
+		}
+		fraction += float64(Days(from, segmentEnd)) / (float64(frequency) * float64(Days(pStart, pEnd)))
+		from = segmentEnd
+	}
+	return fraction
+}
+
+// periodStart returns the date k coupon periods of months months before anchor.
+func periodStart(anchor time.Time, months, k int) time.Time {
+	return AddMonths(anchor, -months*k)
+}
+
+// Thirty360US is the US (NASD) 30/360 bond basis: every month has 30 days and
+// the year 360, with the end-of-February adjustments of spreadsheet basis 0.
+type Thirty360US struct{}
+
+// YearFraction returns the US 30/360 day count between start and end divided by 360.
+func (Thirty360US) YearFraction(start, end time.Time) float64 {
+	y1, m1, d1 := start.Date()
+	y2, m2, d2 := end.Date()
+
+	startIsFebEnd := m1 == time.February && d1 == daysInMonth(y1, m1)
+	endIsFebEnd := m2 == time.February && d2 == daysInMonth(y2, m2)
+	if startIsFebEnd && endIsFebEnd {
+		d2 = 30
+	}
+	if startIsFebEnd {
+		d1 = 30
+	}
+	if d2 == 31 && d1 >= 30 {
+		d2 = 30
+	}
+	if d1 == 31 {
+		d1 = 30
+	}
+
+	return float64(days360(y1, m1, d1, y2, m2, d2)) / 360
+}
+
+// Thirty360European is the 30E/360 (Eurobond) basis: a 31st at either end
+// counts as the 30th, with no special treatment of February.
+type Thirty360European struct{}
+
+// YearFraction returns the 30E/360 day count between start and end divided by 360.
+func (Thirty360European) YearFraction(start, end time.Time) float64 {
+	y1, m1, d1 := start.Date()
+	y2, m2, d2 := end.Date()
+	if d1 == 31 {
+		d1 = 30
+	}
+	if d2 == 31 {
+		d2 = 30
+	}
+
+	return float64(days360(y1, m1, d1, y2, m2, d2)) / 360
+}
+
+// Business252 counts business days over a 252-day year, as used in the
+// Brazilian market. A nil Calendar treats every weekday as a business day.
+type Business252 struct {
+	Calendar Calendar
+}
+
+// YearFraction returns the number of business days from start (inclusive) to
+// end (exclusive) divided by 252.
+func (c Business252) YearFraction(start, end time.Time) float64 {
+	calendar := c.Calendar
+	if calendar == nil {
+		calendar = NewHolidayCalendar(SaturdaySunday)
+	}
+	return float64(BusinessDaysBetween(calendar, start, end)) / 252
+}
+
+// Days returns the number of calendar days from start to end, ignoring the
+// time of day and any daylight saving shifts.
+func Days(start, end time.Time) int {
+	return int(civil(end).Sub(civil(start)).Hours() / 24)
+}
+
+// AddMonths adds months to t, clamping the day to the end of the target month
+// so that, for example, 31 August minus six months is 28 or 29 February.
+func AddMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	target := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	if last := daysInMonth(target.Year(), target.Month()); day > last {
+		day = last
+	}
+	return time.Date(target.Year(), target.Month(), day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+// civil returns the calendar date of t at midnight UTC.
+func civil(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// days360 returns the day count between two already-adjusted 30/360 dates.
+func days360(y1 int, m1 time.Month, d1 int, y2 int, m2 time.Month, d2 int) int {
+	return 360*(y2-y1) + 30*(int(m2)-int(m1)) + (d2 - d1)
+}
+
+// daysInMonth returns the number of days in month of year.
+func daysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// daysInYear returns 366 for leap years and 365 otherwise.
+func daysInYear(year int) int {
+	return Days(time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(year+1, time.January, 1, 0, 0, 0, 0, time.UTC))
+}
@@ -0,0 +1,113 @@
+package daycount
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestYearFraction(t *testing.T) {
+	tests := []struct {
+		name       string
+		convention Convention
+		start, end time.Time
+		expected   float64
+	}{
+		{"Actual/360", Actual360{}, date(2023, 1, 1), date(2023, 7, 1), 181.0 / 360},
+		{"Actual/365F", Actual365Fixed{}, date(2024, 1, 1), date(2025, 1, 1), 366.0 / 365},
+		{"Actual/Actual ISDA", ActualActualISDA{}, date(2003, 11, 1), date(2004, 5, 1), 61.0/365 + 121.0/366},
+		{"Actual/Actual ISDA whole leap year", ActualActualISDA{}, date(2024, 1, 1), date(2025, 1, 1), 1},
+		{"Actual/Actual ICMA regular period", ActualActualICMA{Frequency: 2}, date(2003, 11, 1), date(2004, 5, 1), 0.5},
+		{"Actual/Actual ICMA part period", ActualActualICMA{Frequency: 2, Anchor: date(2010, 5, 1)}, date(2003, 11, 1), date(2004, 2, 1), 92.0 / 364},
+		{"Actual/Actual ICMA across periods", ActualActualICMA{Frequency: 1, Anchor: date(2010, 3, 1)}, date(2003, 9, 1), date(2004, 6, 1), 182.0/366 + 92.0/365},
+		{"30/360 US", Thirty360US{}, date(2007, 1, 31), date(2007, 3, 31), 60.0 / 360},
+		{"30/360 US end of February", Thirty360US{}, date(2007, 2, 28), date(2007, 3, 31), 30.0 / 360},
+		{"30E/360", Thirty360European{}, date(2007, 1, 31), date(2007, 3, 31), 60.0 / 360},
+		{"30E/360 end of February", Thirty360European{}, date(2007, 2, 28), date(2007, 3, 31), 32.0 / 360},
+		{"Business/252", Business252{}, date(2023, 10, 2), date(2023, 10, 16), 10.0 / 252},
+		{"Business/252 with holiday", Business252{Calendar: NewHolidayCalendar(SaturdaySunday, date(2023, 10, 12))}, date(2023, 10, 2), date(2023, 10, 16), 9.0 / 252},
+	}
+
+	for _, test := range tests {
+		actual := test.convention.YearFraction(test.start, test.end)
+		if math.Abs(actual-test.expected) > 1e-12 {
+			t.Errorf("%s: Test failed, expected: '%.12f', got: '%.12f'", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestAdjust(t *testing.T) {
+	calendar := NewHolidayCalendar(SaturdaySunday)
+	tests := []struct {
+		date       time.Time
+		convention RollConvention
+		expected   time.Time
+	}{
+		{date(2023, 9, 30), Unadjusted, date(2023, 9, 30)},
+		{date(2023, 9, 30), Following, date(2023, 10, 2)},
+		{date(2023, 9, 30), ModifiedFollowing, date(2023, 9, 29)},
+		{date(2023, 7, 1), Preceding, date(2023, 6, 30)},
+		{date(2023, 7, 1), ModifiedPreceding, date(2023, 7, 3)},
+		{date(2023, 7, 5), Following, date(2023, 7, 5)},
+	}
+
+	for _, test := range tests {
+		actual, err := Adjust(calendar, test.date, test.convention)
+		if err != nil || !actual.Equal(test.expected) {
+			t.Errorf("Test failed, expected: '%s', got: '%s' (%v)", test.expected.Format("2006-01-02"), actual.Format("2006-01-02"), err)
+		}
+	}
+
+	// A calendar without business days cannot be rolled onto one.
+	never := NewHolidayCalendar(WeekendRule{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday})
+	for _, convention := range []RollConvention{Following, ModifiedFollowing, Preceding, ModifiedPreceding} {
+		if _, err := Adjust(never, date(2023, 9, 30), convention); !errors.Is(err, ErrNoBusinessDay) {
+			t.Errorf("Test failed for convention %d, expected: '%v', got: '%v'", convention, ErrNoBusinessDay, err)
+		}
+	}
+	if _, err := AddBusinessDays(never, date(2023, 9, 30), 2); !errors.Is(err, ErrNoBusinessDay) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrNoBusinessDay, err)
+	}
+}
+
+func TestLoadHolidays(t *testing.T) {
+	calendar, err := LoadHolidayFile("testdata/holidays.txt", SaturdaySunday)
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+
+	if name, ok := calendar.IsHoliday(date(2023, 7, 4)); !ok || name != "Independence Day" {
+		t.Errorf("Test failed, expected: '%s', got: '%s'", "Independence Day", name)
+	}
+	if calendar.IsBusinessDay(date(2023, 12, 25)) || !calendar.IsBusinessDay(date(2023, 12, 26)) {
+		t.Errorf("Test failed, unexpected business days around Christmas")
+	}
+	if actual, _ := Adjust(calendar, date(2023, 7, 4), Following); !actual.Equal(date(2023, 7, 5)) {
+		t.Errorf("Test failed, expected: '%s', got: '%s'", "2023-07-05", actual.Format("2006-01-02"))
+	}
+
+	_, err = LoadHolidays(strings.NewReader("2023-01-02\n2023-13-01\n"), SaturdaySunday)
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Test failed, expected an error on line 2, got: '%v'", err)
+	}
+}
+
+func TestBusinessDays(t *testing.T) {
+	calendar := NewHolidayCalendar(FridaySaturday, date(2023, 10, 10))
+
+	if actual := BusinessDaysBetween(calendar, date(2023, 10, 8), date(2023, 10, 15)); actual != 4 {
+		t.Errorf("Test failed, expected: '%d', got: '%d'", 4, actual)
+	}
+	if actual, _ := AddBusinessDays(calendar, date(2023, 10, 8), 2); !actual.Equal(date(2023, 10, 11)) {
+		t.Errorf("Test failed, expected: '%s', got: '%s'", "2023-10-11", actual.Format("2006-01-02"))
+	}
+	if actual, _ := AddBusinessDays(calendar, date(2023, 10, 15), -2); !actual.Equal(date(2023, 10, 11)) {
+		t.Errorf("Test failed, expected: '%s', got: '%s'", "2023-10-11", actual.Format("2006-01-02"))
+	}
+}
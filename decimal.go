@@ -0,0 +1,296 @@
+package gofin
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RoundingMode selects how a Decimal is rounded to a smaller scale.
+type RoundingMode int
+
+const (
+	// RoundHalfEven rounds to the nearest value and ties to the even
+	// neighbour (banker's rounding): 2.345 becomes 2.34 and 2.355 becomes 2.36.
+	RoundHalfEven RoundingMode = iota
+
+	// RoundHalfUp rounds to the nearest value and ties away from zero:
+	// 2.345 becomes 2.35 and -2.345 becomes -2.35.
+	RoundHalfUp
+
+	// RoundDown truncates towards zero: 2.349 becomes 2.34.
+	RoundDown
+)
+
+// Decimal is an exact base-10 number: an arbitrary-precision integer
+// coefficient scaled by a power of ten. Unlike float64 it represents values
+// such as 0.10 exactly, so sums of money amounts do not drift.
+//
+// The zero value is 0. Decimals are immutable; every operation returns a new value.
+type Decimal struct {
+	coef  *big.Int
+	scale int32
+}
+
+// DecimalContext says how a Decimal calculation rounds its result: to Scale
+// digits after the decimal point using Rounding.
+type DecimalContext struct {
+	Scale    int32
+	Rounding RoundingMode
+}
+
+// NewDecimal returns unscaled * 10^-scale, so NewDecimal(12345, 2) is 123.45.
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	return Decimal{coef: big.NewInt(unscaled), scale: scale}
+}
+
+// ParseDecimal parses a plain decimal string such as "-1234.50". The scale of
+// the result is the number of digits after the decimal point.
+func ParseDecimal(s string) (Decimal, error) {
+	text := strings.TrimSpace(s)
+	sign := ""
+	if strings.HasPrefix(text, "-") || strings.HasPrefix(text, "+") {
+		sign, text = text[:1], text[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(text, ".")
+	digits := whole + fraction
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+	}
+
+	coef, _ := new(big.Int).SetString(sign+digits, 10)
+	return Decimal{coef: coef, scale: int32(len(fraction))}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if s cannot be parsed.
+// It is meant for constants in code and tests.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// DecimalFromFloat returns the shortest Decimal that converts back to f.
+func DecimalFromFloat(f float64) (Decimal, error) {
+	return ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// Scale returns the number of digits after the decimal point.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Sign returns -1, 0 or 1 depending on the sign of d.
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// IsZero reports whether d is zero.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Cmp compares d and e and returns -1, 0 or 1.
+func (d Decimal) Cmp(e Decimal) int {
+	a, b := align(d, e)
+	return a.Cmp(b)
+}
+
+// Add returns d + e at the larger of the two scales.
+func (d Decimal) Add(e Decimal) Decimal {
+	a, b := align(d, e)
+	return Decimal{coef: a.Add(a, b), scale: maxScale(d, e)}
+}
+
+// Sub returns d - e at the larger of the two scales.
+func (d Decimal) Sub(e Decimal) Decimal {
+	a, b := align(d, e)
+	return Decimal{coef: a.Sub(a, b), scale: maxScale(d, e)}
+}
+
+// Mul returns d * e exactly; the scale of the result is the sum of the scales.
+func (d Decimal) Mul(e Decimal) Decimal {
+	return Decimal{coef: new(big.Int).Mul(d.int(), e.int()), scale: d.scale + e.scale}
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+// Quo returns d / e rounded to ctx. It returns ErrZeroValue when e is zero.
+func (d Decimal) Quo(e Decimal, ctx DecimalContext) (Decimal, error) {
+	if e.IsZero() {
+		return Decimal{}, ErrZeroValue
+	}
+	return roundRat(new(big.Rat).Quo(d.rat(), e.rat()), ctx), nil
+}
+
+// Round returns d rounded to ctx.
+func (d Decimal) Round(ctx DecimalContext) Decimal {
+	return roundRat(d.rat(), ctx)
+}
+
+// Float64 returns the nearest float64 to d.
+func (d Decimal) Float64() float64 {
+	f, _ := d.rat().Float64()
+	return f
+}
+
+// String formats d with exactly Scale digits after the decimal point.
+func (d Decimal) String() string {
+	if d.scale <= 0 {
+		return new(big.Int).Mul(d.int(), pow10(-d.scale)).String()
+	}
+
+	digits := new(big.Int).Abs(d.int()).String()
+	if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	point := len(digits) - int(d.scale)
+
+	sign := ""
+	if d.Sign() < 0 {
+		sign = "-"
+	}
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// int returns the coefficient, treating the zero value as 0.
+func (d Decimal) int() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// rat returns d as an exact rational number.
+func (d Decimal) rat() *big.Rat {
+	if d.scale >= 0 {
+		return new(big.Rat).SetFrac(d.int(), pow10(d.scale))
+	}
+	return new(big.Rat).SetInt(new(big.Int).Mul(d.int(), pow10(-d.scale)))
+}
+
+// align returns the coefficients of d and e rescaled to their common scale.
+func align(d, e Decimal) (*big.Int, *big.Int) {
+	scale := maxScale(d, e)
+	a := new(big.Int).Mul(d.int(), pow10(scale-d.scale))
+	b := new(big.Int).Mul(e.int(), pow10(scale-e.scale))
+	return a, b
+}
+
+// maxScale returns the larger scale of d and e.
+func maxScale(d, e Decimal) int32 {
+	if d.scale > e.scale {
+		return d.scale
+	}
+	return e.scale
+}
+
+// roundRat rounds the exact value r to ctx.
+func roundRat(r *big.Rat, ctx DecimalContext) Decimal {
+	num := new(big.Int).Set(r.Num())
+	den := new(big.Int).Set(r.Denom())
+	if ctx.Scale >= 0 {
+		num.Mul(num, pow10(ctx.Scale))
+	} else {
+		den.Mul(den, pow10(-ctx.Scale))
+	}
+
+	q, m := new(big.Int).QuoRem(num, den, new(big.Int))
+	if m.Sign() != 0 && ctx.Rounding != RoundDown {
+		half := new(big.Int).Mul(m.Abs(m), big.NewInt(2)).Cmp(den)
+		odd := new(big.Int).Abs(q).Bit(0) == 1
+		if half > 0 || (half == 0 && (ctx.Rounding == RoundHalfUp || odd)) {
+			// Round away from zero.
+			if num.Sign() < 0 {
+				q.Sub(q, big.NewInt(1))
+			} else {
+				q.Add(q, big.NewInt(1))
+			}
+		}
+	}
+
+	return Decimal{coef: q, scale: ctx.Scale}
+}
+
+// pow10 returns 10^n for n >= 0.
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// FutureValueDecimal is like FutureValue but computes exactly in decimal and
+// rounds only the final result to ctx.
+// FV = PV * (1 + r)^n
+func FutureValueDecimal(presentValue, interestRate Decimal, periods int, ctx DecimalContext) (Decimal, error) {
+	growth, err := decimalGrowth(interestRate, periods)
+	if err != nil {
+		return Decimal{}, err
+	}
+	return roundRat(growth.Mul(growth, presentValue.rat()), ctx), nil
+}
+
+// PresentValueDecimal is like PresentValue but computes exactly in decimal and
+// rounds only the final result to ctx.
+// PV = FV / (1 + r)^n
+func PresentValueDecimal(futureValue, interestRate Decimal, periods int, ctx DecimalContext) (Decimal, error) {
+	growth, err := decimalGrowth(interestRate, periods)
+	if err != nil {
+		return Decimal{}, err
+	}
+	return roundRat(growth.Quo(futureValue.rat(), growth), ctx), nil
+}
+
+// NetPresentValueDecimal is like NetPresentValue but computes exactly in
+// decimal and rounds only the final sum to ctx. cashFlows[0] is undiscounted.
+// NPV = sum(C / (1 + r)^t)
+func NetPresentValueDecimal(interestRate Decimal, cashFlows []Decimal, ctx DecimalContext) (Decimal, error) {
+	if _, err := decimalGrowth(interestRate, 0); err != nil {
+		return Decimal{}, err
+	}
+
+	one := big.NewRat(1, 1)
+	discount := new(big.Rat).Quo(one, new(big.Rat).Add(one, interestRate.rat()))
+	factor := big.NewRat(1, 1)
+	npv := new(big.Rat)
+	for _, cashFlow := range cashFlows {
+		npv.Add(npv, new(big.Rat).Mul(cashFlow.rat(), factor))
+		factor.Mul(factor, discount)
+	}
+	return roundRat(npv, ctx), nil
+}
+
+// PaybackPeriodDecimal is like PaybackPeriodE but accumulates the cash flows exactly.
+func PaybackPeriodDecimal(initialInvestment Decimal, cashInflows []Decimal) (int, error) {
+	cumulativeCashFlow := initialInvestment.Neg()
+	for i, cashInflow := range cashInflows {
+		cumulativeCashFlow = cumulativeCashFlow.Add(cashInflow)
+
+		if cumulativeCashFlow.Sign() >= 0 {
+			return i + 1, nil
+		}
+	}
+
+	return -1, ErrNoPayback
+}
+
+// decimalGrowth returns (1 + r)^n exactly.
+func decimalGrowth(interestRate Decimal, periods int) (*big.Rat, error) {
+	if interestRate.Cmp(NewDecimal(-1, 0)) <= 0 {
+		return nil, ErrInvalidRate
+	}
+	if periods < 0 {
+		return nil, ErrInvalidPeriods
+	}
+
+	base := new(big.Rat).Add(big.NewRat(1, 1), interestRate.rat())
+	n := big.NewInt(int64(periods))
+	num := new(big.Int).Exp(base.Num(), n, nil)
+	den := new(big.Int).Exp(base.Denom(), n, nil)
+	return new(big.Rat).SetFrac(num, den), nil
+}
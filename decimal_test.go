@@ -0,0 +1,127 @@
+package gofin
+
+import (
+	"errors"
+	"testing"
+)
+
+var cents = DecimalContext{Scale: 2, Rounding: RoundHalfEven}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input, expected string
+	}{
+		{"1234.50", "1234.50"},
+		{"-0.05", "-0.05"},
+		{"+7", "7"},
+		{".5", "0.5"},
+		{"000.100", "0.100"},
+	}
+	for _, test := range tests {
+		actual, err := ParseDecimal(test.input)
+		if err != nil || actual.String() != test.expected {
+			t.Errorf("Test failed, expected: '%s', got: '%s' (%v)", test.expected, actual, err)
+		}
+	}
+
+	for _, input := range []string{"", "-", "1.2.3", "1e5", "12a"} {
+		if _, err := ParseDecimal(input); !errors.Is(err, ErrInvalidDecimal) {
+			t.Errorf("Test failed, expected: '%v' for %q, got: '%v'", ErrInvalidDecimal, input, err)
+		}
+	}
+}
+
+func TestDecimalRound(t *testing.T) {
+	tests := []struct {
+		input    string
+		mode     RoundingMode
+		expected string
+	}{
+		{"2.345", RoundHalfEven, "2.34"},
+		{"2.355", RoundHalfEven, "2.36"},
+		{"2.345", RoundHalfUp, "2.35"},
+		{"-2.345", RoundHalfUp, "-2.35"},
+		{"-2.345", RoundHalfEven, "-2.34"},
+		{"2.3451", RoundHalfEven, "2.35"},
+		{"2.349", RoundDown, "2.34"},
+		{"-2.349", RoundDown, "-2.34"},
+	}
+	for _, test := range tests {
+		actual := MustParseDecimal(test.input).Round(DecimalContext{Scale: 2, Rounding: test.mode})
+		if actual.String() != test.expected {
+			t.Errorf("Test failed, expected: '%s', got: '%s'", test.expected, actual)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	// Ten dimes make exactly one dollar, unlike float64.
+	total := Decimal{}
+	for i := 0; i < 10; i++ {
+		total = total.Add(MustParseDecimal("0.10"))
+	}
+	if total.Cmp(NewDecimal(1, 0)) != 0 {
+		t.Errorf("Test failed, expected: '%s', got: '%s'", "1.00", total)
+	}
+
+	if actual := MustParseDecimal("1.5").Mul(MustParseDecimal("-0.25")); actual.String() != "-0.375" {
+		t.Errorf("Test failed, expected: '%s', got: '%s'", "-0.375", actual)
+	}
+	if actual, _ := NewDecimal(100, 0).Quo(NewDecimal(3, 0), cents); actual.String() != "33.33" {
+		t.Errorf("Test failed, expected: '%s', got: '%s'", "33.33", actual)
+	}
+	if _, err := NewDecimal(1, 0).Quo(Decimal{}, cents); !errors.Is(err, ErrZeroValue) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrZeroValue, err)
+	}
+}
+
+func TestFutureValueDecimal(t *testing.T) {
+	// 1000 * 1.05^10 = 1628.89462677744140625 exactly.
+	actual, err := FutureValueDecimal(NewDecimal(1000, 0), MustParseDecimal("0.05"), 10, DecimalContext{Scale: 15})
+	if err != nil || actual.String() != "1628.894626777441406" {
+		t.Errorf("Test failed, expected: '%s', got: '%s' (%v)", "1628.894626777441406", actual, err)
+	}
+
+	actual, _ = FutureValueDecimal(NewDecimal(1000, 0), MustParseDecimal("0.05"), 10, cents)
+	if actual.String() != "1628.89" {
+		t.Errorf("Test failed, expected: '%s', got: '%s'", "1628.89", actual)
+	}
+}
+
+func TestPresentValueDecimal(t *testing.T) {
+	actual, err := PresentValueDecimal(NewDecimal(121, 0), MustParseDecimal("0.1"), 2, cents)
+	if err != nil || actual.String() != "100.00" {
+		t.Errorf("Test failed, expected: '%s', got: '%s' (%v)", "100.00", actual, err)
+	}
+
+	if _, err := PresentValueDecimal(NewDecimal(121, 0), MustParseDecimal("-1"), 2, cents); !errors.Is(err, ErrInvalidRate) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidRate, err)
+	}
+}
+
+func TestNetPresentValueDecimal(t *testing.T) {
+	cashFlows := []Decimal{NewDecimal(-1000, 0), NewDecimal(1100, 0)}
+	actual, err := NetPresentValueDecimal(MustParseDecimal("0.10"), cashFlows, DecimalContext{Scale: 20})
+	if err != nil || !actual.IsZero() {
+		t.Errorf("Test failed, expected: '%s', got: '%s' (%v)", "0", actual, err)
+	}
+
+	cashFlows = []Decimal{MustParseDecimal("-100.00"), MustParseDecimal("60.00"), MustParseDecimal("60.00")}
+	actual, _ = NetPresentValueDecimal(MustParseDecimal("0.10"), cashFlows, cents)
+	if actual.String() != "4.13" {
+		t.Errorf("Test failed, expected: '%s', got: '%s'", "4.13", actual)
+	}
+}
+
+func TestPaybackPeriodDecimal(t *testing.T) {
+	inflows := []Decimal{MustParseDecimal("0.10"), MustParseDecimal("0.10"), MustParseDecimal("0.10")}
+
+	// In float64, 0.1 + 0.1 + 0.1 < 0.3, so the payback would be missed.
+	actual, err := PaybackPeriodDecimal(MustParseDecimal("0.30"), inflows)
+	if err != nil || actual != 3 {
+		t.Errorf("Test failed, expected: '%d', got: '%d' (%v)", 3, actual, err)
+	}
+	if _, err := PaybackPeriodDecimal(MustParseDecimal("0.31"), inflows); !errors.Is(err, ErrNoPayback) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrNoPayback, err)
+	}
+}
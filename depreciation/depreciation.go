@@ -0,0 +1,209 @@
+// Package depreciation builds per-period depreciation schedules for fixed
+// assets: straight-line, declining balance, sum-of-years'-digits,
+// units-of-production and the US MACRS percentage tables.
+package depreciation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Errors returned by the schedule functions.
+var (
+	// ErrInvalidAsset is returned for a non-positive cost or life, a salvage
+	// value outside [0, cost] or a first year of more than 12 months.
+	ErrInvalidAsset = errors.New("depreciation: invalid asset")
+
+	// ErrInvalidMACRS is returned for an unknown MACRS property class,
+	// convention or quarter.
+	ErrInvalidMACRS = errors.New("depreciation: invalid MACRS property")
+)
+
+// Asset describes a depreciable asset.
+type Asset struct {
+	// Cost is the depreciable basis of the asset.
+	Cost float64
+
+	// Salvage is the value the asset is expected to have at the end of its life.
+	Salvage float64
+
+	// Life is the useful life in years.
+	Life int
+
+	// FirstYearMonths is the number of months the asset is in service in the
+	// first year, from 1 to 12. Zero means a full year. A partial first year
+	// moves the rest of the last year's depreciation into an extra year.
+	FirstYearMonths int
+}
+
+// Row is one period of a depreciation schedule.
+type Row struct {
+	Period       int
+	Depreciation float64
+	Accumulated  float64
+	BookValue    float64
+}
+
+// StraightLine depreciates the asset by the same amount each full year.
+// D = (C - S) / L
+// C is the cost,
+// S is the salvage value,
+// L is the life in years.
+func StraightLine(a Asset) ([]Row, error) {
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+	annual := (a.Cost - a.Salvage) / float64(a.Life)
+	first := a.firstYearFraction()
+
+	amounts := make([]float64, 0, a.Life+1)
+	amounts = append(amounts, annual*first)
+	for year := 2; year <= a.Life; year++ {
+		amounts = append(amounts, annual)
+	}
+	if first < 1 {
+		amounts = append(amounts, annual*(1-first))
+	}
+	return schedule(a.Cost, a.Salvage, amounts), nil
+}
+
+// DecliningBalance depreciates the book value at factor / Life a year, for
+// example factor 2 for double declining balance or 1.5 for 150% declining
+// balance. Each year it switches to straight-line over the remaining life when
+// that gives a larger charge, and it never depreciates below the salvage value.
+func DecliningBalance(a Asset, factor float64) ([]Row, error) {
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+	if factor <= 0 {
+		return nil, fmt.Errorf("%w: declining balance factor must be positive", ErrInvalidAsset)
+	}
+	amounts := decliningBalance(a.Cost, a.Salvage, float64(a.Life), factor/float64(a.Life), a.firstYearFraction(), exact)
+	return schedule(a.Cost, a.Salvage, amounts), nil
+}
+
+// SumOfYearsDigits depreciates the asset by a falling fraction of its
+// depreciable base: (L - k + 1) / (L * (L + 1) / 2) in year k of its life.
+// With a partial first year each period takes the matching parts of two
+// years of the asset's life.
+func SumOfYearsDigits(a Asset) ([]Row, error) {
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+	base := a.Cost - a.Salvage
+	digits := float64(a.Life*(a.Life+1)) / 2
+	yearly := func(k int) float64 {
+		if k < 1 || k > a.Life {
+			return 0
+		}
+		return base * float64(a.Life-k+1) / digits
+	}
+
+	first := a.firstYearFraction()
+	periods := a.Life
+	if first < 1 {
+		periods++
+	}
+	amounts := make([]float64, periods)
+	for p := 1; p <= periods; p++ {
+		amounts[p-1] = (1-first)*yearly(p-1) + first*yearly(p)
+	}
+	return schedule(a.Cost, a.Salvage, amounts), nil
+}
+
+// UnitsOfProduction depreciates the asset in proportion to its use: each
+// period's units over the totalUnits it is expected to produce. The asset's
+// Life and FirstYearMonths are not used.
+func UnitsOfProduction(a Asset, totalUnits float64, units []float64) ([]Row, error) {
+	a.Life = 1
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+	if totalUnits <= 0 {
+		return nil, fmt.Errorf("%w: total units must be positive", ErrInvalidAsset)
+	}
+
+	base := a.Cost - a.Salvage
+	amounts := make([]float64, len(units))
+	for i, u := range units {
+		if u < 0 {
+			return nil, fmt.Errorf("%w: units in period %d are negative", ErrInvalidAsset, i+1)
+		}
+		amounts[i] = base * u / totalUnits
+	}
+	return schedule(a.Cost, a.Salvage, amounts), nil
+}
+
+// decliningBalance returns the yearly charges of a declining balance method
+// at rate that switches to straight-line, for a life in years whose first
+// year is the fraction first of a full year. Each charge is passed through
+// round before it is deducted from the book value.
+func decliningBalance(cost, salvage, life, rate, first float64, round func(float64) float64) []float64 {
+	var amounts []float64
+	book, elapsed := cost, 0.0
+	for {
+		fraction := 1.0
+		if len(amounts) == 0 {
+			fraction = first
+		}
+
+		// The last year takes whatever is left above salvage.
+		remaining := life - elapsed
+		if remaining <= fraction+1e-9 {
+			return append(amounts, book-salvage)
+		}
+
+		declining := book * rate * fraction
+		straight := (book - salvage) * fraction / remaining
+		charge := math.Min(round(math.Max(declining, straight)), book-salvage)
+		amounts = append(amounts, charge)
+		if charge >= book-salvage {
+			return amounts
+		}
+
+		book -= charge
+		elapsed += fraction
+	}
+}
+
+// exact returns x unrounded.
+func exact(x float64) float64 {
+	return x
+}
+
+// schedule turns a list of charges into rows with running totals. Any charge
+// that would take the book value below salvage is reduced.
+func schedule(cost, salvage float64, amounts []float64) []Row {
+	rows := make([]Row, len(amounts))
+	accumulated := 0.0
+	for i, amount := range amounts {
+		amount = math.Min(amount, cost-salvage-accumulated)
+		accumulated += amount
+		rows[i] = Row{Period: i + 1, Depreciation: amount, Accumulated: accumulated, BookValue: cost - accumulated}
+	}
+	return rows
+}
+
+// firstYearFraction returns the part of a full year the asset is in service in its first year.
+func (a Asset) firstYearFraction() float64 {
+	if a.FirstYearMonths == 0 {
+		return 1
+	}
+	return float64(a.FirstYearMonths) / 12
+}
+
+// check validates the asset.
+func (a Asset) check() error {
+	switch {
+	case a.Cost <= 0:
+		return fmt.Errorf("%w: cost must be positive", ErrInvalidAsset)
+	case a.Salvage < 0 || a.Salvage > a.Cost:
+		return fmt.Errorf("%w: salvage must be between 0 and the cost", ErrInvalidAsset)
+	case a.Life <= 0:
+		return fmt.Errorf("%w: life must be positive", ErrInvalidAsset)
+	case a.FirstYearMonths < 0 || a.FirstYearMonths > 12:
+		return fmt.Errorf("%w: first year must have 0 to 12 months", ErrInvalidAsset)
+	}
+	return nil
+}
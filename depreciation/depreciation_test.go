@@ -0,0 +1,129 @@
+package depreciation
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func checkCharges(t *testing.T, name string, rows []Row, expected []float64, tolerance float64) {
+	t.Helper()
+	if len(rows) != len(expected) {
+		t.Fatalf("%s failed, expected %d periods, got: %d (%+v)", name, len(expected), len(rows), rows)
+	}
+	for i, row := range rows {
+		if math.Abs(row.Depreciation-expected[i]) > tolerance {
+			t.Errorf("%s failed in period %d, expected: '%f', got: '%f'", name, i+1, expected[i], row.Depreciation)
+		}
+	}
+}
+
+func TestStraightLine(t *testing.T) {
+	rows, err := StraightLine(Asset{Cost: 10000, Salvage: 1000, Life: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCharges(t, "StraightLine", rows, []float64{1800, 1800, 1800, 1800, 1800}, 1e-9)
+	if last := rows[len(rows)-1]; math.Abs(last.BookValue-1000) > 1e-9 || math.Abs(last.Accumulated-9000) > 1e-9 {
+		t.Errorf("Test failed, got: %+v", last)
+	}
+
+	// Placed in service for 3 months of the first year.
+	rows, _ = StraightLine(Asset{Cost: 10000, Salvage: 1000, Life: 5, FirstYearMonths: 3})
+	checkCharges(t, "StraightLine partial", rows, []float64{450, 1800, 1800, 1800, 1800, 1350}, 1e-9)
+}
+
+func TestDecliningBalance(t *testing.T) {
+	// Double declining balance switches to straight-line in year 4.
+	rows, err := DecliningBalance(Asset{Cost: 10000, Life: 5}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCharges(t, "DDB", rows, []float64{4000, 2400, 1440, 1080, 1080}, 1e-9)
+
+	// Salvage stops the declining balance early.
+	rows, _ = DecliningBalance(Asset{Cost: 10000, Salvage: 1000, Life: 5}, 2)
+	checkCharges(t, "DDB salvage", rows, []float64{4000, 2400, 1440, 864, 296}, 1e-9)
+
+	rows, _ = DecliningBalance(Asset{Cost: 10000, Life: 5}, 1.5)
+	checkCharges(t, "150DB", rows, []float64{3000, 2100, 1633.33333333333, 1633.33333333333, 1633.33333333333}, 1e-8)
+
+	rows, _ = DecliningBalance(Asset{Cost: 10000, Life: 5, FirstYearMonths: 6}, 2)
+	checkCharges(t, "DDB partial", rows, []float64{2000, 3200, 1920, 1152, 1152, 576}, 1e-9)
+}
+
+func TestSumOfYearsDigits(t *testing.T) {
+	rows, err := SumOfYearsDigits(Asset{Cost: 10000, Salvage: 1000, Life: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCharges(t, "SYD", rows, []float64{3000, 2400, 1800, 1200, 600}, 1e-9)
+
+	rows, _ = SumOfYearsDigits(Asset{Cost: 10000, Salvage: 1000, Life: 5, FirstYearMonths: 6})
+	checkCharges(t, "SYD partial", rows, []float64{1500, 2700, 2100, 1500, 900, 300}, 1e-9)
+}
+
+func TestUnitsOfProduction(t *testing.T) {
+	rows, err := UnitsOfProduction(Asset{Cost: 50000, Salvage: 5000}, 100000, []float64{20000, 30000, 40000, 25000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The last period is capped at the remaining depreciable base.
+	checkCharges(t, "UnitsOfProduction", rows, []float64{9000, 13500, 18000, 4500}, 1e-9)
+
+	if _, err := UnitsOfProduction(Asset{Cost: 50000}, 0, nil); !errors.Is(err, ErrInvalidAsset) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidAsset, err)
+	}
+}
+
+func TestMACRSRates(t *testing.T) {
+	// IRS Publication 946, tables A-1 and A-5, in percent.
+	tests := []struct {
+		property MACRS
+		expected []float64
+	}{
+		{MACRS{Class: 3}, []float64{33.33, 44.45, 14.81, 7.41}},
+		{MACRS{Class: 5}, []float64{20, 32, 19.2, 11.52, 11.52, 5.76}},
+		{MACRS{Class: 7}, []float64{14.29, 24.49, 17.49, 12.49, 8.93, 8.92, 8.93, 4.46}},
+		{MACRS{Class: 10}, []float64{10, 18, 14.4, 11.52, 9.22, 7.37, 6.55, 6.55, 6.56, 6.55, 3.28}},
+		{MACRS{Class: 15}, []float64{5, 9.5, 8.55, 7.7, 6.93, 6.23, 5.9, 5.9, 5.91, 5.9, 5.91, 5.9, 5.91, 5.9, 5.91, 2.95}},
+		{MACRS{Class: 20}, []float64{3.75, 7.219, 6.677, 6.177, 5.713, 5.285, 4.888, 4.522, 4.462, 4.461, 4.462, 4.461, 4.462, 4.461, 4.462, 4.461, 4.462, 4.461, 4.462, 4.461, 2.231}},
+		{MACRS{Class: 5, Convention: MidQuarter, Quarter: 1}, []float64{35, 26, 15.6, 11.01, 11.01, 1.38}},
+		{MACRS{Class: 5, Convention: MidQuarter, Quarter: 4}, []float64{5, 38, 22.8, 13.68, 10.94, 9.58}},
+	}
+
+	for _, tt := range tests {
+		rates, err := tt.property.Rates()
+		if err != nil {
+			t.Fatal(err)
+		}
+		percent := make([]Row, len(rates))
+		total := 0.0
+		for i, r := range rates {
+			percent[i].Depreciation = 100 * r
+			total += r
+		}
+		checkCharges(t, "MACRS", percent, tt.expected, 1e-9)
+		if math.Abs(total-1) > 1e-12 {
+			t.Errorf("Test failed for %+v, rates sum to '%f'", tt.property, total)
+		}
+	}
+}
+
+func TestMACRSSchedule(t *testing.T) {
+	rows, err := MACRS{Class: 5}.Schedule(10000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCharges(t, "MACRS schedule", rows, []float64{2000, 3200, 1920, 1152, 1152, 576}, 1e-9)
+	if last := rows[len(rows)-1]; math.Abs(last.BookValue) > 1e-9 {
+		t.Errorf("Test failed, expected a zero book value, got: '%f'", last.BookValue)
+	}
+
+	if _, err := (MACRS{Class: 6}).Rates(); !errors.Is(err, ErrInvalidMACRS) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidMACRS, err)
+	}
+	if _, err := (MACRS{Class: 5, Convention: MidQuarter}).Rates(); !errors.Is(err, ErrInvalidMACRS) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidMACRS, err)
+	}
+}
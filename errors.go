@@ -0,0 +1,81 @@
+package gofin
+
+import "errors"
+
+// Errors returned by the E variants of the gofin functions. The plain
+// variants swallow these and return a sentinel value (usually 0.0, or -1 for
+// the payback functions) instead.
+var (
+	// ErrZeroRate is returned when a formula divides by the interest rate and the rate is zero.
+	ErrZeroRate = errors.New("gofin: interest rate is zero")
+
+	// ErrInvalidRate is returned when a rate is at or below -100%, which makes (1 + r) non-positive.
+	ErrInvalidRate = errors.New("gofin: interest rate must be greater than -1")
+
+	// ErrRateBelowGrowth is returned when a growing perpetuity's discount rate does not exceed its growth rate.
+	ErrRateBelowGrowth = errors.New("gofin: interest rate must be greater than growth rate")
+
+	// ErrInvalidPeriods is returned when a number of periods is negative, or zero where a positive count is required.
+	ErrInvalidPeriods = errors.New("gofin: invalid number of periods")
+
+	// ErrInvalidTiming is returned for a PaymentTiming other than EndOfPeriod or BeginningOfPeriod.
+	ErrInvalidTiming = errors.New("gofin: payment timing must be end or beginning of period")
+
+	// ErrZeroValue is returned when a formula divides by an initial or present value of zero.
+	ErrZeroValue = errors.New("gofin: value is zero")
+
+	// ErrInvalidReturn is returned when a holding period return is at or below -100%.
+	ErrInvalidReturn = errors.New("gofin: return must be greater than -1")
+
+	// ErrEmptyInput is returned when a calculation needs at least one value and none was given.
+	ErrEmptyInput = errors.New("gofin: no values given")
+
+	// ErrLengthMismatch is returned when parallel slices have different lengths.
+	ErrLengthMismatch = errors.New("gofin: slice lengths do not match")
+
+	// ErrNoPayback is returned when the cumulative cash flows never recover the initial investment.
+	ErrNoPayback = errors.New("gofin: payback period not reached")
+
+	// ErrInvalidLoan is returned when a loan specification is inconsistent, for example a balloon larger than the principal.
+	ErrInvalidLoan = errors.New("gofin: invalid loan specification")
+
+	// ErrInvalidBond is returned when a bond's terms are inconsistent or its settlement date is not before the redemption date.
+	ErrInvalidBond = errors.New("gofin: invalid bond specification")
+
+	// ErrInvalidCompounding is returned for an unknown compounding frequency, or a continuous one where a period is needed.
+	ErrInvalidCompounding = errors.New("gofin: invalid compounding frequency")
+
+	// ErrInvalidRanking is returned for an unknown ranking criterion, or projects whose replacement chain would be too long.
+	ErrInvalidRanking = errors.New("gofin: invalid project ranking")
+
+	// ErrInvalidOption is returned when an option has a non-positive spot, strike, expiry or volatility.
+	ErrInvalidOption = errors.New("gofin: invalid option specification")
+
+	// ErrInvalidSimulation is returned when a simulation has no return model or an invalid number of paths.
+	ErrInvalidSimulation = errors.New("gofin: invalid simulation")
+
+	// ErrInvalidLedger is returned when a performance ledger has too few valuations, repeated
+	// valuation dates, negative values or cash flows outside the valued period.
+	ErrInvalidLedger = errors.New("gofin: invalid performance ledger")
+
+	// ErrInvalidDate is returned when a dated cash flow falls before the first cash flow's date.
+	ErrInvalidDate = errors.New("gofin: cash flow date precedes the first date")
+
+	// ErrNoSignChange is returned when a series of cash flows has no sign change and therefore no internal rate of return.
+	ErrNoSignChange = errors.New("gofin: cash flows must contain both positive and negative values")
+
+	// ErrInvalidDecimal is returned when a string cannot be parsed as a Decimal.
+	ErrInvalidDecimal = errors.New("gofin: invalid decimal")
+
+	// ErrCurrencyMismatch is returned when Money amounts in different currencies are combined.
+	ErrCurrencyMismatch = errors.New("gofin: currencies do not match")
+
+	// ErrNoSolution is returned when no value satisfies the given inputs, for example a number of periods that would have to be a logarithm of a negative number.
+	ErrNoSolution = errors.New("gofin: no solution for the given inputs")
+
+	// ErrInvalidBounds is returned when a search interval is empty or not finite.
+	ErrInvalidBounds = errors.New("gofin: invalid search bounds")
+
+	// ErrNoConvergence is returned when an iterative solver fails to converge.
+	ErrNoConvergence = errors.New("gofin: solver did not converge")
+)
@@ -0,0 +1,211 @@
+package gofin
+
+import (
+	"fmt"
+	"math"
+)
+
+// maxSeekPeriods is the upper bound of the number of periods searched by
+// PeriodsToReach.
+const maxSeekPeriods = 10000
+
+// GoalSeekOptions controls the search of GoalSeek. The zero value is ready to
+// use; zero fields other than Guess take the defaults noted below.
+type GoalSeekOptions struct {
+	// Guess is the starting point for Newton-Raphson, zero included. A guess
+	// outside the bounds is replaced by their middle.
+	Guess float64
+
+	// Derivative is the derivative of the function. When nil it is estimated
+	// by central differences.
+	Derivative func(float64) float64
+
+	// Tolerance is the relative convergence tolerance on the input. Defaults
+	// to 1e-10.
+	Tolerance float64
+
+	// MaxIterations caps the iterations of each root search. Defaults to 100.
+	MaxIterations int
+
+	// ScanSteps is the number of grid intervals used to bracket the target
+	// when Newton-Raphson fails. Defaults to 100.
+	ScanSteps int
+}
+
+// GoalSeekResult holds the outcome of GoalSeek.
+type GoalSeekResult struct {
+	// Value is the input at which the function reaches the target.
+	Value float64
+
+	// Iterations is the number of iterations used to find Value.
+	Iterations int
+
+	// Method names the algorithm that produced Value: "newton" or "brent".
+	Method string
+}
+
+// withDefaults returns a copy of o with its zero fields replaced by the
+// defaults, and its guess kept within the bounds lower and upper.
+func (o GoalSeekOptions) withDefaults(lower, upper float64) GoalSeekOptions {
+	if !(o.Guess >= lower && o.Guess <= upper) {
+		o.Guess = lower + (upper-lower)/2
+	}
+	if o.Tolerance <= 0 {
+		o.Tolerance = 1e-10
+	}
+	if o.MaxIterations <= 0 {
+		o.MaxIterations = 100
+	}
+	if o.ScanSteps <= 0 {
+		o.ScanSteps = 100
+	}
+	return o
+}
+
+// GoalSeek finds the input between lower and upper at which f returns target,
+// like the spreadsheet Goal Seek. It answers the inverse question of any gofin
+// function by closing over the other arguments; for example the rate at which
+// 1000 grows to 2000 in 10 periods:
+//
+//	result, err := GoalSeek(func(rate float64) float64 {
+//		return FutureValue(1000, rate, 10)
+//	}, 2000, 0, 1, GoalSeekOptions{})
+//
+// It starts with Newton-Raphson from the guess and, when an iterate leaves
+// the bounds or fails to converge, scans the bounds for a sign change of
+// f - target and refines the one closest to the guess with Brent's method.
+// It returns ErrInvalidBounds unless lower < upper and both are finite, and
+// ErrNoSolution when f does not reach the target between the bounds, as when
+// it only jumps across it at a pole.
+func GoalSeek(f func(float64) float64, target, lower, upper float64, opts GoalSeekOptions) (GoalSeekResult, error) {
+	if !(lower < upper) || math.IsInf(lower, 0) || math.IsInf(upper, 0) {
+		return GoalSeekResult{}, fmt.Errorf("%w: [%g, %g]", ErrInvalidBounds, lower, upper)
+	}
+	opts = opts.withDefaults(lower, upper)
+
+	g := func(x float64) float64 { return f(x) - target }
+	dg := opts.Derivative
+	if dg == nil {
+		dg = func(x float64) float64 {
+			h := 1e-6 * math.Max(1, math.Abs(x))
+			a, b := math.Max(lower, x-h), math.Min(upper, x+h)
+			return (g(b) - g(a)) / (b - a)
+		}
+	}
+
+	x, iterations, err := newton(g, dg, opts.Guess, lower, upper, opts.Tolerance, opts.MaxIterations)
+	if err == nil {
+		return GoalSeekResult{Value: x, Iterations: iterations, Method: "newton"}, nil
+	}
+
+	a, b, ok := opts.bracket(g, lower, upper)
+	if !ok {
+		return GoalSeekResult{Iterations: iterations}, fmt.Errorf("%w: the function does not reach %g between %g and %g", ErrNoSolution, target, lower, upper)
+	}
+	x, more, err := brent(g, a, b, opts.Tolerance, opts.MaxIterations)
+	result := GoalSeekResult{Value: x, Iterations: iterations + more, Method: "brent"}
+	if err != nil {
+		return result, err
+	}
+
+	// A sign change across a pole, such as that of 1/x at 0, brackets no
+	// root: Brent's method closes in on the pole, where f is far from the
+	// target. Measure the residual against the target and the finite values
+	// of f - target at the ends of the bracket.
+	scale := math.Abs(target)
+	for _, end := range []float64{g(a), g(b)} {
+		if !math.IsInf(end, 0) {
+			scale = math.Max(scale, math.Abs(end))
+		}
+	}
+	if !(math.Abs(g(x)) <= 1e-8*scale) {
+		return GoalSeekResult{Iterations: result.Iterations}, fmt.Errorf("%w: the function jumps across %g near %g", ErrNoSolution, target, x)
+	}
+	return result, nil
+}
+
+// bracket scans [lower, upper] on a uniform grid and returns the interval
+// closest to the guess on which g changes sign or has a grid point, such as a
+// bound, at which it is zero.
+func (o GoalSeekOptions) bracket(g func(float64) float64, lower, upper float64) (float64, float64, bool) {
+	step := (upper - lower) / float64(o.ScanSteps)
+	bestA, bestB, found := 0.0, 0.0, false
+
+	a, ga := lower, g(lower)
+	for i := 1; i <= o.ScanSteps; i++ {
+		b := lower + step*float64(i)
+		if i == o.ScanSteps {
+			b = upper
+		}
+		gb := g(b)
+		if !math.IsNaN(ga) && !math.IsNaN(gb) && (ga == 0 || gb == 0 || (ga > 0) != (gb > 0)) {
+			if !found || math.Abs(a-o.Guess) < math.Abs(bestA-o.Guess) {
+				bestA, bestB, found = a, b, true
+			}
+		}
+		a, ga = b, gb
+	}
+	return bestA, bestB, found
+}
+
+// PeriodsToReach returns the number of periods, possibly fractional, after
+// which presentValue invested at interestRate per period, with payment added
+// at the end or beginning of each period according to timing, grows to
+// target. It returns 0 when presentValue already reaches the target and
+// ErrNoSolution when the target is not reached within 10000 periods.
+func PeriodsToReach(presentValue, payment, interestRate, target float64, timing PaymentTiming) (float64, error) {
+	if err := checkTVM(interestRate, timing); err != nil {
+		return 0.0, err
+	}
+	if presentValue >= target {
+		return 0.0, nil
+	}
+
+	f := func(periods float64) float64 {
+		balance, _ := FV(interestRate, periods, -payment, -presentValue, timing)
+		return balance
+	}
+	result, err := GoalSeek(f, target, 0, maxSeekPeriods, GoalSeekOptions{Guess: 10})
+	if err != nil {
+		return 0.0, err
+	}
+	return result.Value, nil
+}
+
+// PaymentToReach returns the payment to add at the end or beginning of each
+// of periods periods, according to timing, for presentValue invested at
+// interestRate per period to grow to target. The balance is linear in the
+// payment, so it is solved exactly with PMT.
+func PaymentToReach(presentValue, interestRate float64, periods int, target float64, timing PaymentTiming) (float64, error) {
+	if periods <= 0 {
+		return 0.0, ErrInvalidPeriods
+	}
+	payment, err := PMT(interestRate, float64(periods), -presentValue, target, timing)
+	if err != nil {
+		return 0.0, err
+	}
+	return -payment, nil
+}
+
+// RateToReach returns the interest rate per period at which presentValue,
+// with payment added at the end or beginning of each of periods periods
+// according to timing, grows to target. It searches rates between -99% and
+// 1000% per period and returns ErrNoSolution when none reaches the target.
+func RateToReach(presentValue, payment float64, periods int, target float64, timing PaymentTiming) (float64, error) {
+	if timing != EndOfPeriod && timing != BeginningOfPeriod {
+		return 0.0, ErrInvalidTiming
+	}
+	if periods <= 0 {
+		return 0.0, ErrInvalidPeriods
+	}
+
+	f := func(rate float64) float64 {
+		balance, _ := FV(rate, float64(periods), -payment, -presentValue, timing)
+		return balance
+	}
+	result, err := GoalSeek(f, target, -0.99, 10, GoalSeekOptions{Guess: 0.1})
+	if err != nil {
+		return 0.0, err
+	}
+	return result.Value, nil
+}
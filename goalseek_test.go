@@ -0,0 +1,130 @@
+package gofin
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestGoalSeek(t *testing.T) {
+	tests := []struct {
+		name         string
+		f            func(float64) float64
+		target       float64
+		lower, upper float64
+		opts         GoalSeekOptions
+		expected     float64
+		method       string
+	}{
+		{"FutureValue rate", func(rate float64) float64 { return FutureValue(1000, rate, 10) }, 2000, 0, 1, GoalSeekOptions{}, math.Pow(2, 0.1) - 1, "newton"},
+		{"square", func(x float64) float64 { return x * x }, 4, 0, 10, GoalSeekOptions{Guess: 1, Derivative: func(x float64) float64 { return 2 * x }}, 2, "newton"},
+		{"atan from afar", math.Atan, 0, -20, 20, GoalSeekOptions{Guess: 10}, 0, "brent"},
+		{"target at a bound", func(x float64) float64 { return 2 * x }, 0, 0, 1, GoalSeekOptions{Guess: 0.5}, 0, ""},
+		{"guess of zero", math.Sin, 0, 0, 10, GoalSeekOptions{Guess: 0}, 0, "newton"},
+		{"guess out of bounds", math.Sin, 0, 2, 4, GoalSeekOptions{Guess: 0}, math.Pi, "newton"},
+		{"root at the lower bound", func(x float64) float64 { return -x }, 0, 0, 1, GoalSeekOptions{Guess: 0.5}, 0, "brent"},
+		{"root at the upper bound", func(x float64) float64 { return x - 1 }, 0, 0, 1, GoalSeekOptions{Guess: 0.5}, 1, "brent"},
+	}
+
+	for _, test := range tests {
+		result, err := GoalSeek(test.f, test.target, test.lower, test.upper, test.opts)
+		if err != nil || notWithin(result.Value, test.expected, 1e-9) {
+			t.Errorf("Test %s failed, expected: '%f', got: '%f' (%v)", test.name, test.expected, result.Value, err)
+		}
+		if test.method != "" && result.Method != test.method {
+			t.Errorf("Test %s failed, expected the method %s, got: %s", test.name, test.method, result.Method)
+		}
+	}
+}
+
+func TestGoalSeekErrors(t *testing.T) {
+	square := func(x float64) float64 { return x * x }
+	tests := []struct {
+		lower, upper, target float64
+		expected             error
+	}{
+		{1, 1, 0, ErrInvalidBounds},
+		{2, 1, 0, ErrInvalidBounds},
+		{math.Inf(-1), 1, 0, ErrInvalidBounds},
+		{math.NaN(), 1, 0, ErrInvalidBounds},
+		{-10, 10, -1, ErrNoSolution},
+	}
+
+	for _, test := range tests {
+		if _, err := GoalSeek(square, test.target, test.lower, test.upper, GoalSeekOptions{}); !errors.Is(err, test.expected) {
+			t.Errorf("Test failed for [%f, %f], expected: '%v', got: '%v'", test.lower, test.upper, test.expected, err)
+		}
+	}
+
+	// 1/x changes sign at its pole, not at a root.
+	inverse := func(x float64) float64 { return 1 / x }
+	if result, err := GoalSeek(inverse, 0, -10, 10, GoalSeekOptions{Guess: 3}); !errors.Is(err, ErrNoSolution) {
+		t.Errorf("Test failed, expected: '%v', got: '%f' (%v)", ErrNoSolution, result.Value, err)
+	}
+}
+
+func TestPeriodsToReach(t *testing.T) {
+	for _, timing := range []PaymentTiming{EndOfPeriod, BeginningOfPeriod} {
+		expected, _ := NPER(0.05, -100, -1000, 5000, timing)
+		actual, err := PeriodsToReach(1000, 100, 0.05, 5000, timing)
+		if err != nil || notWithin(actual, expected, 1e-8) {
+			t.Errorf("Test failed for timing %d, expected: '%f', got: '%f' (%v)", timing, expected, actual, err)
+		}
+	}
+
+	// Whole periods of FutureValueAnnuity reach the target.
+	periods, _ := PeriodsToReach(0, 100, 0.01, 1000, EndOfPeriod)
+	if whole := int(math.Ceil(periods)); FutureValueAnnuity(100, 0.01, whole) < 1000 || FutureValueAnnuity(100, 0.01, whole-1) >= 1000 {
+		t.Errorf("Test failed, got: '%f'", periods)
+	}
+
+	if actual, err := PeriodsToReach(2000, 0, 0.05, 1000, EndOfPeriod); err != nil || actual != 0 {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", 0.0, actual, err)
+	}
+	if _, err := PeriodsToReach(1000, 0, 0, 2000, EndOfPeriod); !errors.Is(err, ErrNoSolution) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrNoSolution, err)
+	}
+	if _, err := PeriodsToReach(1000, 0, -1, 2000, EndOfPeriod); !errors.Is(err, ErrInvalidRate) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidRate, err)
+	}
+}
+
+func TestPaymentToReach(t *testing.T) {
+	for _, timing := range []PaymentTiming{EndOfPeriod, BeginningOfPeriod} {
+		payment, err := PaymentToReach(1000, 0.005, 120, 50000, timing)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if balance, _ := FV(0.005, 120, -payment, -1000, timing); notWithin(balance, 50000, 1e-6) {
+			t.Errorf("Test failed for timing %d, expected: '%f', got: '%f'", timing, 50000.0, balance)
+		}
+	}
+
+	if payment, err := PaymentToReach(0, 0, 10, 1000, EndOfPeriod); err != nil || notWithin(payment, 100, 1e-12) {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", 100.0, payment, err)
+	}
+	if _, err := PaymentToReach(1000, 0.05, 0, 2000, EndOfPeriod); !errors.Is(err, ErrInvalidPeriods) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidPeriods, err)
+	}
+}
+
+func TestRateToReach(t *testing.T) {
+	if rate, err := RateToReach(1000, 0, 10, 2000, EndOfPeriod); err != nil || notWithin(rate, InterestRate(1000, 2000, 10), 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", InterestRate(1000, 2000, 10), rate, err)
+	}
+
+	rate, err := RateToReach(1000, 100, 10, 3000, BeginningOfPeriod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance, _ := FV(rate, 10, -100, -1000, BeginningOfPeriod); notWithin(balance, 3000, 1e-6) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", 3000.0, balance)
+	}
+
+	if _, err := RateToReach(1000, 0, 10, -5, EndOfPeriod); !errors.Is(err, ErrNoSolution) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrNoSolution, err)
+	}
+	if _, err := RateToReach(1000, 0, 10, 2000, PaymentTiming(2)); !errors.Is(err, ErrInvalidTiming) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidTiming, err)
+	}
+}
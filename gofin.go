@@ -1,6 +1,8 @@
 package gofin
 
 import (
+	"errors"
+	"fmt"
 	"math"
 )
 
@@ -13,12 +15,22 @@ import (
 // r is the interest rate,
 // n is the number of periods.
 func FutureValueAnnuity(payment, interestRate float64, periods int) float64 {
+	fv, _ := FutureValueAnnuityE(payment, interestRate, periods)
+	return fv
+}
+
+// FutureValueAnnuityE is like FutureValueAnnuity but returns ErrZeroRate instead of 0.0
+// when the interest rate is zero, and ErrInvalidRate for a rate below -1.
+func FutureValueAnnuityE(payment, interestRate float64, periods int) (float64, error) {
 	if interestRate == 0 {
 		// Avoid division by zero
-		return 0.0
+		return 0.0, ErrZeroRate
+	}
+	if interestRate <= -1 {
+		return 0.0, ErrInvalidRate
 	}
 
-	return payment * ((math.Pow(1+interestRate, float64(periods)) - 1) / interestRate)
+	return payment * ((math.Pow(1+interestRate, float64(periods)) - 1) / interestRate), nil
 }
 
 // FutureValue returns the future value of an investment based on periodic, constant payments and a constant interest rate.
@@ -28,7 +40,18 @@ func FutureValueAnnuity(payment, interestRate float64, periods int) float64 {
 // r is the interest rate,
 // n is the number of periods.
 func FutureValue(presentValue, interestRate float64, periods int) float64 {
-	return presentValue * math.Pow(1+interestRate, float64(periods))
+	fv, _ := FutureValueE(presentValue, interestRate, periods)
+	return fv
+}
+
+// FutureValueE is like FutureValue but returns ErrInvalidRate for a rate at or below -1.
+// A negative number of periods discounts the value instead.
+func FutureValueE(presentValue, interestRate float64, periods int) (float64, error) {
+	if interestRate <= -1 {
+		return 0.0, ErrInvalidRate
+	}
+
+	return presentValue * math.Pow(1+interestRate, float64(periods)), nil
 }
 
 // NetPresentValue function calculates the net present value of a series of cash flows given an
@@ -39,11 +62,21 @@ func FutureValue(presentValue, interestRate float64, periods int) float64 {
 // r is the interest rate,
 // t is the number of periods.
 func NetPresentValue(interestRate float64, periods int, cashFlows []float64) float64 {
+	npv, _ := NetPresentValueE(interestRate, periods, cashFlows)
+	return npv
+}
+
+// NetPresentValueE is like NetPresentValue but returns ErrInvalidRate for a rate at or below -1.
+func NetPresentValueE(interestRate float64, periods int, cashFlows []float64) (float64, error) {
+	if interestRate <= -1 {
+		return 0.0, ErrInvalidRate
+	}
+
 	npv := 0.0
 	for i := 0; i < len(cashFlows); i++ {
 		npv += cashFlows[i] / math.Pow(1+interestRate, float64(i))
 	}
-	return npv
+	return npv, nil
 }
 
 // PresentValue function calculates the present value of a future amount given an interest rate and
@@ -54,7 +87,18 @@ func NetPresentValue(interestRate float64, periods int, cashFlows []float64) flo
 // r is the interest rate,
 // t is the number of periods.
 func PresentValue(futureValue, interestRate float64, periods int) float64 {
-	return futureValue / math.Pow(1+interestRate, float64(periods))
+	pv, _ := PresentValueE(futureValue, interestRate, periods)
+	return pv
+}
+
+// PresentValueE is like PresentValue but returns ErrInvalidRate for a rate at or below -1.
+// A negative number of periods compounds the value instead.
+func PresentValueE(futureValue, interestRate float64, periods int) (float64, error) {
+	if interestRate <= -1 {
+		return 0.0, ErrInvalidRate
+	}
+
+	return futureValue / math.Pow(1+interestRate, float64(periods)), nil
 }
 
 // The function calculates the present value of an annuity given an interest rate, number of periods,
@@ -65,173 +109,327 @@ func PresentValue(futureValue, interestRate float64, periods int) float64 {
 // r is the interest rate,
 // t is the number of periods.
 func PresentValueAnnuity(interestRate float64, periods int, cashFlows []float64) float64 {
+	pv, _ := PresentValueAnnuityE(interestRate, periods, cashFlows)
+	return pv
+}
+
+// PresentValueAnnuityE is like PresentValueAnnuity but returns ErrInvalidRate for a rate at or below -1.
+func PresentValueAnnuityE(interestRate float64, periods int, cashFlows []float64) (float64, error) {
+	if interestRate <= -1 {
+		return 0.0, ErrInvalidRate
+	}
+
 	presentValueAnnuity := 0.0
 	for i := 0; i < len(cashFlows); i++ {
-		presentValueAnnuity += cashFlows[i] / math.Pow(1+interestRate, float64(i))
+		presentValueAnnuity += cashFlows[i] / math.Pow(1+interestRate, float64(i+1))
 	}
-	return presentValueAnnuity
+	return presentValueAnnuity, nil
 }
 
 // HoldingPeriodReturn calculates the holding period return (HPR)
 func HoldingPeriodReturn(initialValue, finalValue float64) float64 {
-	return (finalValue - initialValue) / initialValue
+	hpr, err := HoldingPeriodReturnE(initialValue, finalValue)
+	if err != nil {
+		// A zero initial value gives an infinite return, or NaN if nothing changed.
+		return (finalValue - initialValue) / initialValue
+	}
+	return hpr
+}
+
+// HoldingPeriodReturnE is like HoldingPeriodReturn but returns ErrZeroValue when the
+// initial value is zero.
+func HoldingPeriodReturnE(initialValue, finalValue float64) (float64, error) {
+	if initialValue == 0 {
+		return 0.0, ErrZeroValue
+	}
+
+	return (finalValue - initialValue) / initialValue, nil
 }
 
 // GeometricMeanReturn calculates the geometric mean return over multiple holding periods
 func GeometricMeanReturn(holdingPeriodReturns []float64) float64 {
+	geometricMean, _ := GeometricMeanReturnE(holdingPeriodReturns)
+	return geometricMean
+}
+
+// GeometricMeanReturnE is like GeometricMeanReturn but returns ErrEmptyInput for no returns
+// and ErrInvalidReturn for a return below -1. A total loss of -1 in any period makes the
+// mean -1.
+func GeometricMeanReturnE(holdingPeriodReturns []float64) (float64, error) {
 	totalLogReturns := 0.0
 	numReturns := len(holdingPeriodReturns)
 
 	if numReturns == 0 {
-		return 0.0 // Avoid division by zero
+		return 0.0, ErrEmptyInput // Avoid division by zero
 	}
 
 	for _, hpr := range holdingPeriodReturns {
+		if hpr < -1 {
+			return 0.0, ErrInvalidReturn
+		}
 		totalLogReturns += math.Log(1 + hpr)
 	}
+	if math.IsInf(totalLogReturns, -1) {
+		return -1, nil
+	}
 
 	geometricMean := math.Exp(totalLogReturns/float64(numReturns)) - 1
-	return geometricMean
+	return geometricMean, nil
 }
 
 // GeometricMeanReturnAnnualized calculates the geometric mean annualized return over multiple holding periods
 func GeometricMeanReturnAnnualized(initialValues, finalValues []float64, holdingPeriods []float64) float64 {
+	geometricMean, _ := GeometricMeanReturnAnnualizedE(initialValues, finalValues, holdingPeriods)
+	return geometricMean
+}
+
+// GeometricMeanReturnAnnualizedE is like GeometricMeanReturnAnnualized but returns an error
+// for empty or mismatched slices and for any invalid holding period.
+func GeometricMeanReturnAnnualizedE(initialValues, finalValues []float64, holdingPeriods []float64) (float64, error) {
 	totalLogReturns := 0.0
 	numReturns := len(initialValues)
 
 	if numReturns == 0 {
-		return 0.0 // Avoid division by zero
+		return 0.0, ErrEmptyInput // Avoid division by zero
+	}
+	if len(finalValues) != numReturns || len(holdingPeriods) != numReturns {
+		return 0.0, ErrLengthMismatch
 	}
 
 	for i := 0; i < numReturns; i++ {
-		annualizedReturn := HoldingPeriodReturnAnnualized(initialValues[i], finalValues[i], holdingPeriods[i])
+		annualizedReturn, err := HoldingPeriodReturnAnnualizedE(initialValues[i], finalValues[i], holdingPeriods[i])
+		if err != nil {
+			return 0.0, fmt.Errorf("holding period %d: %w", i, err)
+		}
+		if annualizedReturn < -1 {
+			return 0.0, fmt.Errorf("holding period %d: %w", i, ErrInvalidReturn)
+		}
 		totalLogReturns += math.Log(1 + annualizedReturn)
 	}
+	if math.IsInf(totalLogReturns, -1) {
+		return -1, nil
+	}
 
 	geometricMean := math.Exp(totalLogReturns/float64(numReturns)) - 1
-	return geometricMean
+	return geometricMean, nil
 }
 
 // HoldingPeriodReturn calculates the holding period return (HPR)
 // as a percentage
 func HoldingPeriodReturnPercentage(initialValue, finalValue float64) float64 {
-	return ((finalValue - initialValue) / initialValue)*100
+	hpr, err := HoldingPeriodReturnPercentageE(initialValue, finalValue)
+	if err != nil {
+		return HoldingPeriodReturn(initialValue, finalValue) * 100
+	}
+	return hpr
+}
+
+// HoldingPeriodReturnPercentageE is like HoldingPeriodReturnPercentage but returns ErrZeroValue
+// when the initial value is zero.
+func HoldingPeriodReturnPercentageE(initialValue, finalValue float64) (float64, error) {
+	hpr, err := HoldingPeriodReturnE(initialValue, finalValue)
+	if err != nil {
+		return 0.0, err
+	}
+
+	return hpr * 100, nil
 }
 
 // DiscountedPaybackPeriod calculates the discounted payback period
 func DiscountedPaybackPeriod(initialInvestment float64, cashInflows []float64, discountRate float64) int {
+	period, err := DiscountedPaybackPeriodE(initialInvestment, cashInflows, discountRate)
+	if err != nil {
+		return -1 // Indicates that the payback period was not reached within the given cash inflows
+	}
+	return period
+}
+
+// DiscountedPaybackPeriodE is like DiscountedPaybackPeriod but returns ErrNoPayback instead of -1
+// when the payback period is not reached.
+func DiscountedPaybackPeriodE(initialInvestment float64, cashInflows []float64, discountRate float64) (int, error) {
+	if discountRate <= -1 {
+		return -1, ErrInvalidRate
+	}
+
 	netPresentValue := -initialInvestment
 	for i, cashInflow := range cashInflows {
 		discountedCashFlow := cashInflow / math.Pow(1+discountRate, float64(i+1))
 		netPresentValue += discountedCashFlow
 
 		if netPresentValue >= 0 {
-			return i + 1
+			return i + 1, nil
 		}
 	}
 
-	return -1 // Indicates that the payback period was not reached within the given cash inflows
+	return -1, ErrNoPayback
 }
 
 // InternalRateOfReturn calculates the Internal Rate of Return (IRR) using an iterative method
 func InternalRateOfReturn(initialInvestment float64, cashFlows []float64) float64 {
-	const maxIterations = 1000
-	const tolerance = 1e-6
-
-	irr := 0.1 // Initial guess for the IRR
-	for i := 0; i < maxIterations; i++ {
-		previousIRR := irr
-
-		// Calculate the NPV using the current guess for IRR
-		npv := -initialInvestment
-		for j, cashFlow := range cashFlows {
-			npv += cashFlow / math.Pow(1+irr, float64(j+1))
-		}
+	irr, _ := InternalRateOfReturnE(initialInvestment, cashFlows)
+	return irr
+}
 
-		// Calculate the derivative of NPV with respect to IRR
-		derivative := 0.0
-		for j, cashFlow := range cashFlows {
-			derivative -= float64(j+1) * cashFlow / math.Pow(1+irr, float64(j+2))
-		}
+// InternalRateOfReturnE is like InternalRateOfReturn but returns an error instead of 0.0
+// when there is no IRR or the solver fails. It uses IRRSolver from a guess of 0.1, as
+// before; use IRRSolver directly to set the guess, tolerance or bounds, or to get every root.
+func InternalRateOfReturnE(initialInvestment float64, cashFlows []float64) (float64, error) {
+	if len(cashFlows) == 0 {
+		return 0.0, ErrEmptyInput
+	}
 
-		// Update the guess for IRR using the Newton-Raphson method
-		irr -= npv / derivative
+	flows := make([]float64, 0, len(cashFlows)+1)
+	flows = append(flows, -initialInvestment)
+	flows = append(flows, cashFlows...)
 
-		// Check for convergence
-		if math.Abs(irr-previousIRR) < tolerance {
-			return irr
-		}
+	result, err := IRRSolver{Guess: 0.1}.Solve(flows)
+	if err != nil {
+		return 0.0, err
 	}
-
-	return 0.0
+	return result.Rate, nil
 }
 
 // PaybackPeriod calculates the payback period
 func PaybackPeriod(initialInvestment float64, cashInflows []float64) int {
+	period, err := PaybackPeriodE(initialInvestment, cashInflows)
+	if err != nil {
+		return -1 // Indicates that the payback period was not reached within the given cash inflows
+	}
+	return period
+}
+
+// PaybackPeriodE is like PaybackPeriod but returns ErrNoPayback instead of -1
+// when the payback period is not reached.
+func PaybackPeriodE(initialInvestment float64, cashInflows []float64) (int, error) {
 	cumulativeCashFlow := -initialInvestment
 	for i, cashInflow := range cashInflows {
 		cumulativeCashFlow += cashInflow
 
 		if cumulativeCashFlow >= 0 {
-			return i + 1
+			return i + 1, nil
 		}
 	}
 
-	return -1 // Indicates that the payback period was not reached within the given cash inflows
+	return -1, ErrNoPayback
 }
 
 // AverageReturn calculates the average return over multiple holding periods
 func AverageReturn(holdingPeriodReturns []float64) float64 {
+	average, _ := AverageReturnE(holdingPeriodReturns)
+	return average
+}
+
+// AverageReturnE is like AverageReturn but returns ErrEmptyInput for no returns.
+func AverageReturnE(holdingPeriodReturns []float64) (float64, error) {
 	totalReturns := 0.0
 	numReturns := len(holdingPeriodReturns)
 
 	if numReturns == 0 {
-		return 0.0 // Avoid division by zero
+		return 0.0, ErrEmptyInput // Avoid division by zero
 	}
 
 	for _, hpr := range holdingPeriodReturns {
 		totalReturns += hpr
 	}
 
-	return totalReturns / float64(numReturns)
+	return totalReturns / float64(numReturns), nil
 }
 
 // AverageReturnAnnualized calculates the average annualized return over multiple holding periods
 func AverageReturnAnnualized(initialValues, finalValues []float64, holdingPeriods []float64) float64 {
+	average, _ := AverageReturnAnnualizedE(initialValues, finalValues, holdingPeriods)
+	return average
+}
+
+// AverageReturnAnnualizedE is like AverageReturnAnnualized but returns an error
+// for empty or mismatched slices and for any invalid holding period.
+func AverageReturnAnnualizedE(initialValues, finalValues []float64, holdingPeriods []float64) (float64, error) {
 	totalAnnualizedReturns := 0.0
 	numReturns := len(initialValues)
 
 	if numReturns == 0 {
-		return 0.0 // Avoid division by zero
+		return 0.0, ErrEmptyInput // Avoid division by zero
+	}
+	if len(finalValues) != numReturns || len(holdingPeriods) != numReturns {
+		return 0.0, ErrLengthMismatch
 	}
 
 	for i := 0; i < numReturns; i++ {
-		annualizedReturn := HoldingPeriodReturnAnnualized(initialValues[i], finalValues[i], holdingPeriods[i])
+		annualizedReturn, err := HoldingPeriodReturnAnnualizedE(initialValues[i], finalValues[i], holdingPeriods[i])
+		if err != nil {
+			return 0.0, fmt.Errorf("holding period %d: %w", i, err)
+		}
 		totalAnnualizedReturns += annualizedReturn
 	}
 
-	return totalAnnualizedReturns / float64(numReturns)
+	return totalAnnualizedReturns / float64(numReturns), nil
 }
 
 // HoldingPeriodReturnAnnualized calculates the annualized holding period return (HPR)
 func HoldingPeriodReturnAnnualized(initialValue, finalValue float64, holdingPeriodInYears float64) float64 {
-	hpr := HoldingPeriodReturn(initialValue, finalValue)
-	return math.Pow(1+hpr, 1/holdingPeriodInYears) - 1
+	hpr, err := HoldingPeriodReturnAnnualizedE(initialValue, finalValue, holdingPeriodInYears)
+	if errors.Is(err, ErrZeroValue) {
+		return math.Pow(1+HoldingPeriodReturn(initialValue, finalValue), 1/holdingPeriodInYears) - 1
+	}
+	return hpr
+}
+
+// HoldingPeriodReturnAnnualizedE is like HoldingPeriodReturnAnnualized but returns an error
+// for a zero initial value or a holding period that is not positive.
+func HoldingPeriodReturnAnnualizedE(initialValue, finalValue float64, holdingPeriodInYears float64) (float64, error) {
+	if holdingPeriodInYears <= 0 {
+		return 0.0, ErrInvalidPeriods
+	}
+	hpr, err := HoldingPeriodReturnE(initialValue, finalValue)
+	if err != nil {
+		return 0.0, err
+	}
+	return math.Pow(1+hpr, 1/holdingPeriodInYears) - 1, nil
 }
 
 // HoldingPeriodReturnAnnualized calculates the annualized holding period return (HPR)
 // as a percentage
 func HoldingPeriodReturnAnnualizedPercentage(initialValue, finalValue float64, holdingPeriodInYears float64) float64 {
-	hpr := HoldingPeriodReturn(initialValue, finalValue)
-	return (math.Pow(1+hpr, 1/holdingPeriodInYears) - 1)*100
+	hpr, err := HoldingPeriodReturnAnnualizedPercentageE(initialValue, finalValue, holdingPeriodInYears)
+	if errors.Is(err, ErrZeroValue) {
+		return HoldingPeriodReturnAnnualized(initialValue, finalValue, holdingPeriodInYears) * 100
+	}
+	return hpr
+}
+
+// HoldingPeriodReturnAnnualizedPercentageE is like HoldingPeriodReturnAnnualizedPercentage but
+// returns an error for a zero initial value or a holding period that is not positive.
+func HoldingPeriodReturnAnnualizedPercentageE(initialValue, finalValue float64, holdingPeriodInYears float64) (float64, error) {
+	hpr, err := HoldingPeriodReturnAnnualizedE(initialValue, finalValue, holdingPeriodInYears)
+	if err != nil {
+		return 0.0, err
+	}
+	return hpr * 100, nil
 }
 
+// PresentValueAnnuityDue calculates the present value of an annuity whose cash flows are paid
+// at the beginning of each period, so the first one is not discounted.
+// PV = sum(C / (1 + r)^(t - 1))
+// PV is the present value,
+// C is the cash flow at the beginning of each period,
+// r is the interest rate,
+// t is the number of periods.
 func PresentValueAnnuityDue(interestRate float64, periods int, cashFlows []float64) float64 {
+	pv, _ := PresentValueAnnuityDueE(interestRate, periods, cashFlows)
+	return pv
+}
+
+// PresentValueAnnuityDueE is like PresentValueAnnuityDue but returns ErrInvalidRate for a rate at or below -1.
+func PresentValueAnnuityDueE(interestRate float64, periods int, cashFlows []float64) (float64, error) {
+	if interestRate <= -1 {
+		return 0.0, ErrInvalidRate
+	}
+
 	presentValueAnnuityDue := 0.0
 	for i := 0; i < len(cashFlows); i++ {
 		presentValueAnnuityDue += cashFlows[i] / math.Pow(1+interestRate, float64(i))
 	}
-	return presentValueAnnuityDue
+	return presentValueAnnuityDue, nil
 }
 
 // PresentValuePerpetuity returns the present value of a perpetuity.
@@ -242,106 +440,314 @@ func PresentValueAnnuityDue(interestRate float64, periods int, cashFlows []float
 // C is the cash flow at the end of the first period,
 // r is the discount rate.
 func PresentValuePerpetuity(interestRate, cashFlow float64) float64 {
+	pv, _ := PresentValuePerpetuityE(interestRate, cashFlow)
+	return pv
+}
+
+// PresentValuePerpetuityE is like PresentValuePerpetuity but returns ErrZeroRate instead of 0.0
+// when the interest rate is zero.
+func PresentValuePerpetuityE(interestRate, cashFlow float64) (float64, error) {
 	if interestRate == 0 {
 		// Avoid division by zero.
-		return 0.0
+		return 0.0, ErrZeroRate
 	}
 
-	return cashFlow / interestRate
+	return cashFlow / interestRate, nil
 }
 
 // ModifiedInternalRateOfReturn calculates the Modified Internal Rate of Return (MIRR)
 func ModifiedInternalRateOfReturn(initialInvestment float64, cashOutflows []float64, cashInflows []float64, financeRate float64) float64 {
+	mirr, _ := ModifiedInternalRateOfReturnE(initialInvestment, cashOutflows, cashInflows, financeRate)
+	return mirr
+}
+
+// ModifiedInternalRateOfReturnE is like ModifiedInternalRateOfReturn but returns an error
+// when there are no outflow periods or the discounted outflows are zero.
+func ModifiedInternalRateOfReturnE(initialInvestment float64, cashOutflows []float64, cashInflows []float64, financeRate float64) (float64, error) {
+	if len(cashOutflows) == 0 {
+		return 0.0, ErrEmptyInput
+	}
+	if financeRate <= -1 {
+		return 0.0, ErrInvalidRate
+	}
+
 	npvOutflows := -initialInvestment
 	for i, cashOutflow := range cashOutflows {
 		npvOutflows += cashOutflow / math.Pow(1+financeRate, float64(i+1))
 	}
+	if npvOutflows == 0 {
+		return 0.0, ErrZeroValue
+	}
 
 	npvInflows := 0.0
 	for i, cashInflow := range cashInflows {
 		npvInflows += cashInflow / math.Pow(1+financeRate, float64(i+1))
 	}
 
-	mirr := math.Pow((npvInflows / -npvOutflows), 1/float64(len(cashOutflows))) - 1
-	return mirr
+	mirr := math.Pow((npvInflows/-npvOutflows), 1/float64(len(cashOutflows))) - 1
+	if math.IsNaN(mirr) {
+		return 0.0, ErrInvalidReturn
+	}
+	return mirr, nil
 }
 
 // PresentValuePerpetuityDue returns the present value of a perpetuity due.
 // The present value of a perpetuity due is the cash flow divided by the discount rate multiplied by 1 plus the discount rate.
 // The interest rate must be greater than zero to avoid division by zero.
-// PV= (C / r) * (1 / (1+r))
+// PV= (C / r) * (1 + r)
 // PV is the present value,
-// C is the cash flow at the end of the first period,
+// C is the cash flow at the beginning of the first period,
 // r is the discount rate.
 func PresentValuePerpetuityDue(interestRate, cashFlow float64) float64 {
+	pv, _ := PresentValuePerpetuityDueE(interestRate, cashFlow)
+	return pv
+}
+
+// PresentValuePerpetuityDueE is like PresentValuePerpetuityDue but returns ErrZeroRate instead of 0.0
+// when the interest rate is zero.
+func PresentValuePerpetuityDueE(interestRate, cashFlow float64) (float64, error) {
 	if interestRate == 0 {
 		// Avoid division by zero.
-		return 0.0
+		return 0.0, ErrZeroRate
+	}
+	if interestRate <= -1 {
+		return 0.0, ErrInvalidRate
 	}
 
-	return cashFlow / interestRate * (1 / (1 + interestRate))
+	return cashFlow / interestRate * (1 + interestRate), nil
 }
 
+// InterestRateGrowingPerpetuity returns the discount rate implied by the present value of a growing perpetuity.
+// r = C / PV + g
+// r is the discount rate,
+// C is the cash flow at the end of the first period,
+// PV is the present value,
+// g is the growth rate.
 func InterestRateGrowingPerpetuity(presentValue, cashFlow, growthRate float64) float64 {
-	return cashFlow/presentValue + growthRate
+	rate, _ := InterestRateGrowingPerpetuityE(presentValue, cashFlow, growthRate)
+	return rate
+}
+
+// InterestRateGrowingPerpetuityE is like InterestRateGrowingPerpetuity but returns ErrZeroValue
+// when the present value is zero.
+func InterestRateGrowingPerpetuityE(presentValue, cashFlow, growthRate float64) (float64, error) {
+	if presentValue == 0 {
+		return 0.0, ErrZeroValue
+	}
+	return cashFlow/presentValue + growthRate, nil
 }
 
+// InterestRateGrowingAnnuity returns the discount rate implied by a growing annuity without an
+// end date, which is the growing perpetuity rate C / PV + g.
+//
+// Deprecated: Use ImpliedRateGrowingAnnuity, which takes the number of periods.
 func InterestRateGrowingAnnuity(presentValue, cashFlows, growthRate float64) float64 {
-	return cashFlows/presentValue + growthRate
+	rate, _ := InterestRateGrowingAnnuityE(presentValue, cashFlows, growthRate)
+	return rate
 }
 
-// TODO: fix this
+// InterestRateGrowingAnnuityE is like InterestRateGrowingAnnuity but returns ErrZeroValue
+// when the present value is zero.
+//
+// Deprecated: Use ImpliedRateGrowingAnnuity, which takes the number of periods.
+func InterestRateGrowingAnnuityE(presentValue, cashFlows, growthRate float64) (float64, error) {
+	return InterestRateGrowingPerpetuityE(presentValue, cashFlows, growthRate)
+}
+
+// InterestRateGrowingAnnuityDue returns the discount rate implied by a growing annuity due
+// without an end date, which is the growing perpetuity due rate.
+//
+// Deprecated: Use ImpliedRateGrowingAnnuity with BeginningOfPeriod, which takes the number of periods.
 func InterestRateGrowingAnnuityDue(presentValue, cashFlows, growthRate float64) float64 {
-	return cashFlows/presentValue + growthRate
+	rate, _ := InterestRateGrowingAnnuityDueE(presentValue, cashFlows, growthRate)
+	return rate
 }
 
+// InterestRateGrowingAnnuityDueE is like InterestRateGrowingAnnuityDue but returns an error
+// when the present value is zero or equal to the first payment.
+//
+// Deprecated: Use ImpliedRateGrowingAnnuity with BeginningOfPeriod, which takes the number of periods.
+func InterestRateGrowingAnnuityDueE(presentValue, cashFlows, growthRate float64) (float64, error) {
+	return InterestRateGrowingPerpetuityDueE(presentValue, cashFlows, growthRate)
+}
+
+// InterestRateAnnuity returns the discount rate implied by an annuity without an end date,
+// which is the perpetuity rate C / PV.
+//
+// Deprecated: Use ImpliedRateAnnuity, which takes the number of periods.
 func InterestRateAnnuity(presentValue, cashFlows float64) float64 {
-	return cashFlows / presentValue
+	rate, _ := InterestRateAnnuityE(presentValue, cashFlows)
+	return rate
 }
 
+// InterestRateAnnuityE is like InterestRateAnnuity but returns ErrZeroValue
+// when the present value is zero.
+//
+// Deprecated: Use ImpliedRateAnnuity, which takes the number of periods.
+func InterestRateAnnuityE(presentValue, cashFlows float64) (float64, error) {
+	return InterestRatePerpetuityE(presentValue, cashFlows)
+}
+
+// InterestRateAnnuityDue returns the discount rate implied by an annuity due without an end
+// date, which is the perpetuity due rate C / (PV - C).
+//
+// Deprecated: Use ImpliedRateAnnuity with BeginningOfPeriod, which takes the number of periods.
 func InterestRateAnnuityDue(presentValue, cashFlows float64) float64 {
-	return cashFlows / presentValue
+	rate, _ := InterestRateAnnuityDueE(presentValue, cashFlows)
+	return rate
+}
+
+// InterestRateAnnuityDueE is like InterestRateAnnuityDue but returns an error
+// when the present value is zero or equal to the payment.
+//
+// Deprecated: Use ImpliedRateAnnuity with BeginningOfPeriod, which takes the number of periods.
+func InterestRateAnnuityDueE(presentValue, cashFlows float64) (float64, error) {
+	return InterestRatePerpetuityDueE(presentValue, cashFlows)
 }
 
-// TODO: fix this
+// InterestRatePerpetuityDue returns the discount rate implied by the present value of a perpetuity due.
+// r = C / (PV - C)
+// r is the discount rate,
+// C is the cash flow at the beginning of the first period,
+// PV is the present value.
 func InterestRatePerpetuityDue(presentValue, cashFlow float64) float64 {
-	return cashFlow / presentValue
+	rate, _ := InterestRatePerpetuityDueE(presentValue, cashFlow)
+	return rate
+}
+
+// InterestRatePerpetuityDueE is like InterestRatePerpetuityDue but returns ErrZeroValue
+// when the present value is zero and ErrNoSolution when it equals the cash flow.
+func InterestRatePerpetuityDueE(presentValue, cashFlow float64) (float64, error) {
+	return InterestRateGrowingPerpetuityDueE(presentValue, cashFlow, 0)
 }
 
-// TODO: fix this
+// InterestRateGrowingPerpetuityDue returns the discount rate implied by the present value of a growing perpetuity due.
+// r = (C + PV * g) / (PV - C)
+// r is the discount rate,
+// C is the cash flow at the beginning of the first period,
+// PV is the present value,
+// g is the growth rate.
 func InterestRateGrowingPerpetuityDue(presentValue, cashFlow, growthRate float64) float64 {
-	return cashFlow/presentValue + growthRate
+	rate, _ := InterestRateGrowingPerpetuityDueE(presentValue, cashFlow, growthRate)
+	return rate
+}
+
+// InterestRateGrowingPerpetuityDueE is like InterestRateGrowingPerpetuityDue but returns ErrZeroValue
+// when the present value is zero and ErrNoSolution when it equals the cash flow.
+func InterestRateGrowingPerpetuityDueE(presentValue, cashFlow, growthRate float64) (float64, error) {
+	if presentValue == 0 {
+		return 0.0, ErrZeroValue
+	}
+	if presentValue == cashFlow {
+		return 0.0, fmt.Errorf("%w: present value equals the first cash flow", ErrNoSolution)
+	}
+	return (cashFlow + presentValue*growthRate) / (presentValue - cashFlow), nil
 }
 
 func InterestRate(presentValue, futureValue float64, periods int) float64 {
-	return math.Pow(futureValue/presentValue, 1/float64(periods)) - 1
+	rate, _ := InterestRateE(presentValue, futureValue, periods)
+	return rate
 }
 
+// InterestRateE is like InterestRate but returns an error for a zero present value
+// or a number of periods that is not positive.
+func InterestRateE(presentValue, futureValue float64, periods int) (float64, error) {
+	if presentValue == 0 {
+		return 0.0, ErrZeroValue
+	}
+	if periods <= 0 {
+		return 0.0, ErrInvalidPeriods
+	}
+	return math.Pow(futureValue/presentValue, 1/float64(periods)) - 1, nil
+}
+
+// InterestRateContinuousCompounding returns the continuously compounded rate per
+// period that grows presentValue to futureValue. Use Rate to convert it to
+// other compounding frequencies.
+// r = ln(FV / PV) / n
 func InterestRateContinuousCompounding(presentValue, futureValue float64, periods int) float64 {
-	return math.Log(futureValue/presentValue) / float64(periods)
+	rate, _ := InterestRateContinuousCompoundingE(presentValue, futureValue, periods)
+	return rate
+}
+
+// InterestRateContinuousCompoundingE is like InterestRateContinuousCompounding but returns an error
+// for a zero present value or a number of periods that is not positive.
+func InterestRateContinuousCompoundingE(presentValue, futureValue float64, periods int) (float64, error) {
+	if presentValue == 0 {
+		return 0.0, ErrZeroValue
+	}
+	if periods <= 0 {
+		return 0.0, ErrInvalidPeriods
+	}
+	return math.Log(futureValue/presentValue) / float64(periods), nil
 }
 
 func InterestRatePerpetuity(presentValue, cashFlow float64) float64 {
-	return cashFlow / presentValue
+	rate, _ := InterestRatePerpetuityE(presentValue, cashFlow)
+	return rate
 }
 
-func PresentValueGrowingAnnuity(interestRate, growthRate float64, periods int, cashFlows []float64) float64 {
-	pv := 0.0
-	for i := 0; i < len(cashFlows); i++ {
-		pv += cashFlows[i] / math.Pow(1+interestRate, float64(i+1))
+// InterestRatePerpetuityE is like InterestRatePerpetuity but returns ErrZeroValue
+// when the present value is zero.
+func InterestRatePerpetuityE(presentValue, cashFlow float64) (float64, error) {
+	if presentValue == 0 {
+		return 0.0, ErrZeroValue
 	}
+	return cashFlow / presentValue, nil
+}
+
+// PresentValueGrowingAnnuity returns the present value of an annuity whose payments grow at a
+// constant rate. Only cashFlows[0], the first payment, is used; the following periods-1 payments
+// are derived from it with the growth rate.
+// PV = C / (r - g) * (1 - ((1 + g) / (1 + r))^n)
+// PV = n * C / (1 + r) when r == g
+// PV is the present value,
+// C is the cash flow at the end of the first period,
+// r is the discount rate,
+// g is the growth rate,
+// n is the number of periods.
+func PresentValueGrowingAnnuity(interestRate, growthRate float64, periods int, cashFlows []float64) float64 {
+	pv, _ := PresentValueGrowingAnnuityE(interestRate, growthRate, periods, cashFlows)
 	return pv
 }
 
-// TODO: fix this
-func PresentValueGrowingAnnuityDue(interestRate, growthRate float64, periods int, cashFlows []float64) float64 {
-	pv := 0.0
-	for i := 0; i < len(cashFlows); i++ {
-		pv += cashFlows[i] / math.Pow(1+interestRate, float64(i))
+// PresentValueGrowingAnnuityE is like PresentValueGrowingAnnuity but returns an error for a rate
+// at or below -1, a negative number of periods or empty cash flows.
+func PresentValueGrowingAnnuityE(interestRate, growthRate float64, periods int, cashFlows []float64) (float64, error) {
+	if err := checkGrowingAnnuity(interestRate, growthRate, periods); err != nil {
+		return 0.0, err
+	}
+	if len(cashFlows) == 0 {
+		return 0.0, ErrEmptyInput
 	}
+
+	return cashFlows[0] * growingAnnuityFactor(interestRate, growthRate, periods), nil
+}
+
+// PresentValueGrowingAnnuityDue returns the present value of a growing annuity whose payments
+// are made at the beginning of each period. Only cashFlows[0], the first payment, is used.
+// PV = C / (r - g) * (1 - ((1 + g) / (1 + r))^n) * (1 + r)
+// PV = n * C when r == g
+// PV is the present value,
+// C is the cash flow at the beginning of the first period,
+// r is the discount rate,
+// g is the growth rate,
+// n is the number of periods.
+func PresentValueGrowingAnnuityDue(interestRate, growthRate float64, periods int, cashFlows []float64) float64 {
+	pv, _ := PresentValueGrowingAnnuityDueE(interestRate, growthRate, periods, cashFlows)
 	return pv
 }
 
+// PresentValueGrowingAnnuityDueE is like PresentValueGrowingAnnuityDue but returns an error for a
+// rate at or below -1, a negative number of periods or empty cash flows.
+func PresentValueGrowingAnnuityDueE(interestRate, growthRate float64, periods int, cashFlows []float64) (float64, error) {
+	pv, err := PresentValueGrowingAnnuityE(interestRate, growthRate, periods, cashFlows)
+	if err != nil {
+		return 0.0, err
+	}
+	return pv * (1 + interestRate), nil
+}
+
 // PresentValueGrowingPerpetuity returns the present value of a growing perpetuity.
 // The present value of a growing perpetuity is the cash flow divided by the difference between the discount rate and the growth rate.
 // The interest rate must be greater than the growth rate to avoid division by zero.
@@ -351,27 +757,44 @@ func PresentValueGrowingAnnuityDue(interestRate, growthRate float64, periods int
 // r is the discount rate,
 // g is the growth rate.
 func PresentValueGrowingPerpetuity(interestRate, growthRate, cashFlow float64) float64 {
+	pv, _ := PresentValueGrowingPerpetuityE(interestRate, growthRate, cashFlow)
+	return pv
+}
+
+// PresentValueGrowingPerpetuityE is like PresentValueGrowingPerpetuity but returns ErrRateBelowGrowth
+// instead of 0.0 when the interest rate does not exceed the growth rate.
+func PresentValueGrowingPerpetuityE(interestRate, growthRate, cashFlow float64) (float64, error) {
 	if interestRate <= growthRate {
 		// Ensure the interest rate is greater than the growth rate to avoid division by zero.
-		return 0.0
+		return 0.0, ErrRateBelowGrowth
 	}
 
-	return cashFlow / (interestRate - growthRate)
+	return cashFlow / (interestRate - growthRate), nil
 }
 
 // PresentValueGrowingPerpetuityDue returns the present value of a growing perpetuity due.
 // The present value of a growing perpetuity due is the cash flow divided by the difference between the discount rate and the growth rate multiplied by 1 plus the discount rate.
 // The interest rate must be greater than the growth rate to avoid division by zero.
-// PV= (C / r−g) * (1 / (1+r))
+// PV= (C / r−g) * (1 + r)
 // PV is the present value,
-// C is the cash flow at the end of the first period,
+// C is the cash flow at the beginning of the first period,
 // r is the discount rate,
 // g is the growth rate.
 func PresentValueGrowingPerpetuityDue(interestRate, growthRate, cashFlow float64) float64 {
+	pv, _ := PresentValueGrowingPerpetuityDueE(interestRate, growthRate, cashFlow)
+	return pv
+}
+
+// PresentValueGrowingPerpetuityDueE is like PresentValueGrowingPerpetuityDue but returns ErrRateBelowGrowth
+// instead of 0.0 when the interest rate does not exceed the growth rate.
+func PresentValueGrowingPerpetuityDueE(interestRate, growthRate, cashFlow float64) (float64, error) {
 	if interestRate <= growthRate {
 		// Ensure the interest rate is greater than the growth rate to avoid division by zero.
-		return 0.0
+		return 0.0, ErrRateBelowGrowth
+	}
+	if interestRate <= -1 {
+		return 0.0, ErrInvalidRate
 	}
 
-	return (cashFlow / (interestRate - growthRate)) * (1 / (1 + interestRate))
+	return (cashFlow / (interestRate - growthRate)) * (1 + interestRate), nil
 }
@@ -1,6 +1,7 @@
 package gofin
 
 import (
+	"errors"
 	"math"
 	"testing"
 )
@@ -420,3 +421,134 @@ func TestInterestRateGrowingPerpetuityDue(t *testing.T) {
 func compareFloat64(a, b float64) bool {
 	return math.Abs(a-b) < 0.000
 }
+
+func TestFutureValueAnnuityE(t *testing.T) {
+	var payment float64 = 100
+	var interestRate float64 = 0.1
+	var periods int = 2
+	var expected float64 = 210
+	actual, err := FutureValueAnnuityE(payment, interestRate, periods)
+
+	if err != nil || notWithin(actual, expected, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", expected, actual, err)
+	}
+
+	if _, err := FutureValueAnnuityE(payment, 0, periods); !errors.Is(err, ErrZeroRate) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrZeroRate, err)
+	}
+}
+
+func TestPresentValuePerpetuityE(t *testing.T) {
+	if _, err := PresentValuePerpetuityE(0, 100); !errors.Is(err, ErrZeroRate) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrZeroRate, err)
+	}
+	if actual := PresentValuePerpetuity(0, 100); actual != 0 {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", 0.0, actual)
+	}
+}
+
+func TestPresentValueGrowingPerpetuityE(t *testing.T) {
+	var expected float64 = 2000
+	actual, err := PresentValueGrowingPerpetuityE(0.1, 0.05, 100)
+
+	if err != nil || notWithin(actual, expected, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", expected, actual, err)
+	}
+
+	if _, err := PresentValueGrowingPerpetuityE(0.05, 0.1, 100); !errors.Is(err, ErrRateBelowGrowth) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrRateBelowGrowth, err)
+	}
+}
+
+func TestInternalRateOfReturnE(t *testing.T) {
+	var initialInvestment float64 = 1000
+	var cashInflows []float64 = []float64{100, 100, 100, 100, 1100}
+	var expected float64 = 0.1
+
+	actual, err := InternalRateOfReturnE(initialInvestment, cashInflows)
+	if err != nil || notWithin(actual, expected, 1e-6) {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", expected, actual, err)
+	}
+
+	if _, err := InternalRateOfReturnE(1000, []float64{-100, -100}); !errors.Is(err, ErrNoSignChange) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrNoSignChange, err)
+	}
+}
+
+func TestPaybackPeriodE(t *testing.T) {
+	if _, err := PaybackPeriodE(1000, []float64{100, 100}); !errors.Is(err, ErrNoPayback) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrNoPayback, err)
+	}
+	if actual := PaybackPeriod(1000, []float64{100, 100}); actual != -1 {
+		t.Errorf("Test failed, expected: '%d', got: '%d'", -1, actual)
+	}
+	if _, err := DiscountedPaybackPeriodE(1000, []float64{100, 100}, 0.1); !errors.Is(err, ErrNoPayback) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrNoPayback, err)
+	}
+}
+
+func TestGeometricMeanReturnAnnualizedE(t *testing.T) {
+	_, err := GeometricMeanReturnAnnualizedE([]float64{1000, 1000}, []float64{1200}, []float64{2, 2})
+	if !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrLengthMismatch, err)
+	}
+
+	_, err = AverageReturnAnnualizedE([]float64{0}, []float64{1200}, []float64{2})
+	if !errors.Is(err, ErrZeroValue) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrZeroValue, err)
+	}
+
+	if _, err := GeometricMeanReturnE(nil); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrEmptyInput, err)
+	}
+}
+
+func TestInterestRateE(t *testing.T) {
+	if _, err := InterestRateE(0, 121, 2); !errors.Is(err, ErrZeroValue) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrZeroValue, err)
+	}
+	if _, err := InterestRateE(100, 121, 0); !errors.Is(err, ErrInvalidPeriods) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidPeriods, err)
+	}
+	if _, err := FutureValueE(100, -1.5, 2); !errors.Is(err, ErrInvalidRate) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidRate, err)
+	}
+}
+
+func TestLegacyResults(t *testing.T) {
+	// Negative periods move the value the other way in time.
+	if actual := FutureValue(100, 0.1, -1); notWithin(actual, 90.9090909090909, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", 90.9090909090909, actual)
+	}
+	if actual := PresentValue(100, 0.1, -1); notWithin(actual, 110, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", 110.0, actual)
+	}
+
+	// A total loss in any period is a total loss overall.
+	actual, err := GeometricMeanReturnE([]float64{-1, 0.5})
+	if err != nil || actual != -1 {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", -1.0, actual, err)
+	}
+	if actual := GeometricMeanReturn([]float64{-1, 0.5}); actual != -1 {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", -1.0, actual)
+	}
+	if _, err := GeometricMeanReturnE([]float64{-1.5, 0.5}); !errors.Is(err, ErrInvalidReturn) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidReturn, err)
+	}
+
+	// A zero initial value has an infinite return.
+	if actual := HoldingPeriodReturn(0, 100); !math.IsInf(actual, 1) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", math.Inf(1), actual)
+	}
+	if actual := HoldingPeriodReturnPercentage(0, 100); !math.IsInf(actual, 1) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", math.Inf(1), actual)
+	}
+	if _, err := HoldingPeriodReturnE(0, 100); !errors.Is(err, ErrZeroValue) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrZeroValue, err)
+	}
+}
+
+// notWithin reports whether a and b differ by more than tolerance.
+func notWithin(a, b, tolerance float64) bool {
+	return !(math.Abs(a-b) <= tolerance)
+}
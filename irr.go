@@ -0,0 +1,268 @@
+package gofin
+
+import (
+	"math"
+	"sort"
+)
+
+// IRRSolver finds the internal rate of return of a series of cash flows.
+// It starts with Newton-Raphson from Guess and, when Newton fails, scans
+// [LowerBound, UpperBound] for sign changes of the NPV and refines the one
+// closest to the guess with Brent's method. With AllRoots it scans the bounds
+// even when Newton succeeds, so non-conventional cash flows (for example a
+// mid-life capital outflow) report all of their IRRs.
+//
+// The zero value is ready to use; zero fields other than Guess take the
+// defaults noted below.
+type IRRSolver struct {
+	// Guess is the starting point for Newton-Raphson, zero included. A guess
+	// outside the bounds is replaced by 0.1, or by the middle of the bounds
+	// when 0.1 lies outside them too.
+	Guess float64
+
+	// Tolerance is the relative convergence tolerance on the rate. Defaults to 1e-10.
+	Tolerance float64
+
+	// MaxIterations caps the iterations of each root search. Defaults to 100.
+	MaxIterations int
+
+	// LowerBound and UpperBound delimit the rates searched. A zero bound
+	// takes its default on its own: -0.99 (-99%) for LowerBound and 10
+	// (1000%) for UpperBound.
+	LowerBound float64
+	UpperBound float64
+
+	// ScanSteps is the number of grid intervals used to look for sign
+	// changes of the NPV between the bounds. Defaults to 1000.
+	ScanSteps int
+
+	// AllRoots makes the solver look for every IRR between the bounds, not
+	// just the one reached from the guess.
+	AllRoots bool
+}
+
+// IRRResult holds the outcome of IRRSolver.Solve.
+type IRRResult struct {
+	// Rate is the IRR closest to the solver's guess.
+	Rate float64
+
+	// Roots holds every IRR found between the solver's bounds, in ascending
+	// order, when the solver has AllRoots set, and otherwise just Rate.
+	Roots []float64
+
+	// Iterations is the number of iterations used to find Rate, counting
+	// those of a failed Newton-Raphson before Brent's method.
+	Iterations int
+
+	// Converged reports whether Rate satisfies the tolerance.
+	Converged bool
+
+	// Method names the algorithm that produced Rate: "newton" or "brent".
+	Method string
+}
+
+// withDefaults returns a copy of s with its zero fields replaced by the defaults.
+func (s IRRSolver) withDefaults() IRRSolver {
+	if s.Tolerance <= 0 {
+		s.Tolerance = 1e-10
+	}
+	if s.MaxIterations <= 0 {
+		s.MaxIterations = 100
+	}
+	if s.LowerBound == 0 {
+		s.LowerBound = -0.99
+	}
+	if s.UpperBound == 0 {
+		s.UpperBound = 10
+	}
+	if !(s.Guess >= s.LowerBound && s.Guess <= s.UpperBound) {
+		s.Guess = 0.1
+		if !(s.Guess >= s.LowerBound && s.Guess <= s.UpperBound) {
+			s.Guess = s.LowerBound + (s.UpperBound-s.LowerBound)/2
+		}
+	}
+	if s.ScanSteps <= 0 {
+		s.ScanSteps = 1000
+	}
+	return s
+}
+
+// Solve returns the IRR of cashFlows, where cashFlows[0] occurs now and
+// cashFlows[t] at the end of period t. It returns ErrNoSignChange when the
+// cash flows do not change sign and ErrNoConvergence when no root is found.
+func (s IRRSolver) Solve(cashFlows []float64) (IRRResult, error) {
+	s = s.withDefaults()
+
+	if len(cashFlows) < 2 {
+		return IRRResult{}, ErrEmptyInput
+	}
+	if !hasSignChange(cashFlows) {
+		return IRRResult{}, ErrNoSignChange
+	}
+	if err := s.checkBounds(); err != nil {
+		return IRRResult{}, err
+	}
+
+	f := func(rate float64) float64 {
+		npv, _ := npvAndDerivative(rate, cashFlows)
+		return npv
+	}
+	df := func(rate float64) float64 {
+		_, d := npvAndDerivative(rate, cashFlows)
+		return d
+	}
+
+	return s.solve(f, df)
+}
+
+// solve finds the root of the NPV function f, whose derivative is df, and
+// with AllRoots every other root. The solver must already have its defaults
+// applied.
+func (s IRRSolver) solve(f, df func(float64) float64) (IRRResult, error) {
+	rate, iterations, err := newton(f, df, s.Guess, s.LowerBound, s.UpperBound, s.Tolerance, s.MaxIterations)
+	if err == nil {
+		result := IRRResult{Rate: rate, Roots: []float64{rate}, Iterations: iterations, Converged: true, Method: "newton"}
+		if s.AllRoots {
+			result.Roots = mergeRoot(s.roots(f, s.scan(f)), rate, s.Tolerance)
+		}
+		return result, nil
+	}
+
+	brackets := s.scan(f)
+	if len(brackets) == 0 {
+		return IRRResult{Iterations: iterations}, ErrNoConvergence
+	}
+
+	// Newton failed: refine the bracket closest to the guess.
+	closest := brackets[0]
+	for _, b := range brackets[1:] {
+		if b.distance(s.Guess) < closest.distance(s.Guess) {
+			closest = b
+		}
+	}
+	rate, more, err := s.refine(f, closest)
+	result := IRRResult{Rate: rate, Iterations: iterations + more, Method: "brent"}
+	if err != nil {
+		return result, ErrNoConvergence
+	}
+	result.Converged = true
+	result.Roots = []float64{rate}
+	if s.AllRoots {
+		result.Roots = mergeRoot(s.roots(f, brackets), rate, s.Tolerance)
+	}
+	return result, nil
+}
+
+// checkBounds validates the search interval.
+func (s IRRSolver) checkBounds() error {
+	if s.LowerBound <= -1 || s.UpperBound <= s.LowerBound {
+		return ErrInvalidRate
+	}
+	return nil
+}
+
+// bracket is an interval of rates on which the NPV changes sign, or a single
+// rate, when lower equals upper, at which it is zero.
+type bracket struct {
+	lower, upper float64
+}
+
+// distance returns how far rate lies from the bracket.
+func (b bracket) distance(rate float64) float64 {
+	switch {
+	case rate < b.lower:
+		return b.lower - rate
+	case rate > b.upper:
+		return rate - b.upper
+	default:
+		return 0
+	}
+}
+
+// scan looks for sign changes of f on a grid that is uniform in log(1 + rate)
+// and returns their brackets in ascending order.
+func (s IRRSolver) scan(f func(float64) float64) []bracket {
+	var brackets []bracket
+
+	lo, hi := math.Log1p(s.LowerBound), math.Log1p(s.UpperBound)
+	step := (hi - lo) / float64(s.ScanSteps)
+
+	a := s.LowerBound
+	fa := f(a)
+	for i := 1; i <= s.ScanSteps; i++ {
+		b := math.Expm1(lo + step*float64(i))
+		fb := f(b)
+
+		switch {
+		case fa == 0:
+			brackets = append(brackets, bracket{a, a})
+		case fb != 0 && (fa > 0) != (fb > 0):
+			brackets = append(brackets, bracket{a, b})
+		}
+		a, fa = b, fb
+	}
+	if fa == 0 {
+		brackets = append(brackets, bracket{a, a})
+	}
+
+	return brackets
+}
+
+// refine returns the root of f in a bracket found by scan and the number of
+// iterations of Brent's method used.
+func (s IRRSolver) refine(f func(float64) float64, b bracket) (float64, int, error) {
+	if b.lower == b.upper {
+		return b.lower, 0, nil
+	}
+	return brent(f, b.lower, b.upper, s.Tolerance, s.MaxIterations)
+}
+
+// roots refines every bracket and returns the roots found, in ascending order.
+func (s IRRSolver) roots(f func(float64) float64, brackets []bracket) []float64 {
+	var roots []float64
+	for _, b := range brackets {
+		if root, _, err := s.refine(f, b); err == nil {
+			roots = mergeRoot(roots, root, s.Tolerance)
+		}
+	}
+	return roots
+}
+
+// npvAndDerivative returns the NPV of cashFlows at rate, with cashFlows[0]
+// undiscounted, together with its derivative with respect to the rate.
+func npvAndDerivative(rate float64, cashFlows []float64) (float64, float64) {
+	discount := 1 / (1 + rate)
+	factor := 1.0
+	npv, derivative := 0.0, 0.0
+	for t, cashFlow := range cashFlows {
+		npv += cashFlow * factor
+		derivative -= float64(t) * cashFlow * factor * discount
+		factor *= discount
+	}
+	return npv, derivative
+}
+
+// hasSignChange reports whether cashFlows contains both a positive and a negative value.
+func hasSignChange(cashFlows []float64) bool {
+	positive, negative := false, false
+	for _, cashFlow := range cashFlows {
+		if cashFlow > 0 {
+			positive = true
+		} else if cashFlow < 0 {
+			negative = true
+		}
+	}
+	return positive && negative
+}
+
+// mergeRoot adds root to the sorted roots unless an equal root is already present.
+func mergeRoot(roots []float64, root, tolerance float64) []float64 {
+	for _, r := range roots {
+		if math.Abs(r-root) <= 1e3*tolerance*math.Max(1, math.Abs(root)) {
+			return roots
+		}
+	}
+	roots = append(roots, root)
+	sort.Float64s(roots)
+	return roots
+}
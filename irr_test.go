@@ -0,0 +1,130 @@
+package gofin
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIRRSolverSolve(t *testing.T) {
+	cashFlows := []float64{-1000, 300, 400, 500}
+	var expected float64 = 0.0889633947
+
+	actual, err := IRRSolver{}.Solve(cashFlows)
+	if err != nil || !actual.Converged || notWithin(actual.Rate, expected, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", expected, actual.Rate, err)
+	}
+	if len(actual.Roots) != 1 || actual.Iterations == 0 {
+		t.Errorf("Test failed, expected one root and a positive iteration count, got: %v, %d", actual.Roots, actual.Iterations)
+	}
+
+	npv, _ := npvAndDerivative(actual.Rate, cashFlows)
+	if notWithin(npv, 0, 1e-8) {
+		t.Errorf("Test failed, expected NPV at IRR to be zero, got: '%g'", npv)
+	}
+}
+
+func TestIRRSolverMultipleRoots(t *testing.T) {
+	// A mid-life outflow gives two IRRs: 10% and 20%.
+	cashFlows := []float64{-100, 230, -132}
+	expected := []float64{0.1, 0.2}
+
+	actual, err := IRRSolver{Guess: 0.19, AllRoots: true}.Solve(cashFlows)
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	if len(actual.Roots) != len(expected) {
+		t.Fatalf("Test failed, expected: '%v', got: '%v'", expected, actual.Roots)
+	}
+	for i := range expected {
+		if notWithin(actual.Roots[i], expected[i], 1e-9) {
+			t.Errorf("Test failed, expected: '%f', got: '%f'", expected[i], actual.Roots[i])
+		}
+	}
+	if notWithin(actual.Rate, 0.2, 1e-9) {
+		t.Errorf("Test failed, expected the root closest to the guess: '%f', got: '%f'", 0.2, actual.Rate)
+	}
+
+	// Without AllRoots only the root reached from the guess is reported.
+	actual, err = IRRSolver{Guess: 0.19}.Solve(cashFlows)
+	if err != nil || len(actual.Roots) != 1 || actual.Roots[0] != actual.Rate || notWithin(actual.Rate, 0.2, 1e-9) {
+		t.Errorf("Test failed, expected: '%v', got: '%v' (%v)", []float64{0.2}, actual.Roots, err)
+	}
+}
+
+func TestIRRSolverGuess(t *testing.T) {
+	// A guess of zero is kept, and here is already the root.
+	actual, err := IRRSolver{Guess: 0}.Solve([]float64{-100, 100})
+	if err != nil || actual.Rate != 0 || actual.Iterations != 1 {
+		t.Errorf("Test failed, expected: '%f' after 1 iteration, got: '%f' after %d (%v)", 0.0, actual.Rate, actual.Iterations, err)
+	}
+
+	tests := []struct {
+		solver   IRRSolver
+		expected float64
+	}{
+		{IRRSolver{}, 0},
+		{IRRSolver{Guess: -0.5}, -0.5},
+		{IRRSolver{Guess: 20}, 0.1},
+		{IRRSolver{Guess: 5, LowerBound: 0.5, UpperBound: 1}, 0.75},
+		{IRRSolver{Guess: 5, LowerBound: 2}, 5},
+		{IRRSolver{Guess: -0.5, UpperBound: 0.5}, -0.5},
+	}
+	for _, test := range tests {
+		if actual := test.solver.withDefaults().Guess; actual != test.expected {
+			t.Errorf("Test failed for %+v, expected: '%f', got: '%f'", test.solver, test.expected, actual)
+		}
+	}
+}
+
+func TestIRRSolverBrentFallback(t *testing.T) {
+	// Newton from a guess near -1 overshoots out of the bounds, so the
+	// bracketed search has to supply the root.
+	cashFlows := []float64{-1000, 100, 100, 100, 100, 1100}
+	var expected float64 = 0.1
+
+	actual, err := IRRSolver{Guess: -0.98, MaxIterations: 5}.Solve(cashFlows)
+	if err != nil || notWithin(actual.Rate, expected, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", expected, actual.Rate, err)
+	}
+	if actual.Method != "brent" {
+		t.Errorf("Test failed, expected: '%s', got: '%s'", "brent", actual.Method)
+	}
+
+	// The iterations count Brent's method as well as the failed Newton.
+	f := func(rate float64) float64 {
+		npv, _ := npvAndDerivative(rate, cashFlows)
+		return npv
+	}
+	df := func(rate float64) float64 {
+		_, d := npvAndDerivative(rate, cashFlows)
+		return d
+	}
+	if _, newtonIterations, _ := newton(f, df, -0.98, -0.99, 10, 1e-10, 5); actual.Iterations <= newtonIterations {
+		t.Errorf("Test failed, expected more than %d iterations, got: %d", newtonIterations, actual.Iterations)
+	}
+}
+
+func TestIRRSolverErrors(t *testing.T) {
+	if _, err := (IRRSolver{}).Solve([]float64{100, 100}); !errors.Is(err, ErrNoSignChange) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrNoSignChange, err)
+	}
+	if _, err := (IRRSolver{}).Solve([]float64{100}); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrEmptyInput, err)
+	}
+	// The IRR of this series is 50%, outside the requested bounds.
+	if _, err := (IRRSolver{LowerBound: 0, UpperBound: 0.2}).Solve([]float64{-100, 150}); !errors.Is(err, ErrNoConvergence) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrNoConvergence, err)
+	}
+}
+
+func TestIRRSolverOneBound(t *testing.T) {
+	// Setting only the upper bound keeps the default lower bound, so a loss
+	// is still found.
+	result, err := IRRSolver{UpperBound: 0.5}.Solve([]float64{-100, 90})
+	if err != nil || notWithin(result.Rate, -0.1, 1e-10) {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", -0.1, result.Rate, err)
+	}
+	if _, err := (IRRSolver{LowerBound: 0.2}).Solve([]float64{-100, 90}); !errors.Is(err, ErrNoConvergence) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrNoConvergence, err)
+	}
+}
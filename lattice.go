@@ -0,0 +1,241 @@
+package gofin
+
+import (
+	"fmt"
+	"math"
+)
+
+// ExerciseStyle says when an option may be exercised.
+type ExerciseStyle int
+
+const (
+	// EuropeanExercise allows exercise at expiry only.
+	EuropeanExercise ExerciseStyle = iota
+
+	// AmericanExercise allows exercise at any time up to expiry.
+	AmericanExercise
+
+	// BermudanExercise allows exercise at the option's ExerciseTimes and at expiry.
+	BermudanExercise
+)
+
+// LatticeMethod selects the tree used by a Lattice.
+type LatticeMethod int
+
+const (
+	// CoxRossRubinstein is the binomial tree with up and down moves of
+	// e^(±sigma * sqrt(dt)).
+	CoxRossRubinstein LatticeMethod = iota
+
+	// Trinomial is Boyle's trinomial tree with up, middle and down moves of
+	// e^(sigma * sqrt(2 * dt)), 1 and e^(-sigma * sqrt(2 * dt)).
+	Trinomial
+)
+
+// Dividend is a discrete cash dividend paid Time years from now.
+type Dividend struct {
+	Time   float64
+	Amount float64
+}
+
+// LatticeOption is an option valued on a Lattice. Its fields have the same
+// meaning as those of EuropeanOption, with the addition of early exercise and
+// discrete dividends.
+type LatticeOption struct {
+	Type          OptionType
+	Spot          float64
+	Strike        float64
+	Expiry        float64
+	Rate          float64
+	DividendYield float64
+	Volatility    float64
+
+	Exercise ExerciseStyle
+
+	// ExerciseTimes are the times in years at which a Bermudan option may be
+	// exercised. Each is rounded to the nearest step of the tree.
+	ExerciseTimes []float64
+
+	// Dividends are discrete cash dividends, handled with the escrowed
+	// dividend model: the tree models the spot less the present value of the
+	// dividends still to be paid before expiry, and Volatility applies to that
+	// risky part.
+	Dividends []Dividend
+}
+
+// Lattice prices options on a recombining tree.
+type Lattice struct {
+	Method LatticeMethod
+
+	// Steps is the number of time steps. Defaults to 500.
+	Steps int
+
+	// Richardson extrapolates the prices from Steps and Steps/2 steps,
+	// 2 * P(N) - P(N/2), which removes most of the first-order error of the tree.
+	// Steps is rounded up to an even number, and for CoxRossRubinstein to a
+	// multiple of 4, so that both trees have the same odd-even parity, whose
+	// oscillation would otherwise be amplified.
+	Richardson bool
+}
+
+// Price returns the value of the option. The tree discounts one step at a time
+// with PresentValue at the periodic rate equivalent to the continuously
+// compounded Rate.
+func (l Lattice) Price(o LatticeOption) (float64, error) {
+	if l.Steps == 0 {
+		l.Steps = 500
+	}
+	if l.Steps < 2 {
+		return 0.0, fmt.Errorf("%w: a lattice needs at least 2 steps", ErrInvalidPeriods)
+	}
+	if l.Method != CoxRossRubinstein && l.Method != Trinomial {
+		return 0.0, fmt.Errorf("%w: unknown lattice method %d", ErrInvalidOption, l.Method)
+	}
+	if err := o.check(); err != nil {
+		return 0.0, err
+	}
+
+	if l.Richardson {
+		multiple := 2
+		if l.Method == CoxRossRubinstein {
+			multiple = 4
+		}
+		l.Steps += (multiple - l.Steps%multiple) % multiple
+	}
+
+	fine, err := l.price(o, l.Steps)
+	if err != nil || !l.Richardson {
+		return fine, err
+	}
+	coarse, err := l.price(o, l.Steps/2)
+	if err != nil {
+		return 0.0, err
+	}
+	return 2*fine - coarse, nil
+}
+
+// price values the option on a tree of the given number of steps.
+func (l Lattice) price(o LatticeOption, steps int) (float64, error) {
+	dt := o.Expiry / float64(steps)
+	discount := PresentValue(1, math.Expm1(o.Rate*dt), 1)
+	growth := math.Exp((o.Rate - o.DividendYield) * dt)
+
+	// escrow[i] is the value at step i of the dividends paid after it.
+	escrow := make([]float64, steps+1)
+	for i := range escrow {
+		escrow[i] = o.escrowedDividends(float64(i) * dt)
+	}
+	risky := o.Spot - escrow[0]
+	if risky <= 0 {
+		return 0.0, fmt.Errorf("%w: dividends exceed the spot price", ErrInvalidOption)
+	}
+	exercise := o.exerciseSteps(steps, dt)
+
+	switch l.Method {
+	case Trinomial:
+		h := o.Volatility * math.Sqrt(dt/2)
+		up, down := math.Exp(h), math.Exp(-h)
+		a := math.Sqrt(growth)
+		pu := math.Pow((a-down)/(up-down), 2)
+		pd := math.Pow((up-a)/(up-down), 2)
+		pm := 1 - pu - pd
+		if pu <= 0 || pd <= 0 || pm <= 0 {
+			return 0.0, fmt.Errorf("%w: too few steps for a stable trinomial tree", ErrInvalidPeriods)
+		}
+		u := up * up
+
+		values := make([]float64, 2*steps+1)
+		for k := range values {
+			values[k] = o.payoff(risky*math.Pow(u, float64(k-steps)) + escrow[steps])
+		}
+		for i := steps - 1; i >= 0; i-- {
+			for k := 0; k <= 2*i; k++ {
+				v := discount * (pu*values[k+2] + pm*values[k+1] + pd*values[k])
+				if exercise[i] {
+					v = math.Max(v, o.payoff(risky*math.Pow(u, float64(k-i))+escrow[i]))
+				}
+				values[k] = v
+			}
+		}
+		return values[0], nil
+
+	default:
+		u := math.Exp(o.Volatility * math.Sqrt(dt))
+		d := 1 / u
+		p := (growth - d) / (u - d)
+		if p <= 0 || p >= 1 {
+			return 0.0, fmt.Errorf("%w: too few steps for a stable binomial tree", ErrInvalidPeriods)
+		}
+
+		values := make([]float64, steps+1)
+		for j := range values {
+			values[j] = o.payoff(risky*math.Pow(u, float64(2*j-steps)) + escrow[steps])
+		}
+		for i := steps - 1; i >= 0; i-- {
+			for j := 0; j <= i; j++ {
+				v := discount * (p*values[j+1] + (1-p)*values[j])
+				if exercise[i] {
+					v = math.Max(v, o.payoff(risky*math.Pow(u, float64(2*j-i))+escrow[i]))
+				}
+				values[j] = v
+			}
+		}
+		return values[0], nil
+	}
+}
+
+// payoff returns the exercise value of the option at spot.
+func (o LatticeOption) payoff(spot float64) float64 {
+	if o.Type == Call {
+		return math.Max(spot-o.Strike, 0)
+	}
+	return math.Max(o.Strike-spot, 0)
+}
+
+// escrowedDividends returns the value at time t of the dividends paid after t
+// and no later than expiry.
+func (o LatticeOption) escrowedDividends(t float64) float64 {
+	value := 0.0
+	for _, dividend := range o.Dividends {
+		if dividend.Time > t && dividend.Time <= o.Expiry {
+			value += PresentValue(dividend.Amount, math.Expm1(o.Rate*(dividend.Time-t)), 1)
+		}
+	}
+	return value
+}
+
+// exerciseSteps reports for each step before expiry whether the option may be
+// exercised there.
+func (o LatticeOption) exerciseSteps(steps int, dt float64) []bool {
+	exercise := make([]bool, steps)
+	switch o.Exercise {
+	case AmericanExercise:
+		for i := range exercise {
+			exercise[i] = true
+		}
+	case BermudanExercise:
+		for _, t := range o.ExerciseTimes {
+			if i := int(math.Round(t / dt)); i >= 0 && i < steps {
+				exercise[i] = true
+			}
+		}
+	}
+	return exercise
+}
+
+// check validates the option's terms.
+func (o LatticeOption) check() error {
+	european := EuropeanOption{Type: o.Type, Spot: o.Spot, Strike: o.Strike, Expiry: o.Expiry}
+	if err := european.check(o.Volatility); err != nil {
+		return err
+	}
+	if o.Exercise != EuropeanExercise && o.Exercise != AmericanExercise && o.Exercise != BermudanExercise {
+		return fmt.Errorf("%w: unknown exercise style %d", ErrInvalidOption, o.Exercise)
+	}
+	for _, dividend := range o.Dividends {
+		if dividend.Time < 0 || dividend.Amount < 0 {
+			return fmt.Errorf("%w: dividends must have a non-negative time and amount", ErrInvalidOption)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package gofin
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestLatticeEuropeanConvergesToBlackScholes(t *testing.T) {
+	for _, method := range []LatticeMethod{CoxRossRubinstein, Trinomial} {
+		for _, typ := range []OptionType{Call, Put} {
+			o := LatticeOption{Type: typ, Spot: 100, Strike: 110, Expiry: 1.5, Rate: 0.04, DividendYield: 0.02, Volatility: 0.3}
+			expected, _ := EuropeanOption{Type: typ, Spot: 100, Strike: 110, Expiry: 1.5, Rate: 0.04, DividendYield: 0.02, Volatility: 0.3}.Price()
+
+			actual, err := Lattice{Method: method, Steps: 800}.Price(o)
+			if err != nil || notWithin(actual, expected, 2e-2) {
+				t.Errorf("Test failed for method %d type %d, expected: '%f', got: '%f' (%v)", method, typ, expected, actual, err)
+			}
+
+			extrapolated, err := Lattice{Method: method, Steps: 800, Richardson: true}.Price(o)
+			if err != nil || notWithin(extrapolated, expected, 5e-3) {
+				t.Errorf("Test failed for method %d type %d, expected: '%f', got: '%f' (%v)", method, typ, expected, extrapolated, err)
+			}
+		}
+	}
+}
+
+func TestLatticeAmericanPut(t *testing.T) {
+	// Longstaff and Schwartz (2001), table 1: S = 36, K = 40, sigma = 0.2, T = 1.
+	o := LatticeOption{Type: Put, Spot: 36, Strike: 40, Expiry: 1, Rate: 0.06, Volatility: 0.2, Exercise: AmericanExercise}
+	var expected float64 = 4.4867
+
+	for _, method := range []LatticeMethod{CoxRossRubinstein, Trinomial} {
+		actual, err := Lattice{Method: method, Steps: 1000, Richardson: true}.Price(o)
+		if err != nil || notWithin(actual, expected, 2e-3) {
+			t.Errorf("Test failed for method %d, expected: '%f', got: '%f' (%v)", method, expected, actual, err)
+		}
+	}
+
+	// Early exercise is worth something for a put, and a Bermudan option lies
+	// between its European and American counterparts.
+	european := o
+	european.Exercise = EuropeanExercise
+	bermudan := o
+	bermudan.Exercise = BermudanExercise
+	bermudan.ExerciseTimes = []float64{0.25, 0.5, 0.75}
+
+	l := Lattice{Steps: 400}
+	e, _ := l.Price(european)
+	b, _ := l.Price(bermudan)
+	a, _ := l.Price(o)
+	if !(e < b && b < a) {
+		t.Errorf("Test failed, expected European < Bermudan < American, got: %f, %f, %f", e, b, a)
+	}
+}
+
+func TestLatticeAmericanCallWithoutDividends(t *testing.T) {
+	// Without dividends an American call is never exercised early.
+	o := LatticeOption{Type: Call, Spot: 50, Strike: 45, Expiry: 2, Rate: 0.05, Volatility: 0.25, Exercise: AmericanExercise}
+	american, _ := Lattice{Steps: 300}.Price(o)
+	o.Exercise = EuropeanExercise
+	european, _ := Lattice{Steps: 300}.Price(o)
+
+	if notWithin(american, european, 1e-12) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", european, american)
+	}
+}
+
+func TestLatticeDiscreteDividends(t *testing.T) {
+	// With escrowed dividends a European option is worth the Black-Scholes
+	// value on the spot less the present value of the dividends.
+	dividends := []Dividend{{Time: 0.25, Amount: 1}, {Time: 0.75, Amount: 1}, {Time: 2, Amount: 5}}
+	o := LatticeOption{Type: Call, Spot: 60, Strike: 58, Expiry: 1, Rate: 0.05, Volatility: 0.2, Dividends: dividends}
+
+	escrowed := 60 - math.Exp(-0.05*0.25) - math.Exp(-0.05*0.75)
+	expected, _ := EuropeanOption{Type: Call, Spot: escrowed, Strike: 58, Expiry: 1, Rate: 0.05, Volatility: 0.2}.Price()
+
+	actual, err := Lattice{Steps: 1000, Richardson: true}.Price(o)
+	if err != nil || notWithin(actual, expected, 5e-3) {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", expected, actual, err)
+	}
+
+	// An American call can now be worth exercising just before a dividend.
+	o.Exercise = AmericanExercise
+	american, _ := Lattice{Steps: 1000}.Price(o)
+	if american <= actual {
+		t.Errorf("Test failed, expected American call above '%f', got: '%f'", actual, american)
+	}
+}
+
+func TestLatticeRichardsonParity(t *testing.T) {
+	// Richardson extrapolation converges whatever the parity of the steps.
+	o := LatticeOption{Type: Call, Spot: 100, Strike: 100, Expiry: 1, Rate: 0.05, Volatility: 0.2}
+	expected, _ := EuropeanOption{Type: Call, Spot: 100, Strike: 100, Expiry: 1, Rate: 0.05, Volatility: 0.2}.Price()
+
+	for _, method := range []LatticeMethod{CoxRossRubinstein, Trinomial} {
+		for _, steps := range []int{101, 102, 201} {
+			plain, _ := Lattice{Method: method, Steps: steps}.Price(o)
+			actual, err := Lattice{Method: method, Steps: steps, Richardson: true}.Price(o)
+			if err != nil || notWithin(actual, expected, 1e-3) || math.Abs(actual-expected) > math.Abs(plain-expected) {
+				t.Errorf("Test failed for %d steps of method %d, expected: '%f', got: '%f' (%v)", steps, method, expected, actual, err)
+			}
+		}
+	}
+}
+
+func TestLatticeErrors(t *testing.T) {
+	o := LatticeOption{Type: Put, Spot: 36, Strike: 40, Expiry: 1, Rate: 0.06, Volatility: 0.2}
+
+	if _, err := (Lattice{Steps: 1}).Price(o); !errors.Is(err, ErrInvalidPeriods) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidPeriods, err)
+	}
+
+	o.Dividends = []Dividend{{Time: 0.5, Amount: 40}}
+	if _, err := (Lattice{}).Price(o); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidOption, err)
+	}
+}
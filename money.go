@@ -0,0 +1,75 @@
+package gofin
+
+import "fmt"
+
+// Money is an exact Decimal amount in a currency, identified by its ISO 4217
+// code such as "USD" or "EUR". Arithmetic between amounts in different
+// currencies fails with ErrCurrencyMismatch.
+type Money struct {
+	Amount   Decimal
+	Currency string
+}
+
+// NewMoney parses amount as a Decimal and returns it in currency.
+func NewMoney(amount, currency string) (Money, error) {
+	d, err := ParseDecimal(amount)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: d, Currency: currency}, nil
+}
+
+// Add returns m + o.
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	return Money{Amount: m.Amount.Add(o.Amount), Currency: m.Currency}, nil
+}
+
+// Sub returns m - o.
+func (m Money) Sub(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	return Money{Amount: m.Amount.Sub(o.Amount), Currency: m.Currency}, nil
+}
+
+// Mul returns m multiplied by factor, rounded to ctx.
+func (m Money) Mul(factor Decimal, ctx DecimalContext) Money {
+	return Money{Amount: m.Amount.Mul(factor).Round(ctx), Currency: m.Currency}
+}
+
+// Round returns m rounded to ctx, for example to two places for most currencies.
+func (m Money) Round(ctx DecimalContext) Money {
+	return Money{Amount: m.Amount.Round(ctx), Currency: m.Currency}
+}
+
+// Cmp compares m and o and returns -1, 0 or 1. It returns ErrCurrencyMismatch
+// when the currencies differ.
+func (m Money) Cmp(o Money) (int, error) {
+	if m.Currency != o.Currency {
+		return 0, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	return m.Amount.Cmp(o.Amount), nil
+}
+
+// String formats m as its amount followed by its currency code, for example "1234.50 EUR".
+func (m Money) String() string {
+	return m.Amount.String() + " " + m.Currency
+}
+
+// SumMoney adds amounts exactly. All amounts must be in the same currency.
+func SumMoney(amounts []Money) (Money, error) {
+	if len(amounts) == 0 {
+		return Money{}, ErrEmptyInput
+	}
+	total := amounts[0]
+	for _, amount := range amounts[1:] {
+		var err error
+		if total, err = total.Add(amount); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,33 @@
+package gofin
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMoney(t *testing.T) {
+	a, _ := NewMoney("10.25", "EUR")
+	b, _ := NewMoney("0.755", "EUR")
+
+	sum, err := a.Add(b)
+	if err != nil || sum.String() != "11.005 EUR" {
+		t.Errorf("Test failed, expected: '%s', got: '%s' (%v)", "11.005 EUR", sum, err)
+	}
+	if actual := sum.Round(cents); actual.String() != "11.00 EUR" {
+		t.Errorf("Test failed, expected: '%s', got: '%s'", "11.00 EUR", actual)
+	}
+	if actual := sum.Round(DecimalContext{Scale: 2, Rounding: RoundHalfUp}); actual.String() != "11.01 EUR" {
+		t.Errorf("Test failed, expected: '%s', got: '%s'", "11.01 EUR", actual)
+	}
+	if actual := a.Mul(MustParseDecimal("0.1"), cents); actual.String() != "1.02 EUR" {
+		t.Errorf("Test failed, expected: '%s', got: '%s'", "1.02 EUR", actual)
+	}
+
+	usd, _ := NewMoney("1", "USD")
+	if _, err := a.Sub(usd); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrCurrencyMismatch, err)
+	}
+	if _, err := SumMoney([]Money{a, b, usd}); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrCurrencyMismatch, err)
+	}
+}
@@ -0,0 +1,276 @@
+package gofin
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// ReturnModel draws the return of one period, for example 0.05 for 5%.
+// Implementations must only use rng for randomness so that simulations are
+// reproducible from their seed.
+type ReturnModel interface {
+	Sample(rng *rand.Rand) float64
+}
+
+// NormalReturns draws normally distributed returns. Draws below -100% are
+// clamped to -100%.
+type NormalReturns struct {
+	Mean   float64
+	StdDev float64
+}
+
+// Sample draws a return.
+func (m NormalReturns) Sample(rng *rand.Rand) float64 {
+	return math.Max(m.Mean+m.StdDev*rng.NormFloat64(), -1)
+}
+
+// check validates the model's parameters.
+func (m NormalReturns) check() error {
+	if m.StdDev < 0 {
+		return fmt.Errorf("%w: standard deviation must not be negative", ErrInvalidSimulation)
+	}
+	return nil
+}
+
+// LognormalReturns draws returns whose growth factor 1 + r is lognormal, with
+// the arithmetic mean and standard deviation of r given by Mean and StdDev.
+// Unlike NormalReturns it never loses more than 100%.
+type LognormalReturns struct {
+	Mean   float64
+	StdDev float64
+}
+
+// Sample draws a return.
+// 1 + r = e^(mu + sigma * Z)
+// sigma^2 = ln(1 + s^2 / (1 + m)^2)
+// mu = ln(1 + m) - sigma^2 / 2
+// m and s are Mean and StdDev,
+// Z is a standard normal draw.
+func (m LognormalReturns) Sample(rng *rand.Rand) float64 {
+	variance := math.Log1p(m.StdDev * m.StdDev / ((1 + m.Mean) * (1 + m.Mean)))
+	mu := math.Log1p(m.Mean) - variance/2
+	return math.Expm1(mu + math.Sqrt(variance)*rng.NormFloat64())
+}
+
+// check validates the model's parameters.
+func (m LognormalReturns) check() error {
+	if m.Mean <= -1 {
+		return fmt.Errorf("%w: mean return must be greater than -1", ErrInvalidSimulation)
+	}
+	if m.StdDev < 0 {
+		return fmt.Errorf("%w: standard deviation must not be negative", ErrInvalidSimulation)
+	}
+	return nil
+}
+
+// BootstrapReturns draws returns uniformly, with replacement, from a history
+// of observed returns.
+type BootstrapReturns struct {
+	History []float64
+}
+
+// Sample draws a return.
+func (m BootstrapReturns) Sample(rng *rand.Rand) float64 {
+	return m.History[rng.Intn(len(m.History))]
+}
+
+// check validates the history.
+func (m BootstrapReturns) check() error {
+	if len(m.History) == 0 {
+		return fmt.Errorf("%w: bootstrap history is empty", ErrInvalidSimulation)
+	}
+	for _, r := range m.History {
+		if r < -1 {
+			return fmt.Errorf("%w: bootstrap history has a return below -1", ErrInvalidSimulation)
+		}
+	}
+	return nil
+}
+
+// Simulation projects the value of an investment with contributions and
+// withdrawals under random returns.
+//
+// Each path draws its returns from its own random source, seeded from Seed
+// and the path number, so a simulation gives the same result for the same
+// Seed whatever the number of Workers.
+type Simulation struct {
+	// InitialValue is the value at the start of the first period.
+	InitialValue float64
+
+	// Periods is the number of periods to simulate.
+	Periods int
+
+	// CashFlows holds the amount added (positive) or withdrawn (negative) in
+	// each period. Periods past the end of the slice have no flow.
+	CashFlows []float64
+
+	// Timing says whether the flows happen at the beginning or the end of
+	// each period, that is before or after the period's return.
+	Timing PaymentTiming
+
+	// Returns draws the return of each period.
+	Returns ReturnModel
+
+	// Paths is the number of paths to simulate. Defaults to 10000.
+	Paths int
+
+	// Seed seeds the random sources of the paths.
+	Seed int64
+
+	// Workers is the number of goroutines used. Defaults to GOMAXPROCS.
+	Workers int
+}
+
+// PercentileBand holds the 5th, 50th and 95th percentiles of the simulated
+// values at the end of a period.
+type PercentileBand struct {
+	Period int
+	P5     float64
+	P50    float64
+	P95    float64
+}
+
+// SimulationResult is the outcome of a Simulation.
+type SimulationResult struct {
+	// Bands holds the percentile band at the end of each period.
+	Bands []PercentileBand
+
+	// FinalValues holds the value of each path at the end of the last period, in ascending order.
+	FinalValues []float64
+}
+
+// Run simulates the paths. Withdrawals never take a path below zero, and a
+// depleted path earns no returns until money is added to it again.
+func (s Simulation) Run() (SimulationResult, error) {
+	if s.Paths == 0 {
+		s.Paths = 10000
+	}
+	if s.Workers <= 0 {
+		s.Workers = runtime.GOMAXPROCS(0)
+	}
+	if s.Periods <= 0 {
+		return SimulationResult{}, ErrInvalidPeriods
+	}
+	if s.Paths < 0 {
+		return SimulationResult{}, fmt.Errorf("%w: number of paths must be positive", ErrInvalidSimulation)
+	}
+	if s.Timing != EndOfPeriod && s.Timing != BeginningOfPeriod {
+		return SimulationResult{}, ErrInvalidTiming
+	}
+	if s.Returns == nil {
+		return SimulationResult{}, fmt.Errorf("%w: no return model", ErrInvalidSimulation)
+	}
+	if c, ok := s.Returns.(interface{ check() error }); ok {
+		if err := c.check(); err != nil {
+			return SimulationResult{}, err
+		}
+	}
+
+	// values[t][i] is the value of path i at the end of period t+1.
+	values := make([][]float64, s.Periods)
+	for t := range values {
+		values[t] = make([]float64, s.Paths)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < s.Workers; w++ {
+		wg.Add(1)
+		go func(first int) {
+			defer wg.Done()
+			for i := first; i < s.Paths; i += s.Workers {
+				s.runPath(i, values)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	result := SimulationResult{Bands: make([]PercentileBand, s.Periods)}
+	for t, periodValues := range values {
+		sort.Float64s(periodValues)
+		result.Bands[t] = PercentileBand{
+			Period: t + 1,
+			P5:     percentile(periodValues, 0.05),
+			P50:    percentile(periodValues, 0.5),
+			P95:    percentile(periodValues, 0.95),
+		}
+	}
+	result.FinalValues = values[s.Periods-1]
+	return result, nil
+}
+
+// runPath simulates path i and stores its value at the end of each period.
+func (s Simulation) runPath(i int, values [][]float64) {
+	rng := rand.New(rand.NewSource(pathSeed(s.Seed, i)))
+	value := s.InitialValue
+	for t := 0; t < s.Periods; t++ {
+		flow := 0.0
+		if t < len(s.CashFlows) {
+			flow = s.CashFlows[t]
+		}
+
+		if s.Timing == BeginningOfPeriod {
+			value += flow
+		}
+		r := s.Returns.Sample(rng)
+		if value > 0 {
+			value *= 1 + r
+		}
+		if s.Timing == EndOfPeriod {
+			value += flow
+		}
+		if value < 0 {
+			value = 0
+		}
+
+		values[t][i] = value
+	}
+}
+
+// Percentile returns the p-th quantile of the final values, for p between 0
+// and 1, interpolating linearly between paths.
+func (r SimulationResult) Percentile(p float64) float64 {
+	return percentile(r.FinalValues, p)
+}
+
+// ProbabilityAbove returns the fraction of paths that end above target.
+func (r SimulationResult) ProbabilityAbove(target float64) float64 {
+	if len(r.FinalValues) == 0 {
+		return 0.0
+	}
+	below := sort.Search(len(r.FinalValues), func(i int) bool { return r.FinalValues[i] > target })
+	return float64(len(r.FinalValues)-below) / float64(len(r.FinalValues))
+}
+
+// ProbabilityDepleted returns the fraction of paths that end with nothing left.
+func (r SimulationResult) ProbabilityDepleted() float64 {
+	return 1 - r.ProbabilityAbove(0)
+}
+
+// percentile returns the p-th quantile of sorted values, interpolating
+// linearly between the closest ranks.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0.0
+	}
+	p = math.Min(math.Max(p, 0), 1)
+	rank := p * float64(len(sorted)-1)
+	lower := int(rank)
+	if lower+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	fraction := rank - float64(lower)
+	return sorted[lower] + fraction*(sorted[lower+1]-sorted[lower])
+}
+
+// pathSeed derives the seed of path i from the simulation seed with the
+// SplitMix64 mixing function, so that neighbouring paths get unrelated streams.
+func pathSeed(seed int64, i int) int64 {
+	z := uint64(seed) + uint64(i+1)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return int64(z ^ (z >> 31))
+}
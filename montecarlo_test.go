@@ -0,0 +1,143 @@
+package gofin
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestSimulationDeterministicReturns(t *testing.T) {
+	// With no volatility every path follows the time value of money.
+	for _, timing := range []PaymentTiming{EndOfPeriod, BeginningOfPeriod} {
+		s := Simulation{
+			InitialValue: 1000,
+			Periods:      10,
+			CashFlows:    []float64{100, 100, 100, 100, 100, 100, 100, 100, 100, 100},
+			Timing:       timing,
+			Returns:      NormalReturns{Mean: 0.05},
+			Paths:        50,
+		}
+		result, err := s.Run()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected, _ := FV(0.05, 10, -100, -1000, timing)
+		final := result.Bands[9]
+		if notWithin(final.P5, expected, 1e-9) || notWithin(final.P95, expected, 1e-9) {
+			t.Errorf("Test failed for timing %d, expected: '%f', got: %+v", timing, expected, final)
+		}
+		if result.ProbabilityAbove(expected-1) != 1 || result.ProbabilityAbove(expected+1) != 0 {
+			t.Errorf("Test failed for timing %d, got: %f and %f", timing, result.ProbabilityAbove(expected-1), result.ProbabilityAbove(expected+1))
+		}
+	}
+}
+
+func TestSimulationReproducible(t *testing.T) {
+	s := Simulation{
+		InitialValue: 500000,
+		Periods:      30,
+		CashFlows:    repeat(-30000, 30),
+		Returns:      LognormalReturns{Mean: 0.06, StdDev: 0.15},
+		Paths:        2000,
+		Seed:         42,
+		Workers:      1,
+	}
+	serial, err := s.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.Workers = 7
+	parallel, err := s.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range serial.FinalValues {
+		if serial.FinalValues[i] != parallel.FinalValues[i] {
+			t.Fatalf("Test failed, path %d differs: '%f' and '%f'", i, serial.FinalValues[i], parallel.FinalValues[i])
+		}
+	}
+
+	s.Seed = 43
+	other, _ := s.Run()
+	if other.Percentile(0.5) == serial.Percentile(0.5) {
+		t.Errorf("Test failed, expected a different median for a different seed")
+	}
+
+	band := serial.Bands[29]
+	if !(band.P5 <= band.P50 && band.P50 <= band.P95) {
+		t.Errorf("Test failed, bands out of order: %+v", band)
+	}
+	if p := serial.ProbabilityDepleted(); p <= 0 || p >= 1 {
+		t.Errorf("Test failed, expected some but not all paths depleted, got: %f", p)
+	}
+}
+
+func TestLognormalReturnsMoments(t *testing.T) {
+	s := Simulation{InitialValue: 1, Periods: 1, Returns: LognormalReturns{Mean: 0.07, StdDev: 0.2}, Paths: 200000, Seed: 1}
+	result, err := s.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mean, variance := 0.0, 0.0
+	for _, v := range result.FinalValues {
+		mean += v - 1
+	}
+	mean /= float64(len(result.FinalValues))
+	for _, v := range result.FinalValues {
+		variance += (v - 1 - mean) * (v - 1 - mean)
+	}
+	variance /= float64(len(result.FinalValues) - 1)
+
+	if notWithin(mean, 0.07, 2e-3) || notWithin(math.Sqrt(variance), 0.2, 2e-3) {
+		t.Errorf("Test failed, expected mean 0.07 and deviation 0.2, got: '%f' and '%f'", mean, math.Sqrt(variance))
+	}
+}
+
+func TestBootstrapReturns(t *testing.T) {
+	history := []float64{-0.1, 0.2}
+	s := Simulation{InitialValue: 100, Periods: 3, Returns: BootstrapReturns{History: history}, Paths: 1000, Seed: 7}
+	result, err := s.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every final value is one of the four combinations of the history.
+	allowed := []float64{100 * 0.9 * 0.9 * 0.9, 100 * 0.9 * 0.9 * 1.2, 100 * 0.9 * 1.2 * 1.2, 100 * 1.2 * 1.2 * 1.2}
+	for _, v := range result.FinalValues {
+		found := false
+		for _, a := range allowed {
+			if !notWithin(v, a, 1e-9) {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("Test failed, unexpected final value '%f'", v)
+		}
+	}
+	if result.FinalValues[0] == result.FinalValues[len(result.FinalValues)-1] {
+		t.Errorf("Test failed, expected the paths to differ")
+	}
+}
+
+func TestSimulationErrors(t *testing.T) {
+	if _, err := (Simulation{Periods: 10}).Run(); !errors.Is(err, ErrInvalidSimulation) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidSimulation, err)
+	}
+	if _, err := (Simulation{Periods: 10, Returns: BootstrapReturns{}}).Run(); !errors.Is(err, ErrInvalidSimulation) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidSimulation, err)
+	}
+	if _, err := (Simulation{Returns: NormalReturns{}}).Run(); !errors.Is(err, ErrInvalidPeriods) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidPeriods, err)
+	}
+}
+
+func repeat(value float64, n int) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = value
+	}
+	return values
+}
@@ -0,0 +1,45 @@
+package mortgage
+
+import (
+	"math"
+
+	"github.com/lazarospsa/gofin"
+)
+
+// APR returns the annual percentage rate of the mortgage by the actuarial
+// method of Regulation Z, Appendix J: the monthly rate at which the payments
+// of principal, interest and PMI, all finance charges, discount to the amount
+// financed, times 12. The amount financed is the principal less the prepaid
+// finance charges; escrow is not a finance charge and is left out. An ARM is
+// disclosed at the index rates given. The monthly rate is found with
+// gofin.IRRSolver.
+func (m Mortgage) APR() (float64, error) {
+	s, err := m.Schedule()
+	if err != nil {
+		return 0.0, err
+	}
+
+	cashFlows := make([]float64, len(s.Payments)+1)
+	cashFlows[0] = -(m.Principal - m.FinanceCharges)
+	for i, p := range s.Payments {
+		cashFlows[i+1] = p.PrincipalAndInterest + p.PMI
+	}
+
+	result, err := gofin.IRRSolver{Guess: m.Rate / 12}.Solve(cashFlows)
+	if err != nil {
+		return 0.0, err
+	}
+	return result.Rate * 12, nil
+}
+
+// WithinTolerance reports whether a disclosed APR is accurate under
+// Regulation Z: within 1/8 of a percentage point of the actual APR for a
+// regular transaction, or 1/4 of a point for an irregular one, such as a loan
+// with irregular payments or periods.
+func WithinTolerance(disclosed, actual float64, irregular bool) bool {
+	tolerance := 0.00125
+	if irregular {
+		tolerance = 0.0025
+	}
+	return math.Abs(disclosed-actual) <= tolerance+1e-12
+}
@@ -0,0 +1,91 @@
+package mortgage
+
+import (
+	"fmt"
+	"math"
+)
+
+// ARM holds the adjustment terms of an adjustable rate mortgage, such as a
+// 5/1 ARM with 2/2/5 caps: five years at the initial rate, then yearly resets
+// of at most 2 points, up to 5 points above the initial rate. Caps of zero
+// leave the change unlimited.
+type ARM struct {
+	// InitialMonths is the number of months at the initial rate, for example
+	// 60 for a 5/1 ARM.
+	InitialMonths int
+
+	// ResetMonths is the number of months between later resets, for example
+	// 12 for a 5/1 ARM and 6 for a 5/6 ARM.
+	ResetMonths int
+
+	// Index holds the index rate, such as SOFR, at each reset in turn. The
+	// last one holds for any later resets.
+	Index []float64
+
+	// Margin is added to the index to give the fully indexed rate.
+	Margin float64
+
+	// Rounding rounds the fully indexed rate to the nearest multiple, for
+	// example 0.00125 for an eighth of a point. Zero leaves it unrounded.
+	Rounding float64
+
+	// InitialCap limits the change at the first reset. Defaults to PeriodicCap.
+	InitialCap float64
+
+	// PeriodicCap limits the change at each later reset.
+	PeriodicCap float64
+
+	// LifetimeCap limits the rate to the initial rate plus the cap.
+	LifetimeCap float64
+
+	// Floor is the lowest rate, often the margin. The rate never falls below
+	// zero.
+	Floor float64
+}
+
+// validate checks the terms against the term of the loan.
+func (a ARM) validate(months int) error {
+	switch {
+	case a.InitialMonths <= 0 || a.InitialMonths >= months:
+		return fmt.Errorf("%w: ARM initial months must be positive and shorter than the term", ErrInvalidMortgage)
+	case a.ResetMonths <= 0:
+		return fmt.Errorf("%w: ARM reset months must be positive", ErrInvalidMortgage)
+	case len(a.Index) == 0:
+		return fmt.Errorf("%w: ARM needs an index rate", ErrInvalidMortgage)
+	case a.Rounding < 0 || a.InitialCap < 0 || a.PeriodicCap < 0 || a.LifetimeCap < 0:
+		return fmt.Errorf("%w: ARM rounding and caps must not be negative", ErrInvalidMortgage)
+	}
+	return nil
+}
+
+// resets reports whether the rate resets at the start of a month, counting
+// from 1.
+func (a ARM) resets(month int) bool {
+	elapsed := month - 1 - a.InitialMonths
+	return elapsed >= 0 && elapsed%a.ResetMonths == 0
+}
+
+// rate returns the rate after a reset, counting from 0, given the current
+// and initial rates.
+func (a ARM) rate(reset int, current, initial float64) float64 {
+	index := a.Index[len(a.Index)-1]
+	if reset < len(a.Index) {
+		index = a.Index[reset]
+	}
+	rate := index + a.Margin
+	if a.Rounding > 0 {
+		rate = math.Round(rate/a.Rounding) * a.Rounding
+	}
+
+	limit := a.PeriodicCap
+	if reset == 0 && a.InitialCap > 0 {
+		limit = a.InitialCap
+	}
+	if limit > 0 {
+		rate = math.Max(current-limit, math.Min(current+limit, rate))
+	}
+	if a.LifetimeCap > 0 {
+		rate = math.Min(rate, initial+a.LifetimeCap)
+	}
+	return math.Max(rate, math.Max(a.Floor, 0))
+}
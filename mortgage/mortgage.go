@@ -0,0 +1,216 @@
+// Package mortgage builds monthly payment schedules for residential mortgages:
+// escrowed property taxes and insurance, private mortgage insurance that
+// terminates at the Homeowners Protection Act loan-to-value thresholds,
+// adjustable rates that reset to an index plus a margin within caps, and the
+// annual percentage rate of Regulation Z.
+package mortgage
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/lazarospsa/gofin"
+)
+
+// ErrInvalidMortgage is returned for a mortgage with a non-positive amount or
+// term, a rate at or below -100%, or inconsistent escrow, PMI or ARM terms.
+var ErrInvalidMortgage = errors.New("mortgage: invalid mortgage")
+
+// Mortgage describes a loan repaid in monthly installments.
+type Mortgage struct {
+	// Principal is the amount borrowed.
+	Principal float64
+
+	// Rate is the annual note rate, compounded monthly. For an adjustable
+	// rate mortgage it is the initial rate.
+	Rate float64
+
+	// Months is the term of the loan.
+	Months int
+
+	// PropertyValue is the original value of the property, the lesser of its
+	// sale price and appraised value. It is required for PMI and the
+	// loan-to-value ratios of the schedule.
+	PropertyValue float64
+
+	Escrow Escrow
+	PMI    PMI
+
+	// ARM holds the adjustment terms of an adjustable rate mortgage, or nil
+	// for a fixed rate.
+	ARM *ARM
+
+	// FinanceCharges are the prepaid finance charges, such as points and
+	// origination fees, paid at closing. They reduce the amount financed of
+	// the APR.
+	FinanceCharges float64
+}
+
+// Escrow holds the annual property taxes and insurance collected with each
+// payment, one twelfth at a time.
+type Escrow struct {
+	PropertyTax float64
+	Insurance   float64
+
+	// HOA is the annual homeowners association dues, when escrowed.
+	HOA float64
+
+	// AnnualIncrease is the rate at which the escrowed amounts grow at the
+	// start of each year of the loan after the first.
+	AnnualIncrease float64
+}
+
+// monthly returns the escrow payment in the given month, counting from 1.
+func (e Escrow) monthly(month int) float64 {
+	annual := e.PropertyTax + e.Insurance + e.HOA
+	return gofin.FutureValue(annual, e.AnnualIncrease, (month-1)/12) / 12
+}
+
+// PMI describes private mortgage insurance.
+type PMI struct {
+	// AnnualRate is the yearly premium as a fraction of the original
+	// principal, for example 0.005. Zero means no PMI.
+	AnnualRate float64
+
+	// BorrowerRequest cancels PMI once the balance falls to 80% of the
+	// property value, as the borrower may request. Otherwise it terminates
+	// automatically at 78%. Either way it ends after the midpoint of the term.
+	BorrowerRequest bool
+}
+
+// threshold returns the loan-to-value ratio at which PMI stops.
+func (p PMI) threshold() float64 {
+	if p.BorrowerRequest {
+		return 0.80
+	}
+	return 0.78
+}
+
+// Payment is one month of a mortgage schedule.
+type Payment struct {
+	Month int
+
+	// Rate is the annual note rate in effect for the month.
+	Rate float64
+
+	// PrincipalAndInterest is the scheduled installment, Interest plus
+	// Principal.
+	PrincipalAndInterest float64
+	Interest             float64
+	Principal            float64
+
+	Escrow float64
+	PMI    float64
+
+	// Total is the whole monthly payment, including escrow and PMI.
+	Total float64
+
+	// Balance is the principal outstanding after the payment.
+	Balance float64
+
+	// LTV is the balance over the property value, or 0 without a value.
+	LTV float64
+}
+
+// Schedule is the payment schedule of a mortgage with its totals.
+type Schedule struct {
+	Payments []Payment
+
+	TotalInterest float64
+	TotalEscrow   float64
+	TotalPMI      float64
+
+	// PMIMonths is the number of months in which PMI is charged.
+	PMIMonths int
+}
+
+// Schedule returns the monthly payments of the mortgage. The installment is
+// level until an ARM reset, which re-amortizes the balance over the remaining
+// term at the new rate. PMI is charged in a month while the balance at its
+// start exceeds the cancellation threshold of the property value; since the
+// schedule has no prepayments this is the amortization schedule then in
+// effect, as the Homeowners Protection Act requires.
+func (m Mortgage) Schedule() (Schedule, error) {
+	if err := m.validate(); err != nil {
+		return Schedule{}, err
+	}
+
+	var s Schedule
+	s.Payments = make([]Payment, 0, m.Months)
+	balance, rate := m.Principal, m.Rate
+	installment := level(balance, rate/12, m.Months)
+	premium := m.PMI.AnnualRate * m.Principal / 12
+	reset := 0
+	for month := 1; month <= m.Months; month++ {
+		if m.ARM != nil && m.ARM.resets(month) {
+			rate = m.ARM.rate(reset, rate, m.Rate)
+			installment = level(balance, rate/12, m.Months-month+1)
+			reset++
+		}
+
+		p := Payment{Month: month, Rate: rate, Interest: balance * rate / 12, Escrow: m.Escrow.monthly(month)}
+		p.Principal = installment - p.Interest
+		if month == m.Months {
+			p.Principal = balance
+		}
+		p.PrincipalAndInterest = p.Interest + p.Principal
+
+		if premium > 0 && month <= (m.Months+1)/2 && balance > m.PMI.threshold()*m.PropertyValue {
+			p.PMI = premium
+			s.PMIMonths++
+		}
+
+		balance -= p.Principal
+		if month == m.Months || math.Abs(balance) < m.Principal*1e-12 {
+			balance = 0
+		}
+		p.Balance = balance
+		if m.PropertyValue > 0 {
+			p.LTV = balance / m.PropertyValue
+		}
+		p.Total = p.PrincipalAndInterest + p.Escrow + p.PMI
+
+		s.TotalInterest += p.Interest
+		s.TotalEscrow += p.Escrow
+		s.TotalPMI += p.PMI
+		s.Payments = append(s.Payments, p)
+	}
+	return s, nil
+}
+
+// validate checks that the mortgage is consistent.
+func (m Mortgage) validate() error {
+	switch {
+	case m.Principal <= 0:
+		return fmt.Errorf("%w: principal must be positive", ErrInvalidMortgage)
+	case m.Rate <= -1:
+		return gofin.ErrInvalidRate
+	case m.Months <= 0:
+		return gofin.ErrInvalidPeriods
+	case m.PropertyValue < 0:
+		return fmt.Errorf("%w: property value must not be negative", ErrInvalidMortgage)
+	case m.PMI.AnnualRate < 0:
+		return fmt.Errorf("%w: PMI rate must not be negative", ErrInvalidMortgage)
+	case m.PMI.AnnualRate > 0 && m.PropertyValue == 0:
+		return fmt.Errorf("%w: PMI needs the property value", ErrInvalidMortgage)
+	case m.Escrow.PropertyTax < 0 || m.Escrow.Insurance < 0 || m.Escrow.HOA < 0 || m.Escrow.AnnualIncrease <= -1:
+		return fmt.Errorf("%w: invalid escrow", ErrInvalidMortgage)
+	case m.FinanceCharges < 0 || m.FinanceCharges >= m.Principal:
+		return fmt.Errorf("%w: finance charges must be between zero and the principal", ErrInvalidMortgage)
+	}
+	if m.ARM != nil {
+		return m.ARM.validate(m.Months)
+	}
+	return nil
+}
+
+// level returns the installment that repays balance over periods at a
+// monthly rate, the balance over the present value of 1 a month.
+// PMT = B * r / (1 - 1 / (1 + r)^n)
+func level(balance, rate float64, periods int) float64 {
+	if rate == 0 {
+		return balance / float64(periods)
+	}
+	return balance * rate / (1 - gofin.PresentValue(1, rate, periods))
+}
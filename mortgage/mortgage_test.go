@@ -0,0 +1,189 @@
+package mortgage
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/lazarospsa/gofin"
+)
+
+func notWithin(a, b, tolerance float64) bool {
+	return !(math.Abs(a-b) <= tolerance)
+}
+
+func TestSchedule(t *testing.T) {
+	m := Mortgage{
+		Principal:     300000,
+		Rate:          0.065,
+		Months:        360,
+		PropertyValue: 350000,
+		Escrow:        Escrow{PropertyTax: 4200, Insurance: 1200, AnnualIncrease: 0.03},
+		PMI:           PMI{AnnualRate: 0.005},
+	}
+	s, err := m.Schedule()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Payments) != 360 {
+		t.Fatalf("Test failed, expected: %d payments, got: %d", 360, len(s.Payments))
+	}
+
+	first := s.Payments[0]
+	if notWithin(first.PrincipalAndInterest, 1896.2040704788958, 1e-8) || first.Interest != 1625 || first.Escrow != 450 || first.PMI != 125 {
+		t.Errorf("Test failed, got: %+v", first)
+	}
+	if notWithin(first.Total, 1896.2040704788958+450+125, 1e-8) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", 1896.2040704788958+575, first.Total)
+	}
+	if s.Payments[12].Escrow != 450*1.03 || s.Payments[11].Escrow != 450 {
+		t.Errorf("Test failed, expected the escrow to rise in month 13, got: '%f' and '%f'", s.Payments[11].Escrow, s.Payments[12].Escrow)
+	}
+	if last := s.Payments[359]; last.Balance != 0 || notWithin(last.PrincipalAndInterest, first.PrincipalAndInterest, 1e-6) {
+		t.Errorf("Test failed, got: %+v", last)
+	}
+
+	// PMI stops once the balance reaches 78% of the value.
+	if s.PMIMonths != 80 || s.Payments[79].PMI != 125 || s.Payments[80].PMI != 0 || s.Payments[79].LTV > 0.78 || s.Payments[78].LTV <= 0.78 {
+		t.Errorf("Test failed, expected PMI for 80 months, got: %d", s.PMIMonths)
+	}
+	if notWithin(s.TotalPMI, 80*125, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", 80*125.0, s.TotalPMI)
+	}
+
+	m.PMI.BorrowerRequest = true
+	if s, _ := m.Schedule(); s.PMIMonths != 63 {
+		t.Errorf("Test failed, expected PMI for 63 months, got: %d", s.PMIMonths)
+	}
+
+	// PMI ends at the midpoint of the term whatever the balance.
+	m.PMI.BorrowerRequest = false
+	m.PropertyValue = 250000
+	if s, _ := m.Schedule(); s.PMIMonths != 180 {
+		t.Errorf("Test failed, expected PMI for 180 months, got: %d", s.PMIMonths)
+	}
+
+	zero, err := Mortgage{Principal: 1200, Months: 12}.Schedule()
+	if err != nil || zero.Payments[0].PrincipalAndInterest != 100 || zero.TotalInterest != 0 || zero.Payments[0].LTV != 0 {
+		t.Errorf("Test failed, got: %+v (%v)", zero.Payments[0], err)
+	}
+}
+
+func TestARM(t *testing.T) {
+	m := Mortgage{
+		Principal: 300000,
+		Rate:      0.05,
+		Months:    360,
+		ARM: &ARM{
+			InitialMonths: 60,
+			ResetMonths:   12,
+			Index:         []float64{0.0413, 0.05, 0.01, 0.2},
+			Margin:        0.0275,
+			Rounding:      0.00125,
+			InitialCap:    2 * 0.01,
+			PeriodicCap:   2 * 0.01,
+			LifetimeCap:   5 * 0.01,
+			Floor:         0.0275,
+		},
+	}
+	s, err := m.Schedule()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		month int
+		rate  float64
+	}{
+		{1, 0.05},
+		{60, 0.05},
+		{61, 0.06875}, // 4.13% + 2.75% rounded to an eighth
+		{72, 0.06875},
+		{73, 0.0775},  // 5% + 2.75%
+		{85, 0.0575},  // 1% + 2.75%, limited by the periodic cap
+		{97, 0.0775},  // 20% + 2.75%, limited by the periodic cap
+		{109, 0.0975}, // limited by the periodic cap
+		{121, 0.1},    // limited by the lifetime cap
+		{360, 0.1},
+	}
+	for _, test := range tests {
+		if p := s.Payments[test.month-1]; notWithin(p.Rate, test.rate, 1e-12) {
+			t.Errorf("Test failed for month %d, expected: '%f', got: '%f'", test.month, test.rate, p.Rate)
+		}
+	}
+
+	// A reset re-amortizes the balance over the remaining term.
+	balance := s.Payments[59].Balance
+	expected, _ := gofin.PMT(0.06875/12, 300, -balance, 0, gofin.EndOfPeriod)
+	if p := s.Payments[60]; notWithin(p.PrincipalAndInterest, expected, 1e-8) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", expected, p.PrincipalAndInterest)
+	}
+	if s.Payments[359].Balance != 0 {
+		t.Errorf("Test failed, expected the loan to be repaid, got: '%f'", s.Payments[359].Balance)
+	}
+
+	// The floor holds the rate up when the index falls.
+	m.ARM = &ARM{InitialMonths: 12, ResetMonths: 6, Index: []float64{-0.01}, Margin: 0.01, Floor: 0.02}
+	s, _ = m.Schedule()
+	if s.Payments[12].Rate != 0.02 || s.Payments[11].Rate != 0.05 {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", 0.02, s.Payments[12].Rate)
+	}
+}
+
+func TestAPR(t *testing.T) {
+	m := Mortgage{
+		Principal:      300000,
+		Rate:           0.065,
+		Months:         360,
+		PropertyValue:  350000,
+		Escrow:         Escrow{PropertyTax: 4200, Insurance: 1200},
+		PMI:            PMI{AnnualRate: 0.005},
+		FinanceCharges: 3000,
+	}
+	apr, err := m.APR()
+	if err != nil || notWithin(apr, 0.06863206845176384, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", 0.06863206845176384, apr, err)
+	}
+
+	// Without finance charges or PMI the APR is the note rate.
+	apr, err = Mortgage{Principal: 200000, Rate: 0.06, Months: 360, Escrow: Escrow{PropertyTax: 3000}}.APR()
+	if err != nil || notWithin(apr, 0.06, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", 0.06, apr, err)
+	}
+
+	if !WithinTolerance(0.0686, 0.06863206845176384, false) || WithinTolerance(0.0672, 0.0686, false) || !WithinTolerance(0.0665, 0.0686, true) {
+		t.Errorf("Test failed, unexpected APR tolerance")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := Mortgage{Principal: 100000, Rate: 0.05, Months: 360}
+	tests := []struct {
+		change   func(m *Mortgage)
+		expected error
+	}{
+		{func(m *Mortgage) { m.Principal = 0 }, ErrInvalidMortgage},
+		{func(m *Mortgage) { m.Rate = -1 }, gofin.ErrInvalidRate},
+		{func(m *Mortgage) { m.Months = 0 }, gofin.ErrInvalidPeriods},
+		{func(m *Mortgage) { m.PMI.AnnualRate = 0.005 }, ErrInvalidMortgage},
+		{func(m *Mortgage) { m.Escrow.Insurance = -1 }, ErrInvalidMortgage},
+		{func(m *Mortgage) { m.FinanceCharges = 100000 }, ErrInvalidMortgage},
+		{func(m *Mortgage) { m.ARM = &ARM{InitialMonths: 360, ResetMonths: 12, Index: []float64{0.04}} }, ErrInvalidMortgage},
+		{func(m *Mortgage) { m.ARM = &ARM{InitialMonths: 60, Index: []float64{0.04}} }, ErrInvalidMortgage},
+		{func(m *Mortgage) { m.ARM = &ARM{InitialMonths: 60, ResetMonths: 12} }, ErrInvalidMortgage},
+		{func(m *Mortgage) {
+			m.ARM = &ARM{InitialMonths: 60, ResetMonths: 12, Index: []float64{0.04}, PeriodicCap: -0.01}
+		}, ErrInvalidMortgage},
+	}
+
+	for i, test := range tests {
+		m := valid
+		test.change(&m)
+		if _, err := m.Schedule(); !errors.Is(err, test.expected) {
+			t.Errorf("Test %d failed, expected: '%v', got: '%v'", i, test.expected, err)
+		}
+		if _, err := m.APR(); !errors.Is(err, test.expected) {
+			t.Errorf("Test %d failed, expected: '%v', got: '%v'", i, test.expected, err)
+		}
+	}
+}
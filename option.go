@@ -0,0 +1,183 @@
+package gofin
+
+import (
+	"fmt"
+	"math"
+)
+
+// OptionType says whether an option is a call or a put.
+type OptionType int
+
+const (
+	// Call is the right to buy the underlying at the strike.
+	Call OptionType = iota
+
+	// Put is the right to sell the underlying at the strike.
+	Put
+)
+
+// EuropeanOption is an option that can only be exercised at expiry, priced
+// under Black-Scholes-Merton with a continuous dividend yield.
+type EuropeanOption struct {
+	Type OptionType
+
+	// Spot is the current price of the underlying and Strike the exercise price.
+	Spot   float64
+	Strike float64
+
+	// Expiry is the time to expiry in years.
+	Expiry float64
+
+	// Rate is the continuously compounded risk-free rate and DividendYield
+	// the continuous dividend yield of the underlying, both annual.
+	Rate          float64
+	DividendYield float64
+
+	// Volatility is the annual volatility of the underlying's returns. It is
+	// ignored by ImpliedVolatility.
+	Volatility float64
+}
+
+// Greeks are the sensitivities of an option's price. Vega and Rho are per
+// unit change (1.00 = 100%) of volatility and rate, and Theta is the change
+// in price per year as time passes.
+type Greeks struct {
+	Delta float64
+	Gamma float64
+	Vega  float64
+	Theta float64
+	Rho   float64
+}
+
+// Price returns the Black-Scholes-Merton value of the option.
+// C = S * e^(-qT) * N(d1) - K * e^(-rT) * N(d2)
+// P = K * e^(-rT) * N(-d2) - S * e^(-qT) * N(-d1)
+// d1 = (ln(S / K) + (r - q + sigma^2 / 2) * T) / (sigma * sqrt(T))
+// d2 = d1 - sigma * sqrt(T)
+// S is the spot price,
+// K is the strike,
+// T is the time to expiry in years,
+// r is the risk-free rate,
+// q is the dividend yield,
+// sigma is the volatility,
+// N is the standard normal cumulative distribution function.
+func (o EuropeanOption) Price() (float64, error) {
+	if err := o.check(o.Volatility); err != nil {
+		return 0.0, err
+	}
+	return o.price(o.Volatility), nil
+}
+
+// Greeks returns the analytic Black-Scholes-Merton sensitivities of the option.
+func (o EuropeanOption) Greeks() (Greeks, error) {
+	if err := o.check(o.Volatility); err != nil {
+		return Greeks{}, err
+	}
+
+	d1, d2 := o.d1d2(o.Volatility)
+	sqrtT := math.Sqrt(o.Expiry)
+	spotDiscount, strikeDiscount := math.Exp(-o.DividendYield*o.Expiry), math.Exp(-o.Rate*o.Expiry)
+	density := normalPDF(d1)
+
+	g := Greeks{
+		Gamma: spotDiscount * density / (o.Spot * o.Volatility * sqrtT),
+		Vega:  o.Spot * spotDiscount * density * sqrtT,
+	}
+	decay := -o.Spot * spotDiscount * density * o.Volatility / (2 * sqrtT)
+	if o.Type == Call {
+		g.Delta = spotDiscount * normalCDF(d1)
+		g.Theta = decay - o.Rate*o.Strike*strikeDiscount*normalCDF(d2) + o.DividendYield*o.Spot*spotDiscount*normalCDF(d1)
+		g.Rho = o.Strike * o.Expiry * strikeDiscount * normalCDF(d2)
+	} else {
+		g.Delta = -spotDiscount * normalCDF(-d1)
+		g.Theta = decay + o.Rate*o.Strike*strikeDiscount*normalCDF(-d2) - o.DividendYield*o.Spot*spotDiscount*normalCDF(-d1)
+		g.Rho = -o.Strike * o.Expiry * strikeDiscount * normalCDF(-d2)
+	}
+	return g, nil
+}
+
+// ImpliedVolatility returns the volatility at which the option's
+// Black-Scholes-Merton value equals price. It returns ErrNoSolution when the
+// price lies outside the no-arbitrage bounds of the option.
+func (o EuropeanOption) ImpliedVolatility(price float64) (float64, error) {
+	if err := o.check(1); err != nil {
+		return 0.0, err
+	}
+
+	forwardSpot := o.Spot * math.Exp(-o.DividendYield*o.Expiry)
+	forwardStrike := o.Strike * math.Exp(-o.Rate*o.Expiry)
+	lower, upper := math.Max(0, forwardSpot-forwardStrike), forwardSpot
+	if o.Type == Put {
+		lower, upper = math.Max(0, forwardStrike-forwardSpot), forwardStrike
+	}
+	if price <= lower || price >= upper {
+		return 0.0, fmt.Errorf("%w: price %g is outside (%g, %g)", ErrNoSolution, price, lower, upper)
+	}
+
+	const tolerance, maxIterations = 1e-12, 100
+	const minVolatility, maxVolatility = 1e-9, 10.0
+	f := func(volatility float64) float64 { return o.price(volatility) - price }
+	vega := func(volatility float64) float64 {
+		d1, _ := o.d1d2(volatility)
+		return forwardSpot * normalPDF(d1) * math.Sqrt(o.Expiry)
+	}
+
+	// Start Newton at the volatility that maximizes vega, from which the
+	// iteration converges monotonically for most prices.
+	guess := math.Sqrt(2 * math.Abs(math.Log(forwardSpot/forwardStrike)) / o.Expiry)
+	if guess < 0.1 {
+		guess = 0.1
+	}
+	if volatility, _, err := newton(f, vega, guess, 0, maxVolatility, tolerance, maxIterations); err == nil {
+		return volatility, nil
+	}
+
+	volatility, _, err := brent(f, minVolatility, maxVolatility, tolerance, maxIterations)
+	if err != nil {
+		return 0.0, fmt.Errorf("%w: no volatility up to %g matches the price", ErrNoSolution, maxVolatility)
+	}
+	return volatility, nil
+}
+
+// price returns the option value at the given volatility.
+func (o EuropeanOption) price(volatility float64) float64 {
+	d1, d2 := o.d1d2(volatility)
+	spot := o.Spot * math.Exp(-o.DividendYield*o.Expiry)
+	strike := o.Strike * math.Exp(-o.Rate*o.Expiry)
+	if o.Type == Call {
+		return spot*normalCDF(d1) - strike*normalCDF(d2)
+	}
+	return strike*normalCDF(-d2) - spot*normalCDF(-d1)
+}
+
+// d1d2 returns the d1 and d2 terms of the Black-Scholes-Merton formula.
+func (o EuropeanOption) d1d2(volatility float64) (float64, float64) {
+	stdDev := volatility * math.Sqrt(o.Expiry)
+	d1 := (math.Log(o.Spot/o.Strike) + (o.Rate-o.DividendYield+volatility*volatility/2)*o.Expiry) / stdDev
+	return d1, d1 - stdDev
+}
+
+// check validates the option's terms with the given volatility.
+func (o EuropeanOption) check(volatility float64) error {
+	switch {
+	case o.Type != Call && o.Type != Put:
+		return fmt.Errorf("%w: unknown option type %d", ErrInvalidOption, o.Type)
+	case o.Spot <= 0 || o.Strike <= 0:
+		return fmt.Errorf("%w: spot and strike must be positive", ErrInvalidOption)
+	case o.Expiry <= 0:
+		return fmt.Errorf("%w: expiry must be positive", ErrInvalidOption)
+	case volatility <= 0:
+		return fmt.Errorf("%w: volatility must be positive", ErrInvalidOption)
+	}
+	return nil
+}
+
+// normalCDF is the standard normal cumulative distribution function.
+func normalCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+// normalPDF is the standard normal probability density function.
+func normalPDF(x float64) float64 {
+	return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+}
@@ -0,0 +1,127 @@
+package gofin
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestEuropeanOptionPrice(t *testing.T) {
+	// Hull, Options, Futures and Other Derivatives, example 15.6.
+	call := EuropeanOption{Type: Call, Spot: 42, Strike: 40, Expiry: 0.5, Rate: 0.1, Volatility: 0.2}
+	put := call
+	put.Type = Put
+
+	var expected float64 = 4.75942239287154
+	actual, err := call.Price()
+	if err != nil || notWithin(actual, expected, 1e-10) {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", expected, actual, err)
+	}
+
+	expected = 0.808599372900096
+	actual, err = put.Price()
+	if err != nil || notWithin(actual, expected, 1e-10) {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", expected, actual, err)
+	}
+}
+
+func TestEuropeanOptionPutCallParity(t *testing.T) {
+	// C - P = S * e^(-qT) - K * e^(-rT)
+	for _, o := range []EuropeanOption{
+		{Spot: 100, Strike: 95, Expiry: 0.25, Rate: 0.05, DividendYield: 0.02, Volatility: 0.3},
+		{Spot: 50, Strike: 60, Expiry: 2, Rate: 0.03, DividendYield: 0.04, Volatility: 0.15},
+		{Spot: 10, Strike: 10, Expiry: 5, Rate: -0.005, Volatility: 0.6},
+	} {
+		o.Type = Call
+		call, _ := o.Price()
+		o.Type = Put
+		put, _ := o.Price()
+
+		expected := o.Spot*math.Exp(-o.DividendYield*o.Expiry) - o.Strike*math.Exp(-o.Rate*o.Expiry)
+		if notWithin(call-put, expected, 1e-10) {
+			t.Errorf("Test failed for %+v, expected: '%f', got: '%f'", o, expected, call-put)
+		}
+	}
+}
+
+func TestEuropeanOptionGreeks(t *testing.T) {
+	for _, typ := range []OptionType{Call, Put} {
+		o := EuropeanOption{Type: typ, Spot: 100, Strike: 105, Expiry: 0.75, Rate: 0.04, DividendYield: 0.015, Volatility: 0.25}
+		g, err := o.Greeks()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Central finite differences of the price in each input.
+		bump := func(h float64, set func(o *EuropeanOption, h float64)) float64 {
+			up, down := o, o
+			set(&up, h)
+			set(&down, -h)
+			pu, _ := up.Price()
+			pd, _ := down.Price()
+			return (pu - pd) / (2 * h)
+		}
+		delta := bump(1e-3, func(o *EuropeanOption, h float64) { o.Spot += h })
+		vega := bump(1e-5, func(o *EuropeanOption, h float64) { o.Volatility += h })
+		theta := -bump(1e-5, func(o *EuropeanOption, h float64) { o.Expiry += h })
+		rho := bump(1e-5, func(o *EuropeanOption, h float64) { o.Rate += h })
+
+		h := 1e-2
+		up, down := o, o
+		up.Spot += h
+		down.Spot -= h
+		p, _ := o.Price()
+		pu, _ := up.Price()
+		pd, _ := down.Price()
+		gamma := (pu - 2*p + pd) / (h * h)
+
+		checks := []struct {
+			name             string
+			actual, expected float64
+		}{
+			{"Delta", g.Delta, delta},
+			{"Gamma", g.Gamma, gamma},
+			{"Vega", g.Vega, vega},
+			{"Theta", g.Theta, theta},
+			{"Rho", g.Rho, rho},
+		}
+		for _, c := range checks {
+			if notWithin(c.actual, c.expected, 1e-5) {
+				t.Errorf("%s failed for type %d, expected: '%f', got: '%f'", c.name, typ, c.expected, c.actual)
+			}
+		}
+	}
+}
+
+func TestEuropeanOptionImpliedVolatility(t *testing.T) {
+	for _, typ := range []OptionType{Call, Put} {
+		for _, strike := range []float64{50, 90, 100, 120, 200} {
+			for _, volatility := range []float64{0.05, 0.2, 0.8, 2.5} {
+				o := EuropeanOption{Type: typ, Spot: 100, Strike: strike, Expiry: 1, Rate: 0.03, DividendYield: 0.01, Volatility: volatility}
+				price, _ := o.Price()
+				if vega, _ := o.Greeks(); vega.Vega < 1e-6 {
+					// The price does not pin down the volatility.
+					continue
+				}
+
+				actual, err := o.ImpliedVolatility(price)
+				if err != nil || notWithin(actual, volatility, 1e-7) {
+					t.Errorf("Test failed for %+v, expected: '%f', got: '%f' (%v)", o, volatility, actual, err)
+				}
+			}
+		}
+	}
+}
+
+func TestEuropeanOptionErrors(t *testing.T) {
+	o := EuropeanOption{Type: Call, Spot: 100, Strike: 100, Expiry: 1, Rate: 0.05}
+	if _, err := o.Price(); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidOption, err)
+	}
+	if _, err := o.ImpliedVolatility(120); !errors.Is(err, ErrNoSolution) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrNoSolution, err)
+	}
+	if _, err := o.ImpliedVolatility(1); !errors.Is(err, ErrNoSolution) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrNoSolution, err)
+	}
+}
@@ -0,0 +1,291 @@
+package gofin
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/lazarospsa/gofin/daycount"
+)
+
+// Valuation is the market value of an account at the close of a date. It
+// includes any external cash flow dated on the same day.
+type Valuation struct {
+	Date  time.Time
+	Value float64
+}
+
+// PeriodReturn is the time-weighted return between two valuation dates.
+type PeriodReturn struct {
+	Start  time.Time
+	End    time.Time
+	Return float64
+}
+
+// PerformanceSummary holds the returns of an account between two valuation dates.
+// The TimeWeighted, ModifiedDietz and MoneyWeighted returns are for the whole
+// period and are not annualized.
+type PerformanceSummary struct {
+	Start time.Time
+	End   time.Time
+
+	// TimeWeighted chains the sub-period returns between every valuation in
+	// the period, which removes the effect of the timing of external flows.
+	TimeWeighted float64
+
+	// ModifiedDietz approximates the time-weighted return from the start and
+	// end values alone, weighting each flow by the time it was invested.
+	ModifiedDietz float64
+
+	// MoneyWeighted is the internal rate of return over the period, which
+	// rewards having more money invested when returns are high. It is -1 when
+	// nothing comes back and NaN when no rate above -100% and up to 1000%
+	// fits the flows.
+	MoneyWeighted float64
+
+	// AnnualizedTimeWeighted and AnnualizedMoneyWeighted convert the period
+	// returns to annual rates on an Actual/365 basis. As the GIPS standards
+	// require, a period shorter than a year is not annualized: both equal the
+	// period returns.
+	AnnualizedTimeWeighted  float64
+	AnnualizedMoneyWeighted float64
+}
+
+// Performance computes time-weighted and money-weighted returns from a ledger
+// of account valuations and external cash flows. Deposits are positive flows
+// and withdrawals negative ones.
+//
+// A flow is assumed to happen at the end of its day, so it is included in that
+// day's valuation and earns nothing on that day.
+type Performance struct {
+	valuations []Valuation
+	flows      []DatedCashFlow
+}
+
+// NewPerformance returns a Performance for the ledger. It needs at least two
+// valuations on distinct dates, and every flow must fall on or after the first
+// valuation and on or before the last. Neither slice needs to be sorted.
+func NewPerformance(valuations []Valuation, flows []DatedCashFlow) (*Performance, error) {
+	p := &Performance{
+		valuations: append([]Valuation(nil), valuations...),
+		flows:      append([]DatedCashFlow(nil), flows...),
+	}
+	sort.SliceStable(p.valuations, func(i, j int) bool { return p.valuations[i].Date.Before(p.valuations[j].Date) })
+	sort.SliceStable(p.flows, func(i, j int) bool { return p.flows[i].Date.Before(p.flows[j].Date) })
+
+	if len(p.valuations) < 2 {
+		return nil, fmt.Errorf("%w: at least two valuations are required", ErrInvalidLedger)
+	}
+	for i, v := range p.valuations {
+		if v.Value < 0 {
+			return nil, fmt.Errorf("%w: negative value on %s", ErrInvalidLedger, v.Date.Format("2006-01-02"))
+		}
+		if i > 0 && daycount.Days(p.valuations[i-1].Date, v.Date) == 0 {
+			return nil, fmt.Errorf("%w: two valuations on %s", ErrInvalidLedger, v.Date.Format("2006-01-02"))
+		}
+	}
+	first, last := p.valuations[0].Date, p.valuations[len(p.valuations)-1].Date
+	for _, flow := range p.flows {
+		if daycount.Days(first, flow.Date) < 0 || daycount.Days(flow.Date, last) < 0 {
+			return nil, fmt.Errorf("%w: flow on %s is outside the valued period", ErrInvalidLedger, flow.Date.Format("2006-01-02"))
+		}
+	}
+
+	return p, nil
+}
+
+// Daily returns the time-weighted return between each pair of consecutive
+// valuations, which is one return per day for a daily valued account.
+func (p *Performance) Daily() ([]PeriodReturn, error) {
+	returns := make([]PeriodReturn, 0, len(p.valuations)-1)
+	for i := 1; i < len(p.valuations); i++ {
+		r, err := p.subPeriodReturn(i)
+		if err != nil {
+			return nil, err
+		}
+		returns = append(returns, PeriodReturn{Start: p.valuations[i-1].Date, End: p.valuations[i].Date, Return: r})
+	}
+	return returns, nil
+}
+
+// Monthly returns the time-weighted return of each calendar month, measured
+// from the last valuation of the previous month (or the first valuation) to
+// the last valuation of the month.
+func (p *Performance) Monthly() ([]PeriodReturn, error) {
+	var returns []PeriodReturn
+	start := 0
+	for i := 1; i < len(p.valuations); i++ {
+		if i+1 < len(p.valuations) && sameMonth(p.valuations[i].Date, p.valuations[i+1].Date) {
+			continue
+		}
+		r, err := p.timeWeighted(start, i)
+		if err != nil {
+			return nil, err
+		}
+		returns = append(returns, PeriodReturn{Start: p.valuations[start].Date, End: p.valuations[i].Date, Return: r})
+		start = i
+	}
+	return returns, nil
+}
+
+// YearToDate returns the performance from the last valuation of the previous
+// year, or the first valuation if it is later, to the last valuation on or
+// before asOf.
+func (p *Performance) YearToDate(asOf time.Time) (PerformanceSummary, error) {
+	yearEnd := time.Date(asOf.Year(), time.January, 1, 0, 0, 0, 0, asOf.Location()).AddDate(0, 0, -1)
+	start := p.valuations[0].Date
+	if daycount.Days(start, yearEnd) > 0 {
+		start = yearEnd
+	}
+	return p.Summary(start, asOf)
+}
+
+// SinceInception returns the performance from the first valuation to the last
+// valuation on or before asOf.
+func (p *Performance) SinceInception(asOf time.Time) (PerformanceSummary, error) {
+	return p.Summary(p.valuations[0].Date, asOf)
+}
+
+// Summary returns the performance from the last valuation on or before start
+// to the last valuation on or before end. A money-weighted return that cannot
+// be found is NaN rather than an error.
+func (p *Performance) Summary(start, end time.Time) (PerformanceSummary, error) {
+	from, to := p.valuationAt(start), p.valuationAt(end)
+	if from < 0 || to <= from {
+		return PerformanceSummary{}, fmt.Errorf("%w: no valuations between %s and %s",
+			ErrInvalidLedger, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+	v0, v1 := p.valuations[from], p.valuations[to]
+	flows := p.flowsBetween(v0.Date, v1.Date)
+
+	twr, err := p.timeWeighted(from, to)
+	if err != nil {
+		return PerformanceSummary{}, err
+	}
+	dietz, err := modifiedDietz(v0, v1, flows)
+	if err != nil {
+		return PerformanceSummary{}, err
+	}
+	mwr, err := moneyWeighted(v0, v1, flows)
+	if err != nil {
+		// The time-weighted returns stand on their own.
+		mwr = math.NaN()
+	}
+
+	summary := PerformanceSummary{
+		Start:                   v0.Date,
+		End:                     v1.Date,
+		TimeWeighted:            twr,
+		ModifiedDietz:           dietz,
+		MoneyWeighted:           mwr,
+		AnnualizedTimeWeighted:  twr,
+		AnnualizedMoneyWeighted: mwr,
+	}
+	if years := float64(daycount.Days(v0.Date, v1.Date)) / 365; years >= 1 {
+		summary.AnnualizedTimeWeighted = math.Pow(1+twr, 1/years) - 1
+		summary.AnnualizedMoneyWeighted = math.Pow(1+mwr, 1/years) - 1
+	}
+	return summary, nil
+}
+
+// timeWeighted chains the sub-period returns from valuation from to valuation to.
+// TWR = prod(1 + r_i) - 1
+func (p *Performance) timeWeighted(from, to int) (float64, error) {
+	growth := 1.0
+	for i := from + 1; i <= to; i++ {
+		r, err := p.subPeriodReturn(i)
+		if err != nil {
+			return 0.0, err
+		}
+		growth *= 1 + r
+	}
+	return growth - 1, nil
+}
+
+// subPeriodReturn returns the Modified Dietz return from valuation i-1 to
+// valuation i, which is exact when no flow falls strictly between them.
+func (p *Performance) subPeriodReturn(i int) (float64, error) {
+	v0, v1 := p.valuations[i-1], p.valuations[i]
+	return modifiedDietz(v0, v1, p.flowsBetween(v0.Date, v1.Date))
+}
+
+// valuationAt returns the index of the last valuation on or before t, or -1.
+func (p *Performance) valuationAt(t time.Time) int {
+	return sort.Search(len(p.valuations), func(i int) bool {
+		return daycount.Days(t, p.valuations[i].Date) > 0
+	}) - 1
+}
+
+// flowsBetween returns the flows after start and on or before end.
+func (p *Performance) flowsBetween(start, end time.Time) []DatedCashFlow {
+	first := sort.Search(len(p.flows), func(i int) bool { return daycount.Days(start, p.flows[i].Date) > 0 })
+	last := sort.Search(len(p.flows), func(i int) bool { return daycount.Days(end, p.flows[i].Date) > 0 })
+	return p.flows[first:last]
+}
+
+// modifiedDietz returns the Modified Dietz return between two valuations.
+// R = (V1 - V0 - sum(F_i)) / (V0 + sum(W_i * F_i))
+// V0 and V1 are the start and end values,
+// F_i are the external flows,
+// W_i is the fraction of the period left after flow i.
+func modifiedDietz(v0, v1 Valuation, flows []DatedCashFlow) (float64, error) {
+	days := float64(daycount.Days(v0.Date, v1.Date))
+	net, weighted := 0.0, 0.0
+	for _, flow := range flows {
+		net += flow.Amount
+		weighted += flow.Amount * float64(daycount.Days(flow.Date, v1.Date)) / days
+	}
+
+	invested := v0.Value + weighted
+	if invested == 0 {
+		return 0.0, fmt.Errorf("%w: nothing invested from %s to %s",
+			ErrZeroValue, v0.Date.Format("2006-01-02"), v1.Date.Format("2006-01-02"))
+	}
+	return (v1.Value - v0.Value - net) / invested, nil
+}
+
+// moneyWeighted returns the internal rate of return over the period from v0 to
+// v1, seen from the investor: the start value and deposits are paid in and the
+// end value is received.
+func moneyWeighted(v0, v1 Valuation, flows []DatedCashFlow) (float64, error) {
+	cashFlows := make([]DatedCashFlow, 0, len(flows)+2)
+	cashFlows = append(cashFlows, DatedCashFlow{Date: v0.Date, Amount: -v0.Value})
+	for _, flow := range flows {
+		cashFlows = append(cashFlows, DatedCashFlow{Date: flow.Date, Amount: -flow.Amount})
+	}
+	cashFlows = append(cashFlows, DatedCashFlow{Date: v1.Date, Amount: v1.Value})
+
+	received := false
+	for _, cashFlow := range cashFlows {
+		received = received || cashFlow.Amount > 0
+	}
+	if !received {
+		// Nothing comes back: a total loss.
+		return -1, nil
+	}
+
+	period := wholePeriod{days: float64(daycount.Days(v0.Date, v1.Date))}
+	solver := IRRSolver{LowerBound: -1 + 1e-9, UpperBound: 10}
+	result, err := XIRR(cashFlows, XIRROptions{Solver: solver, DayCount: period})
+	if err != nil {
+		return 0.0, err
+	}
+	return result.Rate, nil
+}
+
+// wholePeriod measures time as a fraction of a period of days days, so that
+// XIRR solves for the return over the whole period rather than a yearly rate.
+type wholePeriod struct {
+	days float64
+}
+
+// YearFraction returns the days from start to end as a fraction of the period.
+func (p wholePeriod) YearFraction(start, end time.Time) float64 {
+	return float64(daycount.Days(start, end)) / p.days
+}
+
+// sameMonth reports whether a and b fall in the same calendar month.
+func sameMonth(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.Month() == b.Month()
+}
@@ -0,0 +1,199 @@
+package gofin
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func testPerformance(t *testing.T) *Performance {
+	t.Helper()
+	valuations := []Valuation{
+		{date(2024, 2, 29), 1680},
+		{date(2024, 1, 31), 1000},
+		{date(2024, 2, 15), 1600},
+		{date(2024, 3, 31), 1550},
+		{date(2024, 12, 31), 1700},
+		{date(2025, 1, 31), 1850},
+	}
+	flows := []DatedCashFlow{
+		{date(2024, 2, 15), 500},
+		{date(2024, 3, 10), -200},
+		{date(2025, 1, 15), 100},
+	}
+	p, err := NewPerformance(valuations, flows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestPerformanceDaily(t *testing.T) {
+	daily, err := testPerformance(t).Daily()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The deposit on 15 February is part of that day's valuation, so the
+	// first sub-period earned (1600 - 1000 - 500) / 1000.
+	expected := []float64{0.1, 0.05, 0.0453216374269006, 0.0967741935483871, 0.0285451197053407}
+	if len(daily) != len(expected) {
+		t.Fatalf("Test failed, expected %d returns, got: %d", len(expected), len(daily))
+	}
+	for i, r := range daily {
+		if notWithin(r.Return, expected[i], 1e-12) {
+			t.Errorf("Test failed for %v, expected: '%f', got: '%f'", r.End, expected[i], r.Return)
+		}
+	}
+}
+
+func TestPerformanceMonthly(t *testing.T) {
+	monthly, err := testPerformance(t).Monthly()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []PeriodReturn{
+		{date(2024, 1, 31), date(2024, 2, 29), 1.1*1.05 - 1},
+		{date(2024, 2, 29), date(2024, 3, 31), 0.0453216374269006},
+		{date(2024, 3, 31), date(2024, 12, 31), 0.0967741935483871},
+		{date(2024, 12, 31), date(2025, 1, 31), 0.0285451197053407},
+	}
+	if len(monthly) != len(expected) {
+		t.Fatalf("Test failed, expected %d returns, got: %d", len(expected), len(monthly))
+	}
+	for i, r := range monthly {
+		if !r.Start.Equal(expected[i].Start) || !r.End.Equal(expected[i].End) || notWithin(r.Return, expected[i].Return, 1e-12) {
+			t.Errorf("Test failed, expected: %+v, got: %+v", expected[i], r)
+		}
+	}
+}
+
+func TestPerformanceSummary(t *testing.T) {
+	p := testPerformance(t)
+
+	inception, err := p.SinceInception(date(2025, 2, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := PerformanceSummary{
+		Start:                   date(2024, 1, 31),
+		End:                     date(2025, 1, 31),
+		TimeWeighted:            0.361985535669805,
+		ModifiedDietz:           0.344777056730165,
+		MoneyWeighted:           0.344963524262351,
+		AnnualizedTimeWeighted:  0.360836357644421,
+		AnnualizedMoneyWeighted: 0.343874886772467,
+	}
+	checkSummary(t, inception, expected)
+
+	ytd, err := p.YearToDate(date(2025, 1, 31))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = PerformanceSummary{
+		Start:                   date(2024, 12, 31),
+		End:                     date(2025, 1, 31),
+		TimeWeighted:            0.0285451197053407,
+		ModifiedDietz:           0.0285451197053407,
+		MoneyWeighted:           0.0285508501609185,
+		AnnualizedTimeWeighted:  0.0285451197053407,
+		AnnualizedMoneyWeighted: 0.0285508501609185,
+	}
+	checkSummary(t, ytd, expected)
+}
+
+func TestPerformanceLosses(t *testing.T) {
+	// A deposit that is lost with the rest of the account.
+	p, err := NewPerformance([]Valuation{
+		{date(2024, 1, 1), 1000},
+		{date(2024, 1, 15), 1400},
+		{date(2024, 1, 31), 0},
+	}, []DatedCashFlow{{date(2024, 1, 15), 500}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary, err := p.SinceInception(date(2024, 1, 31))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSummary(t, summary, PerformanceSummary{
+		Start:                   date(2024, 1, 1),
+		End:                     date(2024, 1, 31),
+		TimeWeighted:            -1,
+		ModifiedDietz:           -1500 / (1000 + 500*16/30.0),
+		MoneyWeighted:           -1,
+		AnnualizedTimeWeighted:  -1,
+		AnnualizedMoneyWeighted: -1,
+	})
+
+	// A loss beyond -99% is still within the solver's bounds.
+	p, _ = NewPerformance([]Valuation{{date(2024, 1, 1), 1000}, {date(2025, 1, 1), 5}}, nil)
+	summary, err = p.SinceInception(date(2025, 1, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	annualized := math.Pow(0.005, 365/366.0) - 1
+	checkSummary(t, summary, PerformanceSummary{
+		Start:                   date(2024, 1, 1),
+		End:                     date(2025, 1, 1),
+		TimeWeighted:            -0.995,
+		ModifiedDietz:           -0.995,
+		MoneyWeighted:           -0.995,
+		AnnualizedTimeWeighted:  annualized,
+		AnnualizedMoneyWeighted: annualized,
+	})
+
+	// A gain beyond the solver's bounds leaves only the money-weighted
+	// return unknown.
+	p, _ = NewPerformance([]Valuation{{date(2024, 1, 1), 1000}, {date(2024, 2, 1), 20000}}, nil)
+	summary, err = p.SinceInception(date(2024, 2, 1))
+	if err != nil || notWithin(summary.TimeWeighted, 19, 1e-12) || notWithin(summary.ModifiedDietz, 19, 1e-12) {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", 19.0, summary.TimeWeighted, err)
+	}
+	if !math.IsNaN(summary.MoneyWeighted) || !math.IsNaN(summary.AnnualizedMoneyWeighted) {
+		t.Errorf("Test failed, expected NaN, got: '%f'", summary.MoneyWeighted)
+	}
+}
+
+func checkSummary(t *testing.T, actual, expected PerformanceSummary) {
+	t.Helper()
+	if !actual.Start.Equal(expected.Start) || !actual.End.Equal(expected.End) {
+		t.Errorf("Test failed, expected period %v to %v, got: %v to %v", expected.Start, expected.End, actual.Start, actual.End)
+	}
+	fields := []struct {
+		name             string
+		actual, expected float64
+	}{
+		{"TimeWeighted", actual.TimeWeighted, expected.TimeWeighted},
+		{"ModifiedDietz", actual.ModifiedDietz, expected.ModifiedDietz},
+		{"MoneyWeighted", actual.MoneyWeighted, expected.MoneyWeighted},
+		{"AnnualizedTimeWeighted", actual.AnnualizedTimeWeighted, expected.AnnualizedTimeWeighted},
+		{"AnnualizedMoneyWeighted", actual.AnnualizedMoneyWeighted, expected.AnnualizedMoneyWeighted},
+	}
+	for _, f := range fields {
+		if notWithin(f.actual, f.expected, 1e-9) {
+			t.Errorf("%s failed, expected: '%f', got: '%f'", f.name, f.expected, f.actual)
+		}
+	}
+}
+
+func TestNewPerformanceErrors(t *testing.T) {
+	valuations := []Valuation{{date(2024, 1, 31), 1000}, {date(2024, 2, 29), 1100}}
+
+	if _, err := NewPerformance(valuations[:1], nil); !errors.Is(err, ErrInvalidLedger) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidLedger, err)
+	}
+	if _, err := NewPerformance(valuations, []DatedCashFlow{{date(2024, 3, 1), 10}}); !errors.Is(err, ErrInvalidLedger) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidLedger, err)
+	}
+	duplicate := append(valuations, Valuation{date(2024, 2, 29), 1200})
+	if _, err := NewPerformance(duplicate, nil); !errors.Is(err, ErrInvalidLedger) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidLedger, err)
+	}
+
+	p, _ := NewPerformance(valuations, nil)
+	if _, err := p.Summary(date(2024, 2, 29), date(2024, 3, 15)); !errors.Is(err, ErrInvalidLedger) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidLedger, err)
+	}
+}
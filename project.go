@@ -0,0 +1,163 @@
+package gofin
+
+import "math"
+
+// Project is an investment described by one series of signed cash flows:
+// money paid out is negative and money received is positive. CashFlows[0]
+// occurs now and CashFlows[t] at the end of period t.
+type Project struct {
+	// Name labels the project in rankings.
+	Name string
+
+	CashFlows []float64
+
+	// DiscountRate is the cost of capital per period. It discounts the cash
+	// flows and finances the outflows in MIRR.
+	DiscountRate float64
+
+	// ReinvestmentRate is the rate per period at which MIRR reinvests the
+	// inflows.
+	ReinvestmentRate float64
+}
+
+// ProjectReport holds the capital budgeting measures of a Project. Measures
+// that do not exist for the cash flows are NaN, or -1 for the payback periods.
+type ProjectReport struct {
+	// NPV is the net present value at the discount rate.
+	NPV float64
+
+	// IRR is the internal rate of return found from the default guess, and
+	// IRRs every rate found, in ascending order. Cash flows that change sign
+	// more than once may have several.
+	IRR  float64
+	IRRs []float64
+
+	// MIRR is the modified internal rate of return.
+	MIRR float64
+
+	// ProfitabilityIndex is the present value of the inflows over that of the
+	// outflows; 1 + NPV / I for a single initial investment I.
+	ProfitabilityIndex float64
+
+	// EquivalentAnnualAnnuity is the level payment per period over the life
+	// of the project with the same NPV.
+	EquivalentAnnualAnnuity float64
+
+	// PaybackPeriod and DiscountedPaybackPeriod are the first periods at the
+	// end of which the cumulative cash flows, undiscounted and discounted,
+	// are no longer negative.
+	PaybackPeriod           int
+	DiscountedPaybackPeriod int
+
+	// FractionalPayback and FractionalDiscountedPayback interpolate within
+	// the payback period, assuming its cash flow arrives evenly.
+	FractionalPayback           float64
+	FractionalDiscountedPayback float64
+}
+
+// Evaluate returns the capital budgeting measures of the project. It returns
+// ErrEmptyInput for fewer than two cash flows and ErrInvalidRate for a rate at
+// or below -1.
+func (p Project) Evaluate() (ProjectReport, error) {
+	if err := p.check(); err != nil {
+		return ProjectReport{}, err
+	}
+
+	discounted := p.discounted()
+	report := ProjectReport{
+		IRR:                math.NaN(),
+		MIRR:               mirr(p.CashFlows, p.DiscountRate, p.ReinvestmentRate),
+		ProfitabilityIndex: profitabilityIndex(discounted),
+	}
+	for _, cashFlow := range discounted {
+		report.NPV += cashFlow
+	}
+	report.EquivalentAnnualAnnuity = report.NPV / annuityFactor(p.DiscountRate, len(p.CashFlows)-1)
+
+	if result, err := (IRRSolver{AllRoots: true}).Solve(p.CashFlows); err == nil {
+		report.IRR, report.IRRs = result.Rate, result.Roots
+	}
+	report.PaybackPeriod, report.FractionalPayback = payback(p.CashFlows)
+	report.DiscountedPaybackPeriod, report.FractionalDiscountedPayback = payback(discounted)
+	return report, nil
+}
+
+// check validates the project.
+func (p Project) check() error {
+	if len(p.CashFlows) < 2 {
+		return ErrEmptyInput
+	}
+	if p.DiscountRate <= -1 || p.ReinvestmentRate <= -1 {
+		return ErrInvalidRate
+	}
+	return nil
+}
+
+// discounted returns the present value of each cash flow at the discount rate.
+func (p Project) discounted() []float64 {
+	discounted := make([]float64, len(p.CashFlows))
+	factor := 1.0
+	for t, cashFlow := range p.CashFlows {
+		discounted[t] = cashFlow * factor
+		factor /= 1 + p.DiscountRate
+	}
+	return discounted
+}
+
+// mirr returns the modified internal rate of return of signed cash flows,
+// like the spreadsheet MIRR function, or NaN without both an outflow and an
+// inflow.
+// MIRR = (FV(inflows, r) / -PV(outflows, f))^(1 / n) - 1
+// r is the reinvestment rate,
+// f is the finance rate,
+// n is the number of periods.
+func mirr(cashFlows []float64, financeRate, reinvestmentRate float64) float64 {
+	n := len(cashFlows) - 1
+	outflows, inflows := 0.0, 0.0
+	for t, cashFlow := range cashFlows {
+		if cashFlow < 0 {
+			outflows += PresentValue(cashFlow, financeRate, t)
+		} else {
+			inflows += FutureValue(cashFlow, reinvestmentRate, n-t)
+		}
+	}
+	if outflows == 0 || inflows == 0 {
+		return math.NaN()
+	}
+	return math.Pow(inflows/-outflows, 1/float64(n)) - 1
+}
+
+// profitabilityIndex returns the present value of the inflows over that of
+// the outflows, or NaN without outflows.
+func profitabilityIndex(discounted []float64) float64 {
+	outflows, inflows := 0.0, 0.0
+	for _, cashFlow := range discounted {
+		if cashFlow < 0 {
+			outflows -= cashFlow
+		} else {
+			inflows += cashFlow
+		}
+	}
+	if outflows == 0 {
+		return math.NaN()
+	}
+	return inflows / outflows
+}
+
+// payback returns the first period at the end of which the cumulative cash
+// flows are no longer negative, and that period interpolated linearly within
+// it, or -1 and -1 when they stay negative.
+func payback(cashFlows []float64) (int, float64) {
+	cumulative := cashFlows[0]
+	if cumulative >= 0 {
+		return 0, 0.0
+	}
+	for t := 1; t < len(cashFlows); t++ {
+		previous := cumulative
+		cumulative += cashFlows[t]
+		if cumulative >= 0 {
+			return t, float64(t-1) + -previous/cashFlows[t]
+		}
+	}
+	return -1, -1.0
+}
@@ -0,0 +1,85 @@
+package gofin
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestProjectEvaluate(t *testing.T) {
+	p := Project{CashFlows: []float64{-1000, 300, 400, 500, 200}, DiscountRate: 0.1, ReinvestmentRate: 0.12}
+	report, err := p.Evaluate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		actual   float64
+		expected float64
+	}{
+		{"NPV", report.NPV, 115.56587664776981},
+		{"MIRR", report.MIRR, 0.1390332647327417},
+		{"ProfitabilityIndex", report.ProfitabilityIndex, 1.1155658766477698},
+		{"EquivalentAnnualAnnuity", report.EquivalentAnnualAnnuity, 36.457659987071686},
+		{"FractionalPayback", report.FractionalPayback, 2.6},
+		{"FractionalDiscountedPayback", report.FractionalDiscountedPayback, 3.154},
+	}
+	for _, test := range tests {
+		if notWithin(test.actual, test.expected, 1e-9) {
+			t.Errorf("Test %s failed, expected: '%f', got: '%f'", test.name, test.expected, test.actual)
+		}
+	}
+
+	if npv := NetPresentValue(report.IRR, 5, p.CashFlows); math.Abs(npv) > 1e-6 || len(report.IRRs) != 1 || notWithin(report.IRRs[0], report.IRR, 1e-9) {
+		t.Errorf("Test failed, expected a single IRR, got: '%f' %v", report.IRR, report.IRRs)
+	}
+	if report.PaybackPeriod != 3 || report.DiscountedPaybackPeriod != 4 {
+		t.Errorf("Test failed, expected: 3 and 4, got: %d and %d", report.PaybackPeriod, report.DiscountedPaybackPeriod)
+	}
+}
+
+func TestProjectEvaluateMultipleIRRs(t *testing.T) {
+	report, err := Project{CashFlows: []float64{-100, 230, -132}, DiscountRate: 0.05}.Evaluate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.IRRs) != 2 || notWithin(report.IRRs[0], 0.1, 1e-9) || notWithin(report.IRRs[1], 0.2, 1e-9) {
+		t.Errorf("Test failed, expected: [0.1 0.2], got: %v", report.IRRs)
+	}
+}
+
+func TestProjectEvaluateUndefined(t *testing.T) {
+	report, err := Project{CashFlows: []float64{-1000, 100, 100}, DiscountRate: 0.1}.Evaluate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.PaybackPeriod != -1 || report.FractionalPayback != -1 || report.DiscountedPaybackPeriod != -1 {
+		t.Errorf("Test failed, expected no payback, got: %+v", report)
+	}
+
+	report, err = Project{CashFlows: []float64{100, 200}, DiscountRate: 0.1}.Evaluate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsNaN(report.IRR) || !math.IsNaN(report.MIRR) || !math.IsNaN(report.ProfitabilityIndex) || report.PaybackPeriod != 0 {
+		t.Errorf("Test failed, expected undefined measures, got: %+v", report)
+	}
+}
+
+func TestProjectEvaluateErrors(t *testing.T) {
+	tests := []struct {
+		project  Project
+		expected error
+	}{
+		{Project{CashFlows: []float64{-100}}, ErrEmptyInput},
+		{Project{CashFlows: []float64{-100, 110}, DiscountRate: -1}, ErrInvalidRate},
+		{Project{CashFlows: []float64{-100, 110}, ReinvestmentRate: -1.5}, ErrInvalidRate},
+	}
+
+	for _, test := range tests {
+		if _, err := test.project.Evaluate(); !errors.Is(err, test.expected) {
+			t.Errorf("Test failed for %+v, expected: '%v', got: '%v'", test.project, test.expected, err)
+		}
+	}
+}
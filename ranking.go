@@ -0,0 +1,220 @@
+package gofin
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// maxChainPeriods bounds the common life of a replacement chain, so that
+// projects with coprime lives do not produce absurdly long chains.
+const maxChainPeriods = 10000
+
+// RankingCriterion is the measure by which RankProjects orders projects.
+type RankingCriterion int
+
+const (
+	// RankByNPV ranks by net present value over each project's own life.
+	RankByNPV RankingCriterion = iota
+
+	// RankByChainNPV ranks by the net present value of each project repeated
+	// until all the projects end together, for projects with unequal lives.
+	RankByChainNPV
+
+	// RankByEAA ranks by equivalent annual annuity, which compares projects
+	// with unequal lives as if each were repeated forever.
+	RankByEAA
+
+	// RankByIRR ranks by internal rate of return.
+	RankByIRR
+
+	// RankByPI ranks by profitability index.
+	RankByPI
+)
+
+// String returns the name of the criterion.
+func (c RankingCriterion) String() string {
+	switch c {
+	case RankByNPV:
+		return "NPV"
+	case RankByChainNPV:
+		return "chain NPV"
+	case RankByEAA:
+		return "EAA"
+	case RankByIRR:
+		return "IRR"
+	case RankByPI:
+		return "PI"
+	}
+	return fmt.Sprintf("RankingCriterion(%d)", int(c))
+}
+
+// RankedProject is one place of a ranking.
+type RankedProject struct {
+	// Index is the position of the project in the ranked slice.
+	Index int
+	Name  string
+
+	// Value is the measure the project was ranked by, NaN when undefined.
+	Value float64
+}
+
+// Crossover holds the crossover, or Fisher, rates of two projects: the
+// discount rates at which their NPVs are equal and their NPV ranking flips.
+type Crossover struct {
+	// A and B are the positions of the projects, with A < B.
+	A, B int
+
+	// Rates are the crossover rates in ascending order, empty when one
+	// project's NPV exceeds the other's at every rate.
+	Rates []float64
+}
+
+// NPVProfile returns profile[i][j], the net present value of projects[i] at
+// rates[j], ignoring the projects' own discount rates. It returns
+// ErrEmptyInput for a project without cash flows and ErrInvalidRate when a
+// rate is at or below -1.
+func NPVProfile(projects []Project, rates []float64) ([][]float64, error) {
+	flows := make([][]float64, len(projects))
+	for i, p := range projects {
+		if len(p.CashFlows) == 0 {
+			return nil, ErrEmptyInput
+		}
+		flows[i] = p.CashFlows
+	}
+	return NPVBatch(rates, flows)
+}
+
+// CrossoverRates returns the crossover rates of every pair of projects, found
+// as the internal rates of return of the differences of their cash flows.
+// A shorter series of cash flows is extended with zeros.
+func CrossoverRates(projects []Project) ([]Crossover, error) {
+	for _, p := range projects {
+		if len(p.CashFlows) == 0 {
+			return nil, ErrEmptyInput
+		}
+	}
+
+	var crossovers []Crossover
+	for a := 0; a < len(projects); a++ {
+		for b := a + 1; b < len(projects); b++ {
+			crossovers = append(crossovers, Crossover{A: a, B: b, Rates: crossoverRates(projects[a].CashFlows, projects[b].CashFlows)})
+		}
+	}
+	return crossovers, nil
+}
+
+// crossoverRates returns the rates at which two series of cash flows have
+// equal NPVs.
+func crossoverRates(a, b []float64) []float64 {
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+	difference := make([]float64, n)
+	copy(difference, a)
+	for t, cashFlow := range b {
+		difference[t] -= cashFlow
+	}
+	if !hasSignChange(difference) {
+		return nil
+	}
+
+	result, err := (IRRSolver{AllRoots: true}).Solve(difference)
+	if err != nil {
+		return nil
+	}
+	return result.Roots
+}
+
+// RankProjects orders mutually exclusive projects best first by a criterion,
+// each at its own discount rate. Projects whose measure is undefined, such as
+// the IRR of cash flows without a sign change, come last; ties keep their
+// order. It returns the errors of Project.Evaluate, and ErrInvalidRanking for
+// an unknown criterion or a replacement chain longer than 10000 periods.
+func RankProjects(projects []Project, criterion RankingCriterion) ([]RankedProject, error) {
+	if criterion < RankByNPV || criterion > RankByPI {
+		return nil, fmt.Errorf("%w: unknown criterion %d", ErrInvalidRanking, criterion)
+	}
+
+	periods := 1
+	if criterion == RankByChainNPV {
+		for _, p := range projects {
+			if len(p.CashFlows) < 2 {
+				return nil, ErrEmptyInput
+			}
+			periods = lcm(periods, len(p.CashFlows)-1)
+			if periods > maxChainPeriods {
+				return nil, fmt.Errorf("%w: replacement chain exceeds %d periods", ErrInvalidRanking, maxChainPeriods)
+			}
+		}
+	}
+
+	ranking := make([]RankedProject, len(projects))
+	for i, p := range projects {
+		if criterion == RankByChainNPV {
+			chain, err := p.Chain(periods)
+			if err != nil {
+				return nil, err
+			}
+			p = chain
+		}
+		report, err := p.Evaluate()
+		if err != nil {
+			return nil, err
+		}
+
+		var value float64
+		switch criterion {
+		case RankByNPV, RankByChainNPV:
+			value = report.NPV
+		case RankByEAA:
+			value = report.EquivalentAnnualAnnuity
+		case RankByIRR:
+			value = report.IRR
+		case RankByPI:
+			value = report.ProfitabilityIndex
+		}
+		ranking[i] = RankedProject{Index: i, Name: projects[i].Name, Value: value}
+	}
+
+	sort.SliceStable(ranking, func(i, j int) bool {
+		if math.IsNaN(ranking[j].Value) {
+			return !math.IsNaN(ranking[i].Value)
+		}
+		return ranking[i].Value > ranking[j].Value
+	})
+	return ranking, nil
+}
+
+// Chain returns the project repeated back to back over periods periods, each
+// repetition's first cash flow falling on the last period of the one before.
+// periods must be a positive multiple of the project's life, len(CashFlows) - 1,
+// or Chain returns ErrInvalidPeriods.
+func (p Project) Chain(periods int) (Project, error) {
+	if len(p.CashFlows) < 2 {
+		return Project{}, ErrEmptyInput
+	}
+	life := len(p.CashFlows) - 1
+	if periods <= 0 || periods%life != 0 {
+		return Project{}, fmt.Errorf("%w: %d is not a multiple of the project life %d", ErrInvalidPeriods, periods, life)
+	}
+
+	chain := p
+	chain.CashFlows = make([]float64, periods+1)
+	for start := 0; start < periods; start += life {
+		for t, cashFlow := range p.CashFlows {
+			chain.CashFlows[start+t] += cashFlow
+		}
+	}
+	return chain, nil
+}
+
+// lcm returns the least common multiple of two positive integers.
+func lcm(a, b int) int {
+	x, y := a, b
+	for y != 0 {
+		x, y = y, x%y
+	}
+	return a / x * b
+}
@@ -0,0 +1,153 @@
+package gofin
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestNPVProfile(t *testing.T) {
+	projects := []Project{
+		{Name: "A", CashFlows: []float64{-10000, 6500, 3000, 3000, 1000}},
+		{Name: "B", CashFlows: []float64{-10000, 3500, 3500, 3500, 3500}},
+	}
+	rates := []float64{0.05, 0.15}
+
+	profile, err := NPVProfile(projects, rates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]float64{{2325.779896236649, 464.9068578228364}, {2410.82676456826, -7.575730504105422}}
+	for i := range expected {
+		for j := range expected[i] {
+			if notWithin(profile[i][j], expected[i][j], 1e-9) {
+				t.Errorf("Test failed for project %d at %f, expected: '%f', got: '%f'", i, rates[j], expected[i][j], profile[i][j])
+			}
+		}
+	}
+
+	if _, err := NPVProfile([]Project{{}}, rates); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrEmptyInput, err)
+	}
+}
+
+func TestCrossoverRates(t *testing.T) {
+	projects := []Project{
+		{CashFlows: []float64{-10000, 6500, 3000, 3000, 1000}},
+		{CashFlows: []float64{-10000, 3500, 3500, 3500, 3500}},
+		{CashFlows: []float64{-5000, 6000}},
+	}
+
+	crossovers, err := CrossoverRates(projects)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(crossovers) != 3 || crossovers[0].A != 0 || crossovers[0].B != 1 || crossovers[2].A != 1 || crossovers[2].B != 2 {
+		t.Fatalf("Test failed, expected the pairs 0-1, 0-2 and 1-2, got: %+v", crossovers)
+	}
+	if rates := crossovers[0].Rates; len(rates) != 1 || notWithin(rates[0], 0.0621875390811416, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: %v", 0.0621875390811416, rates)
+	}
+
+	// At the crossover rate both projects have the same NPV.
+	for _, c := range crossovers {
+		for _, rate := range c.Rates {
+			a := NetPresentValue(rate, 0, projects[c.A].CashFlows)
+			b := NetPresentValue(rate, 0, projects[c.B].CashFlows)
+			if math.Abs(a-b) > 1e-6 {
+				t.Errorf("Test failed for %d and %d at %f, expected equal NPVs, got: '%f' and '%f'", c.A, c.B, rate, a, b)
+			}
+		}
+	}
+
+	// Cash flows that differ by a constant outflow never cross.
+	crossovers, _ = CrossoverRates([]Project{{CashFlows: []float64{-100, 120}}, {CashFlows: []float64{-110, 120}}})
+	if len(crossovers) != 1 || len(crossovers[0].Rates) != 0 {
+		t.Errorf("Test failed, expected no crossover, got: %+v", crossovers)
+	}
+}
+
+func TestRankProjects(t *testing.T) {
+	projects := []Project{
+		{Name: "short", CashFlows: []float64{-1000, 620, 620}, DiscountRate: 0.1},
+		{Name: "long", CashFlows: []float64{-1500, 500, 500, 500, 500}, DiscountRate: 0.1},
+		{Name: "free", CashFlows: []float64{100, 10}, DiscountRate: 0.1},
+	}
+
+	tests := []struct {
+		criterion RankingCriterion
+		order     []string
+		best      float64
+	}{
+		{RankByNPV, []string{"free", "long", "short"}, 109.0909090909091},
+		{RankByChainNPV, []string{"free", "short", "long"}, 0},
+		{RankByEAA, []string{"free", "short", "long"}, 120},
+		{RankByIRR, []string{"short", "long", "free"}, 0.15622691992160131},
+		{RankByPI, []string{"short", "long", "free"}, 1.0760330578512396},
+	}
+
+	for _, test := range tests {
+		ranking, err := RankProjects(projects, test.criterion)
+		if err != nil {
+			t.Errorf("Test %v failed: %v", test.criterion, err)
+			continue
+		}
+		for i, name := range test.order {
+			if ranking[i].Name != name || projects[ranking[i].Index].Name != name {
+				t.Errorf("Test %v failed, expected: %v, got: %+v", test.criterion, test.order, ranking)
+				break
+			}
+		}
+		if test.best != 0 && notWithin(ranking[0].Value, test.best, 1e-6) {
+			t.Errorf("Test %v failed, expected: '%f', got: '%f'", test.criterion, test.best, ranking[0].Value)
+		}
+	}
+
+	ranking, _ := RankProjects(projects[:2], RankByChainNPV)
+	if notWithin(ranking[0].Value, 138.87029574482597, 1e-9) || notWithin(ranking[1].Value, 84.93272317464624, 1e-9) {
+		t.Errorf("Test failed, expected: '%f' and '%f', got: %+v", 138.87029574482597, 84.93272317464624, ranking)
+	}
+}
+
+func TestRankProjectsErrors(t *testing.T) {
+	tests := []struct {
+		projects  []Project
+		criterion RankingCriterion
+		expected  error
+	}{
+		{[]Project{{CashFlows: []float64{-1, 2}}}, RankingCriterion(9), ErrInvalidRanking},
+		{[]Project{{CashFlows: []float64{-1}}}, RankByNPV, ErrEmptyInput},
+		{[]Project{{CashFlows: []float64{-1}}}, RankByChainNPV, ErrEmptyInput},
+		{[]Project{{CashFlows: []float64{-1, 2}, DiscountRate: -1}}, RankByIRR, ErrInvalidRate},
+		{[]Project{{CashFlows: make([]float64, 98)}, {CashFlows: make([]float64, 102)}, {CashFlows: make([]float64, 104)}}, RankByChainNPV, ErrInvalidRanking},
+	}
+
+	for _, test := range tests {
+		if _, err := RankProjects(test.projects, test.criterion); !errors.Is(err, test.expected) {
+			t.Errorf("Test %v failed, expected: '%v', got: '%v'", test.criterion, test.expected, err)
+		}
+	}
+}
+
+func TestProjectChain(t *testing.T) {
+	chain, err := Project{Name: "a", CashFlows: []float64{-1000, 620, 620}, DiscountRate: 0.1}.Chain(6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []float64{-1000, 620, -380, 620, -380, 620, 620}
+	if chain.Name != "a" || chain.DiscountRate != 0.1 || len(chain.CashFlows) != len(expected) {
+		t.Fatalf("Test failed, expected: %v, got: %+v", expected, chain)
+	}
+	for i := range expected {
+		if chain.CashFlows[i] != expected[i] {
+			t.Errorf("Test failed, expected: %v, got: %v", expected, chain.CashFlows)
+			break
+		}
+	}
+
+	for _, periods := range []int{0, 3, -2} {
+		if _, err := (Project{CashFlows: []float64{-1, 1, 1}}).Chain(periods); !errors.Is(err, ErrInvalidPeriods) {
+			t.Errorf("Test failed for %d, expected: '%v', got: '%v'", periods, ErrInvalidPeriods, err)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package gofin
+
+import (
+	"fmt"
+	"math"
+)
+
+// Compounding is how often interest is compounded, or how often cash flows
+// fall, in a year.
+type Compounding int
+
+const (
+	// CompoundAnnually compounds once a year.
+	CompoundAnnually Compounding = iota
+
+	// CompoundSemiannually compounds twice a year.
+	CompoundSemiannually
+
+	// CompoundQuarterly compounds four times a year.
+	CompoundQuarterly
+
+	// CompoundMonthly compounds twelve times a year.
+	CompoundMonthly
+
+	// CompoundWeekly compounds 52 times a year.
+	CompoundWeekly
+
+	// CompoundDaily compounds 365 times a year.
+	CompoundDaily
+
+	// CompoundContinuously compounds continuously.
+	CompoundContinuously
+)
+
+// PeriodsPerYear returns the number of compounding periods in a year, or 0
+// for CompoundContinuously and unknown frequencies.
+func (c Compounding) PeriodsPerYear() float64 {
+	switch c {
+	case CompoundAnnually:
+		return 1
+	case CompoundSemiannually:
+		return 2
+	case CompoundQuarterly:
+		return 4
+	case CompoundMonthly:
+		return 12
+	case CompoundWeekly:
+		return 52
+	case CompoundDaily:
+		return 365
+	default:
+		return 0
+	}
+}
+
+// check validates the frequency.
+func (c Compounding) check() error {
+	if c < CompoundAnnually || c > CompoundContinuously {
+		return fmt.Errorf("%w: %d", ErrInvalidCompounding, c)
+	}
+	return nil
+}
+
+// Rate is an annual interest rate together with its compounding frequency,
+// for example 6% compounded monthly. Two Rates with different Nominal values
+// can describe the same growth: 6% compounded monthly is the same as 6.1678%
+// compounded annually. The Rate functions convert through the effective
+// annual rate, so a monthly loan rate can be used to discount annual flows,
+// or an annual rate to discount monthly ones, without converting by hand.
+type Rate struct {
+	// Nominal is the annual rate, for example 0.06 for 6%. For periodic
+	// compounding it is the rate per period times the periods per year.
+	Nominal float64
+
+	Compounding Compounding
+}
+
+// EffectiveRate returns the Rate with the given effective annual rate.
+func EffectiveRate(effective float64) Rate {
+	return Rate{Nominal: effective, Compounding: CompoundAnnually}
+}
+
+// Effective returns the effective annual rate, the growth of one unit over a year less one.
+// EAR = (1 + r / m)^m - 1
+// EAR = e^r - 1 for continuous compounding
+// r is the nominal rate,
+// m is the number of compounding periods per year.
+func (r Rate) Effective() (float64, error) {
+	growth, err := r.logGrowth()
+	if err != nil {
+		return 0.0, err
+	}
+	return math.Expm1(growth), nil
+}
+
+// APY returns the annual percentage yield. It is the effective annual rate
+// under the name used for US deposit accounts.
+func (r Rate) APY() (float64, error) {
+	return r.Effective()
+}
+
+// Convert returns the equivalent rate compounded at frequency c.
+// r' = m' * ((1 + EAR)^(1 / m') - 1)
+// r' = ln(1 + EAR) for continuous compounding
+func (r Rate) Convert(c Compounding) (Rate, error) {
+	if err := c.check(); err != nil {
+		return Rate{}, err
+	}
+	growth, err := r.logGrowth()
+	if err != nil {
+		return Rate{}, err
+	}
+	if c == CompoundContinuously {
+		return Rate{Nominal: growth, Compounding: c}, nil
+	}
+	m := c.PeriodsPerYear()
+	return Rate{Nominal: m * math.Expm1(growth/m), Compounding: c}, nil
+}
+
+// Periodic returns the effective rate over one period of frequency, for
+// example the monthly rate to use with monthly payments. It returns
+// ErrInvalidCompounding for CompoundContinuously, which has no period.
+func (r Rate) Periodic(frequency Compounding) (float64, error) {
+	if err := frequency.check(); err != nil {
+		return 0.0, err
+	}
+	if frequency == CompoundContinuously {
+		return 0.0, fmt.Errorf("%w: continuous compounding has no period", ErrInvalidCompounding)
+	}
+	growth, err := r.logGrowth()
+	if err != nil {
+		return 0.0, err
+	}
+	return math.Expm1(growth / frequency.PeriodsPerYear()), nil
+}
+
+// Growth returns the growth of one unit over a number of years, which need
+// not be whole.
+func (r Rate) Growth(years float64) (float64, error) {
+	growth, err := r.logGrowth()
+	if err != nil {
+		return 0.0, err
+	}
+	return math.Exp(growth * years), nil
+}
+
+// logGrowth returns the logarithm of the growth of one unit over a year.
+func (r Rate) logGrowth() (float64, error) {
+	if err := r.Compounding.check(); err != nil {
+		return 0.0, err
+	}
+	if r.Compounding == CompoundContinuously {
+		return r.Nominal, nil
+	}
+	m := r.Compounding.PeriodsPerYear()
+	if r.Nominal/m <= -1 {
+		return 0.0, ErrInvalidRate
+	}
+	return m * math.Log1p(r.Nominal/m), nil
+}
+
+// FutureValueRate returns the value after a number of years, which need not
+// be whole, of a present amount growing at rate.
+// FV = PV * (1 + r / m)^(m * t)
+// FV = PV * e^(r * t) for continuous compounding
+// PV is the present value,
+// r is the nominal rate,
+// m is the number of compounding periods per year,
+// t is the number of years.
+func FutureValueRate(presentValue float64, rate Rate, years float64) float64 {
+	fv, _ := FutureValueRateE(presentValue, rate, years)
+	return fv
+}
+
+// FutureValueRateE is like FutureValueRate but returns an error for an invalid rate or compounding frequency.
+func FutureValueRateE(presentValue float64, rate Rate, years float64) (float64, error) {
+	growth, err := rate.Growth(years)
+	if err != nil {
+		return 0.0, err
+	}
+	return presentValue * growth, nil
+}
+
+// PresentValueRate returns the value today of an amount due after a number of
+// years, which need not be whole, discounted at rate.
+// PV = FV / (1 + r / m)^(m * t)
+// PV = FV * e^(-r * t) for continuous compounding
+func PresentValueRate(futureValue float64, rate Rate, years float64) float64 {
+	pv, _ := PresentValueRateE(futureValue, rate, years)
+	return pv
+}
+
+// PresentValueRateE is like PresentValueRate but returns an error for an invalid rate or compounding frequency.
+func PresentValueRateE(futureValue float64, rate Rate, years float64) (float64, error) {
+	growth, err := rate.Growth(-years)
+	if err != nil {
+		return 0.0, err
+	}
+	return futureValue * growth, nil
+}
+
+// NetPresentValueRate returns the net present value of cash flows spaced one
+// period of frequency apart, with the first at time zero as in
+// NetPresentValue, discounted at rate whatever its own compounding.
+// NPV = sum(C / (1 + p)^t)
+// p is the effective rate per period of frequency,
+// t is the period of the cash flow, starting at 0.
+func NetPresentValueRate(rate Rate, frequency Compounding, cashFlows []float64) float64 {
+	npv, _ := NetPresentValueRateE(rate, frequency, cashFlows)
+	return npv
+}
+
+// NetPresentValueRateE is like NetPresentValueRate but returns an error for an
+// invalid rate, or for a continuous or unknown frequency.
+func NetPresentValueRateE(rate Rate, frequency Compounding, cashFlows []float64) (float64, error) {
+	periodic, err := rate.Periodic(frequency)
+	if err != nil {
+		return 0.0, err
+	}
+	return NetPresentValueE(periodic, len(cashFlows), cashFlows)
+}
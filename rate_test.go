@@ -0,0 +1,138 @@
+package gofin
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestRateEffective(t *testing.T) {
+	tests := []struct {
+		rate     Rate
+		expected float64
+	}{
+		{Rate{Nominal: 0.12, Compounding: CompoundAnnually}, 0.12},
+		{Rate{Nominal: 0.12, Compounding: CompoundSemiannually}, 0.1236},
+		{Rate{Nominal: 0.12, Compounding: CompoundQuarterly}, 0.12550881},
+		{Rate{Nominal: 0.12, Compounding: CompoundMonthly}, 0.12682503013196977},
+		{Rate{Nominal: 0.12, Compounding: CompoundDaily}, math.Pow(1+0.12/365, 365) - 1},
+		{Rate{Nominal: 0.12, Compounding: CompoundContinuously}, math.Exp(0.12) - 1},
+	}
+
+	for _, test := range tests {
+		actual, err := test.rate.Effective()
+		if err != nil || notWithin(actual, test.expected, 1e-12) {
+			t.Errorf("Test failed for %+v, expected: '%f', got: '%f' (%v)", test.rate, test.expected, actual, err)
+		}
+		apy, _ := test.rate.APY()
+		if apy != actual {
+			t.Errorf("Test failed for %+v, expected APY to equal the effective rate, got: '%f'", test.rate, apy)
+		}
+	}
+}
+
+func TestRateConvert(t *testing.T) {
+	monthly := Rate{Nominal: 0.06, Compounding: CompoundMonthly}
+
+	annual, err := monthly.Convert(CompoundAnnually)
+	if err != nil || notWithin(annual.Nominal, 0.061677811864497, 1e-12) || annual.Compounding != CompoundAnnually {
+		t.Errorf("Test failed, expected: '%f', got: %+v (%v)", 0.061677811864497, annual, err)
+	}
+
+	continuous, _ := monthly.Convert(CompoundContinuously)
+	if notWithin(continuous.Nominal, 12*math.Log1p(0.005), 1e-12) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", 12*math.Log1p(0.005), continuous.Nominal)
+	}
+
+	// Converting between any two frequencies keeps the effective rate.
+	for c := CompoundAnnually; c <= CompoundContinuously; c++ {
+		converted, err := continuous.Convert(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		back, _ := converted.Convert(CompoundMonthly)
+		if notWithin(back.Nominal, 0.06, 1e-12) {
+			t.Errorf("Test failed for frequency %d, expected: '%f', got: '%f'", c, 0.06, back.Nominal)
+		}
+	}
+
+	if actual := EffectiveRate(0.0609); actual != (Rate{Nominal: 0.0609, Compounding: CompoundAnnually}) {
+		t.Errorf("Test failed, got: %+v", actual)
+	}
+}
+
+func TestRatePeriodic(t *testing.T) {
+	// A 6% monthly loan rate is 0.5% a month.
+	actual, err := Rate{Nominal: 0.06, Compounding: CompoundMonthly}.Periodic(CompoundMonthly)
+	if err != nil || notWithin(actual, 0.005, 1e-15) {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", 0.005, actual, err)
+	}
+
+	// A 10% effective annual rate is about 0.797% a month.
+	actual, _ = EffectiveRate(0.1).Periodic(CompoundMonthly)
+	if notWithin(actual, math.Pow(1.1, 1.0/12)-1, 1e-15) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", math.Pow(1.1, 1.0/12)-1, actual)
+	}
+
+	if _, err := EffectiveRate(0.1).Periodic(CompoundContinuously); !errors.Is(err, ErrInvalidCompounding) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidCompounding, err)
+	}
+}
+
+func TestFutureValueRate(t *testing.T) {
+	monthly := Rate{Nominal: 0.06, Compounding: CompoundMonthly}
+
+	expected := 1000 * math.Pow(1.005, 120)
+	if actual := FutureValueRate(1000, monthly, 10); notWithin(actual, expected, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", expected, actual)
+	}
+
+	// Fractional years compound continuously at the same effective rate.
+	if actual := FutureValueRate(100, Rate{Nominal: 0.05, Compounding: CompoundContinuously}, 2.5); notWithin(actual, 100*math.Exp(0.125), 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", 100*math.Exp(0.125), actual)
+	}
+
+	// The annual rate matches FutureValue over whole years.
+	if actual := FutureValueRate(1000, EffectiveRate(0.05), 10); notWithin(actual, FutureValue(1000, 0.05, 10), 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", FutureValue(1000, 0.05, 10), actual)
+	}
+
+	if actual := PresentValueRate(expected, monthly, 10); notWithin(actual, 1000, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", 1000.0, actual)
+	}
+}
+
+func TestNetPresentValueRate(t *testing.T) {
+	// Monthly flows discounted at a monthly rate need no conversion.
+	cashFlows := []float64{-1000, 300, 300, 300, 300}
+	monthly := Rate{Nominal: 0.12, Compounding: CompoundMonthly}
+	actual, err := NetPresentValueRateE(monthly, CompoundMonthly, cashFlows)
+	if expected := NetPresentValue(0.01, 5, cashFlows); err != nil || notWithin(actual, expected, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", expected, actual, err)
+	}
+
+	// Annual flows discounted at the same monthly rate use its effective annual rate.
+	actual, _ = NetPresentValueRateE(monthly, CompoundAnnually, cashFlows)
+	effective, _ := monthly.Effective()
+	if expected := NetPresentValue(effective, 5, cashFlows); notWithin(actual, expected, 1e-9) {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", expected, actual)
+	}
+}
+
+func TestRateErrors(t *testing.T) {
+	if _, err := (Rate{Nominal: -12, Compounding: CompoundMonthly}).Effective(); !errors.Is(err, ErrInvalidRate) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidRate, err)
+	}
+	if _, err := (Rate{Nominal: 0.05, Compounding: Compounding(99)}).Effective(); !errors.Is(err, ErrInvalidCompounding) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidCompounding, err)
+	}
+	if _, err := EffectiveRate(0.05).Convert(Compounding(-1)); !errors.Is(err, ErrInvalidCompounding) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidCompounding, err)
+	}
+	if _, err := NetPresentValueRateE(EffectiveRate(0.05), CompoundContinuously, []float64{1}); !errors.Is(err, ErrInvalidCompounding) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidCompounding, err)
+	}
+	if actual := FutureValueRate(100, Rate{Nominal: -1}, 1); actual != 0.0 {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", 0.0, actual)
+	}
+}
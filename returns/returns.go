@@ -0,0 +1,402 @@
+// Package returns computes risk and performance statistics of a series of
+// periodic returns, such as volatility, Sharpe and Sortino ratios, maximum
+// drawdown and statistics relative to a benchmark.
+//
+// Every statistic that is quoted per year is annualized with the series'
+// PeriodsPerYear: means are multiplied by it and deviations by its square
+// root. Risk-free rates and minimum acceptable returns are given as annual
+// rates and converted to a per-period rate by compounding, so an annual rate R
+// becomes (1 + R)^(1 / PeriodsPerYear) - 1 each period.
+package returns
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Errors returned by the statistics.
+var (
+	// ErrEmptySeries is returned when a series has too few returns for the statistic.
+	ErrEmptySeries = errors.New("returns: not enough returns")
+
+	// ErrLengthMismatch is returned when a series and its dates or its benchmark differ in length,
+	// or a benchmark has a different PeriodsPerYear.
+	ErrLengthMismatch = errors.New("returns: series lengths do not match")
+
+	// ErrInvalidPeriodsPerYear is returned when PeriodsPerYear is not positive.
+	ErrInvalidPeriodsPerYear = errors.New("returns: periods per year must be positive")
+
+	// ErrInvalidReturn is returned for a return or price that gives a non-positive wealth.
+	ErrInvalidReturn = errors.New("returns: return must be greater than -1")
+
+	// ErrUndefined is returned when a ratio's denominator is zero, for example
+	// the Sharpe ratio of a series with no volatility.
+	ErrUndefined = errors.New("returns: ratio is undefined")
+)
+
+// Common annualization factors.
+const (
+	Daily     = 252
+	Weekly    = 52
+	Monthly   = 12
+	Quarterly = 4
+	Annual    = 1
+)
+
+// Series is a sequence of periodic simple returns, for example 0.01 for 1%.
+type Series struct {
+	// Returns holds one return per period, oldest first.
+	Returns []float64
+
+	// Dates optionally holds the end date of each period. When set it must
+	// have the same length as Returns; it is used to date drawdowns.
+	Dates []time.Time
+
+	// PeriodsPerYear is the annualization factor, for example Monthly.
+	PeriodsPerYear float64
+}
+
+// FromPrices returns the series of returns between consecutive prices. When
+// dates are given they are the dates of the prices, so the series is dated
+// from the second price on.
+func FromPrices(prices []float64, dates []time.Time, periodsPerYear float64) (Series, error) {
+	if len(prices) < 2 {
+		return Series{}, ErrEmptySeries
+	}
+	if dates != nil && len(dates) != len(prices) {
+		return Series{}, ErrLengthMismatch
+	}
+
+	s := Series{Returns: make([]float64, len(prices)-1), PeriodsPerYear: periodsPerYear}
+	for i := 1; i < len(prices); i++ {
+		if prices[i-1] <= 0 {
+			return Series{}, fmt.Errorf("%w: price %d is not positive", ErrInvalidReturn, i-1)
+		}
+		s.Returns[i-1] = prices[i]/prices[i-1] - 1
+	}
+	if dates != nil {
+		s.Dates = dates[1:]
+	}
+	return s, s.check(1)
+}
+
+// Len returns the number of returns in the series.
+func (s Series) Len() int {
+	return len(s.Returns)
+}
+
+// Mean returns the arithmetic mean return per period.
+func (s Series) Mean() (float64, error) {
+	if err := s.check(1); err != nil {
+		return 0.0, err
+	}
+	return mean(s.Returns), nil
+}
+
+// CumulativeReturn returns the compounded return over the whole series.
+// R = prod(1 + r_t) - 1
+func (s Series) CumulativeReturn() (float64, error) {
+	if err := s.check(1); err != nil {
+		return 0.0, err
+	}
+	growth := 1.0
+	for _, r := range s.Returns {
+		growth *= 1 + r
+	}
+	return growth - 1, nil
+}
+
+// AnnualizedReturn returns the compound annual growth rate of the series.
+// R = prod(1 + r_t)^(P / n) - 1
+// P is the number of periods per year,
+// n is the number of returns.
+func (s Series) AnnualizedReturn() (float64, error) {
+	cumulative, err := s.CumulativeReturn()
+	if err != nil {
+		return 0.0, err
+	}
+	if cumulative <= -1 {
+		return 0.0, ErrInvalidReturn
+	}
+	return math.Pow(1+cumulative, s.PeriodsPerYear/float64(s.Len())) - 1, nil
+}
+
+// StdDev returns the sample standard deviation of the returns per period.
+func (s Series) StdDev() (float64, error) {
+	if err := s.check(2); err != nil {
+		return 0.0, err
+	}
+	return stdDev(s.Returns), nil
+}
+
+// Volatility returns the annualized standard deviation of the returns.
+// sigma = StdDev * sqrt(P)
+func (s Series) Volatility() (float64, error) {
+	sd, err := s.StdDev()
+	if err != nil {
+		return 0.0, err
+	}
+	return sd * math.Sqrt(s.PeriodsPerYear), nil
+}
+
+// DownsideDeviation returns the annualized downside deviation below the
+// annual minimum acceptable return, counting only the periods that fall short.
+// DD = sqrt(sum(min(0, r_t - m)^2) / n) * sqrt(P)
+// m is the minimum acceptable return per period.
+func (s Series) DownsideDeviation(minimumAcceptable float64) (float64, error) {
+	if err := s.check(1); err != nil {
+		return 0.0, err
+	}
+	target := s.periodic(minimumAcceptable)
+	sum := 0.0
+	for _, r := range s.Returns {
+		if r < target {
+			sum += (r - target) * (r - target)
+		}
+	}
+	return math.Sqrt(sum/float64(s.Len())) * math.Sqrt(s.PeriodsPerYear), nil
+}
+
+// SharpeRatio returns the annualized mean excess return over the annual
+// risk-free rate divided by the annualized volatility of the excess returns.
+func (s Series) SharpeRatio(riskFree float64) (float64, error) {
+	if err := s.check(2); err != nil {
+		return 0.0, err
+	}
+	excess := s.excess(riskFree)
+	return ratio(mean(excess)*s.PeriodsPerYear, stdDev(excess)*math.Sqrt(s.PeriodsPerYear))
+}
+
+// SortinoRatio returns the annualized mean return in excess of the annual
+// minimum acceptable return divided by the downside deviation below it.
+func (s Series) SortinoRatio(minimumAcceptable float64) (float64, error) {
+	downside, err := s.DownsideDeviation(minimumAcceptable)
+	if err != nil {
+		return 0.0, err
+	}
+	return ratio(mean(s.excess(minimumAcceptable))*s.PeriodsPerYear, downside)
+}
+
+// CalmarRatio returns the annualized return divided by the depth of the maximum drawdown.
+func (s Series) CalmarRatio() (float64, error) {
+	annualized, err := s.AnnualizedReturn()
+	if err != nil {
+		return 0.0, err
+	}
+	drawdown, err := s.MaxDrawdown()
+	if err != nil {
+		return 0.0, err
+	}
+	return ratio(annualized, drawdown.Depth)
+}
+
+// Drawdown is a fall in the wealth index from a peak to a later trough.
+// Peak, Trough and Recovery index the period at whose end the event happens;
+// -1 stands for the start of the series, before the first return.
+type Drawdown struct {
+	// Depth is the fall from peak to trough as a positive fraction of the peak.
+	Depth float64
+
+	Peak   int
+	Trough int
+	// Recovery is the first period at which the wealth index is back at the
+	// peak, or -1 if it has not recovered by the end of the series.
+	Recovery int
+
+	// PeakDate, TroughDate and RecoveryDate are set when the series has Dates
+	// and the event falls on one of them.
+	PeakDate     time.Time
+	TroughDate   time.Time
+	RecoveryDate time.Time
+}
+
+// MaxDrawdown returns the largest peak-to-trough fall of the wealth index
+// built by compounding the returns from 1. A series that never falls returns
+// a zero Depth.
+func (s Series) MaxDrawdown() (Drawdown, error) {
+	if err := s.check(1); err != nil {
+		return Drawdown{}, err
+	}
+
+	worst := Drawdown{Peak: -1, Trough: -1, Recovery: -1}
+	wealth, peakWealth, peak := 1.0, 1.0, -1
+	for t, r := range s.Returns {
+		wealth *= 1 + r
+		if wealth >= peakWealth {
+			if worst.Recovery == -1 && worst.Peak == peak && worst.Depth > 0 {
+				worst.Recovery = t
+			}
+			peakWealth, peak = wealth, t
+			continue
+		}
+		if depth := 1 - wealth/peakWealth; depth > worst.Depth {
+			worst = Drawdown{Depth: depth, Peak: peak, Trough: t, Recovery: -1}
+		}
+	}
+
+	worst.PeakDate = s.date(worst.Peak)
+	worst.TroughDate = s.date(worst.Trough)
+	worst.RecoveryDate = s.date(worst.Recovery)
+	return worst, nil
+}
+
+// Beta returns the sensitivity of the series to the benchmark: the covariance
+// of their returns divided by the variance of the benchmark's.
+func (s Series) Beta(benchmark Series) (float64, error) {
+	if err := s.checkBenchmark(benchmark); err != nil {
+		return 0.0, err
+	}
+	return beta(s.Returns, benchmark.Returns)
+}
+
+// Alpha returns Jensen's alpha, the annualized mean excess return of the
+// series not explained by its beta to the benchmark. A benchmark without a
+// PeriodsPerYear is taken at the series' one.
+// alpha = (mean(r - rf) - beta * mean(b - rf)) * P
+func (s Series) Alpha(benchmark Series, riskFree float64) (float64, error) {
+	b, err := s.Beta(benchmark)
+	if err != nil {
+		return 0.0, err
+	}
+	rate := s.periodic(riskFree)
+	return (mean(s.excess(riskFree)) - b*(mean(benchmark.Returns)-rate)) * s.PeriodsPerYear, nil
+}
+
+// TreynorRatio returns the annualized mean excess return over the annual
+// risk-free rate divided by the beta to the benchmark.
+func (s Series) TreynorRatio(benchmark Series, riskFree float64) (float64, error) {
+	b, err := s.Beta(benchmark)
+	if err != nil {
+		return 0.0, err
+	}
+	return ratio(mean(s.excess(riskFree))*s.PeriodsPerYear, b)
+}
+
+// TrackingError returns the annualized standard deviation of the difference
+// between the series' and the benchmark's returns.
+func (s Series) TrackingError(benchmark Series) (float64, error) {
+	if err := s.checkBenchmark(benchmark); err != nil {
+		return 0.0, err
+	}
+	return stdDev(active(s.Returns, benchmark.Returns)) * math.Sqrt(s.PeriodsPerYear), nil
+}
+
+// InformationRatio returns the annualized mean active return over the
+// benchmark divided by the tracking error.
+func (s Series) InformationRatio(benchmark Series) (float64, error) {
+	trackingError, err := s.TrackingError(benchmark)
+	if err != nil {
+		return 0.0, err
+	}
+	return ratio(mean(active(s.Returns, benchmark.Returns))*s.PeriodsPerYear, trackingError)
+}
+
+// check validates the series and requires at least minLen returns.
+func (s Series) check(minLen int) error {
+	if s.PeriodsPerYear <= 0 {
+		return ErrInvalidPeriodsPerYear
+	}
+	if s.Len() < minLen {
+		return ErrEmptySeries
+	}
+	if s.Dates != nil && len(s.Dates) != s.Len() {
+		return ErrLengthMismatch
+	}
+	return nil
+}
+
+// checkBenchmark validates the series and a benchmark of the same periods.
+// A benchmark without a PeriodsPerYear is taken at the series' one.
+func (s Series) checkBenchmark(benchmark Series) error {
+	if err := s.check(2); err != nil {
+		return err
+	}
+	if benchmark.Len() != s.Len() {
+		return ErrLengthMismatch
+	}
+	if benchmark.Dates != nil && len(benchmark.Dates) != benchmark.Len() {
+		return fmt.Errorf("%w: the benchmark has %d dates for %d returns", ErrLengthMismatch, len(benchmark.Dates), benchmark.Len())
+	}
+	if benchmark.PeriodsPerYear != 0 && benchmark.PeriodsPerYear != s.PeriodsPerYear {
+		return fmt.Errorf("%w: the benchmark has %g periods per year, the series %g", ErrLengthMismatch, benchmark.PeriodsPerYear, s.PeriodsPerYear)
+	}
+	if s.Dates != nil && benchmark.Dates != nil {
+		for i := range s.Dates {
+			if !s.Dates[i].Equal(benchmark.Dates[i]) {
+				return fmt.Errorf("%w: dates differ at period %d", ErrLengthMismatch, i)
+			}
+		}
+	}
+	return nil
+}
+
+// periodic converts an annual rate into the equivalent rate per period.
+func (s Series) periodic(annual float64) float64 {
+	return math.Pow(1+annual, 1/s.PeriodsPerYear) - 1
+}
+
+// excess returns the returns less the periodic equivalent of an annual rate.
+func (s Series) excess(annual float64) []float64 {
+	rate := s.periodic(annual)
+	excess := make([]float64, s.Len())
+	for i, r := range s.Returns {
+		excess[i] = r - rate
+	}
+	return excess
+}
+
+// date returns the date of period t, or the zero time if there is none.
+func (s Series) date(t int) time.Time {
+	if t < 0 || t >= len(s.Dates) {
+		return time.Time{}
+	}
+	return s.Dates[t]
+}
+
+// mean returns the arithmetic mean of values.
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// stdDev returns the sample standard deviation of values.
+func stdDev(values []float64) float64 {
+	return math.Sqrt(covariance(values, values))
+}
+
+// covariance returns the sample covariance of a and b.
+func covariance(a, b []float64) float64 {
+	meanA, meanB := mean(a), mean(b)
+	sum := 0.0
+	for i := range a {
+		sum += (a[i] - meanA) * (b[i] - meanB)
+	}
+	return sum / float64(len(a)-1)
+}
+
+// beta returns the covariance of a and b divided by the variance of b.
+func beta(a, b []float64) (float64, error) {
+	return ratio(covariance(a, b), covariance(b, b))
+}
+
+// active returns the element-wise difference a - b.
+func active(a, b []float64) []float64 {
+	diff := make([]float64, len(a))
+	for i := range a {
+		diff[i] = a[i] - b[i]
+	}
+	return diff
+}
+
+// ratio returns numerator / denominator, or ErrUndefined for a zero denominator.
+func ratio(numerator, denominator float64) (float64, error) {
+	if denominator == 0 {
+		return 0.0, ErrUndefined
+	}
+	return numerator / denominator, nil
+}
@@ -0,0 +1,130 @@
+package returns
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+var (
+	portfolio = Series{Returns: []float64{0.10, -0.05, 0.02, -0.10, 0.08, 0.03}, PeriodsPerYear: Monthly}
+	benchmark = Series{Returns: []float64{0.05, -0.02, 0.01, -0.06, 0.04, 0.02}, PeriodsPerYear: Monthly}
+)
+
+func TestStatistics(t *testing.T) {
+	tests := []struct {
+		name     string
+		stat     func() (float64, error)
+		expected float64
+	}{
+		{"Mean", portfolio.Mean, 0.0133333333333333},
+		{"StdDev", portfolio.StdDev, 0.0763326055278258},
+		{"Volatility", portfolio.Volatility, 0.264423902096615},
+		{"CumulativeReturn", portfolio.CumulativeReturn, 0.067136444},
+		{"AnnualizedReturn", portfolio.AnnualizedReturn, 0.138780190112966},
+		{"SharpeRatio", func() (float64, error) { return portfolio.SharpeRatio(0.03) }, 0.493165563696692},
+		{"DownsideDeviation", func() (float64, error) { return portfolio.DownsideDeviation(0) }, 0.158113883008419},
+		{"SortinoRatio", func() (float64, error) { return portfolio.SortinoRatio(0) }, 1.01192885125388},
+		{"CalmarRatio", portfolio.CalmarRatio, 1.08506794458925},
+		{"Beta", func() (float64, error) { return portfolio.Beta(benchmark) }, 1.856},
+		{"Alpha", func() (float64, error) { return portfolio.Alpha(benchmark, 0.03) }, 0.0368535231011034},
+		{"Alpha without benchmark frequency", func() (float64, error) { return portfolio.Alpha(Series{Returns: benchmark.Returns}, 0.03) }, 0.0368535231011034},
+		{"TreynorRatio", func() (float64, error) { return portfolio.TreynorRatio(benchmark, 0.03) }, 0.070261186817002},
+		{"TrackingError", func() (float64, error) { return portfolio.TrackingError(benchmark) }, 0.125219806739988},
+		{"InformationRatio", func() (float64, error) { return portfolio.InformationRatio(benchmark) }, 0.63887656499994},
+	}
+
+	for _, tt := range tests {
+		actual, err := tt.stat()
+		if err != nil || math.Abs(actual-tt.expected) > 1e-12 {
+			t.Errorf("%s failed, expected: '%f', got: '%f' (%v)", tt.name, tt.expected, actual, err)
+		}
+	}
+}
+
+func TestMaxDrawdown(t *testing.T) {
+	dates := make([]time.Time, portfolio.Len())
+	for i := range dates {
+		dates[i] = time.Date(2024, time.Month(i+1), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, -1)
+	}
+	s := portfolio
+	s.Dates = dates
+
+	dd, err := s.MaxDrawdown()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(dd.Depth-0.1279) > 1e-12 || dd.Peak != 0 || dd.Trough != 3 || dd.Recovery != -1 {
+		t.Errorf("Test failed, got: %+v", dd)
+	}
+	if !dd.PeakDate.Equal(dates[0]) || !dd.TroughDate.Equal(dates[3]) || !dd.RecoveryDate.IsZero() {
+		t.Errorf("Test failed, got dates: %v %v %v", dd.PeakDate, dd.TroughDate, dd.RecoveryDate)
+	}
+
+	// A fall from the start that is later recovered.
+	dd, err = Series{Returns: []float64{-0.2, 0.1, 0.2, -0.05}, PeriodsPerYear: Annual}.MaxDrawdown()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(dd.Depth-0.2) > 1e-12 || dd.Peak != -1 || dd.Trough != 0 || dd.Recovery != 2 {
+		t.Errorf("Test failed, got: %+v", dd)
+	}
+
+	dd, _ = Series{Returns: []float64{0.01, 0.02}, PeriodsPerYear: Annual}.MaxDrawdown()
+	if dd.Depth != 0 {
+		t.Errorf("Test failed, expected no drawdown, got: %+v", dd)
+	}
+}
+
+func TestFromPrices(t *testing.T) {
+	dates := []time.Time{
+		time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC),
+	}
+	s, err := FromPrices([]float64{100, 110, 99}, dates, Daily)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Len() != 2 || math.Abs(s.Returns[0]-0.1) > 1e-12 || math.Abs(s.Returns[1]+0.1) > 1e-12 {
+		t.Errorf("Test failed, got: %v", s.Returns)
+	}
+	if !s.Dates[0].Equal(dates[1]) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", dates[1], s.Dates[0])
+	}
+
+	if _, err := FromPrices([]float64{100, 0, 99}, nil, Daily); !errors.Is(err, ErrInvalidReturn) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidReturn, err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if _, err := (Series{Returns: []float64{0.1}}).Mean(); !errors.Is(err, ErrInvalidPeriodsPerYear) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidPeriodsPerYear, err)
+	}
+	if _, err := (Series{Returns: []float64{0.1}, PeriodsPerYear: Monthly}).StdDev(); !errors.Is(err, ErrEmptySeries) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrEmptySeries, err)
+	}
+	short := Series{Returns: benchmark.Returns[:3], PeriodsPerYear: Monthly}
+	if _, err := portfolio.Beta(short); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrLengthMismatch, err)
+	}
+	dates := []time.Time{
+		time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+	}
+	series := Series{Returns: portfolio.Returns[:3], Dates: dates, PeriodsPerYear: Monthly}
+	if _, err := series.Beta(Series{Returns: benchmark.Returns[:3], Dates: dates[:1]}); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrLengthMismatch, err)
+	}
+	quarterly := Series{Returns: benchmark.Returns, PeriodsPerYear: Quarterly}
+	if _, err := portfolio.Alpha(quarterly, 0.03); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrLengthMismatch, err)
+	}
+	flat := Series{Returns: []float64{0.01, 0.01, 0.01}, PeriodsPerYear: Monthly}
+	if _, err := flat.SharpeRatio(0); !errors.Is(err, ErrUndefined) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrUndefined, err)
+	}
+}
@@ -0,0 +1,79 @@
+package sensitivity
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// WriteCSV writes one line per bar, with the columns input, low_value,
+// high_value, low_output, base_output, high_output and swing.
+func (t Tornado) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"input", "low_value", "high_value", "low_output", "base_output", "high_output", "swing"})
+	for _, bar := range t.Bars {
+		writer.Write([]string{bar.Input, format(bar.LowValue), format(bar.HighValue), format(bar.LowOutput), format(t.Base), format(bar.HighOutput), format(bar.Swing)})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteCSV writes one line per value, with the columns named after the input
+// and output.
+func (s Sweep) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{s.Input, "output"})
+	for i, value := range s.Values {
+		writer.Write([]string{format(value), format(s.Outputs[i])})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteCSV writes the table as a grid, the way a spreadsheet lays out a data
+// table: the header holds "row\column" and the column values, and each line
+// starts with its row value.
+func (t Table) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	header := []string{t.Row + `\` + t.Column}
+	for _, value := range t.ColumnValues {
+		header = append(header, format(value))
+	}
+	writer.Write(header)
+	for i, value := range t.RowValues {
+		record := []string{format(value)}
+		for _, output := range t.Outputs[i] {
+			record = append(record, format(output))
+		}
+		writer.Write(record)
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteCSV writes one line per scenario, with the columns scenario, each
+// input in sorted order, output and change. Add a scenario without inputs to
+// list the base case.
+func (s Scenarios) WriteCSV(w io.Writer) error {
+	var names []string
+	if len(s.Results) > 0 {
+		names = s.Results[0].Inputs.names()
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Write(append(append([]string{"scenario"}, names...), "output", "change"))
+	for _, result := range s.Results {
+		record := []string{result.Name}
+		for _, name := range names {
+			record = append(record, format(result.Inputs[name]))
+		}
+		writer.Write(append(record, format(result.Output), format(result.Change)))
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// format formats a number in its shortest exact form.
+func format(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
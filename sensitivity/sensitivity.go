@@ -0,0 +1,324 @@
+// Package sensitivity runs what-if analyses over any model with named
+// numeric inputs: tornado charts, one- and two-way data tables and named
+// scenarios such as base, best and worst case.
+//
+// A model is a plain function, so any gofin calculation can be analysed by
+// wrapping it:
+//
+//	model := func(in sensitivity.Inputs) (float64, error) {
+//		flows := []float64{-in["investment"], in["revenue"], in["revenue"]}
+//		return gofin.NetPresentValueE(in["rate"], len(flows), flows)
+//	}
+//
+// The results carry JSON tags and a WriteCSV method for export.
+package sensitivity
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Errors returned by the package.
+var (
+	// ErrNoModel is returned by an Analysis without a model.
+	ErrNoModel = errors.New("sensitivity: no model")
+
+	// ErrUnknownInput is returned for a variation, table or scenario that
+	// names an input missing from the base inputs.
+	ErrUnknownInput = errors.New("sensitivity: unknown input")
+
+	// ErrInvalidVariation is returned for an unknown Shift or a grid of fewer
+	// than two steps.
+	ErrInvalidVariation = errors.New("sensitivity: invalid variation")
+)
+
+// Inputs maps the names of a model's inputs to their values.
+type Inputs map[string]float64
+
+// with returns a copy of the inputs with the given values replaced.
+func (in Inputs) with(overrides Inputs) Inputs {
+	copied := make(Inputs, len(in))
+	for name, value := range in {
+		copied[name] = value
+	}
+	for name, value := range overrides {
+		copied[name] = value
+	}
+	return copied
+}
+
+// names returns the input names in sorted order.
+func (in Inputs) names() []string {
+	names := make([]string, 0, len(in))
+	for name := range in {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Model computes one output, such as an NPV, from a set of inputs. It must
+// not modify the inputs.
+type Model func(Inputs) (float64, error)
+
+// Shift says how a Variation's Low and High apply to the base value of an input.
+type Shift int
+
+const (
+	// Absolute adds Low and High to the base value: a discount rate of 0.08
+	// varied by -0.02 and 0.02 ranges over 0.06 to 0.10.
+	Absolute Shift = iota
+
+	// Relative scales the base value by 1 + Low and 1 + High: revenue of
+	// 1000 varied by -0.1 and 0.1 ranges over 900 to 1100.
+	Relative
+
+	// Replace uses Low and High as the values themselves.
+	Replace
+)
+
+// Variation is the range over which one input varies.
+type Variation struct {
+	Input     string
+	Low, High float64
+	Shift     Shift
+}
+
+// values returns the lowest and highest values of the input.
+func (v Variation) values(base float64) (float64, float64, error) {
+	switch v.Shift {
+	case Absolute:
+		return base + v.Low, base + v.High, nil
+	case Relative:
+		return base * (1 + v.Low), base * (1 + v.High), nil
+	case Replace:
+		return v.Low, v.High, nil
+	}
+	return 0.0, 0.0, fmt.Errorf("%w: unknown shift %d for %q", ErrInvalidVariation, v.Shift, v.Input)
+}
+
+// Steps returns the values of the input at steps evenly spaced points from
+// its low to its high value, for use in a data table.
+func (v Variation) Steps(base float64, steps int) ([]float64, error) {
+	if steps < 2 {
+		return nil, fmt.Errorf("%w: %d steps for %q", ErrInvalidVariation, steps, v.Input)
+	}
+	low, high, err := v.values(base)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]float64, steps)
+	for i := range values {
+		values[i] = low + (high-low)*float64(i)/float64(steps-1)
+	}
+	values[steps-1] = high
+	return values, nil
+}
+
+// Analysis evaluates a model around a set of base inputs.
+type Analysis struct {
+	Model Model
+	Base  Inputs
+}
+
+// evaluate runs the model with some inputs overridden. A model error is
+// wrapped with the overridden inputs.
+func (a Analysis) evaluate(overrides Inputs) (float64, error) {
+	output, err := a.Model(a.Base.with(overrides))
+	if err != nil {
+		if len(overrides) == 0 {
+			return 0.0, fmt.Errorf("sensitivity: base inputs: %w", err)
+		}
+		return 0.0, fmt.Errorf("sensitivity: inputs %v: %w", overrides, err)
+	}
+	return output, nil
+}
+
+// check returns an error for an analysis without a model or an input name
+// missing from the base inputs.
+func (a Analysis) check(inputs ...string) error {
+	if a.Model == nil {
+		return ErrNoModel
+	}
+	for _, input := range inputs {
+		if _, ok := a.Base[input]; !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownInput, input)
+		}
+	}
+	return nil
+}
+
+// Bar is one bar of a tornado chart: the model output with one input at its
+// low and high values and every other input at its base value.
+type Bar struct {
+	Input      string  `json:"input"`
+	LowValue   float64 `json:"low_value"`
+	HighValue  float64 `json:"high_value"`
+	LowOutput  float64 `json:"low_output"`
+	HighOutput float64 `json:"high_output"`
+
+	// Swing is the distance between the two outputs.
+	Swing float64 `json:"swing"`
+}
+
+// Tornado is the data of a tornado chart.
+type Tornado struct {
+	// Base is the output at the base inputs.
+	Base float64 `json:"base"`
+
+	// Bars are ordered by decreasing swing, so the input the output is most
+	// sensitive to comes first. Ties keep the order of the variations.
+	Bars []Bar `json:"bars"`
+}
+
+// Tornado varies each input in turn over its range, holding the others at
+// their base values.
+func (a Analysis) Tornado(variations []Variation) (Tornado, error) {
+	inputs := make([]string, len(variations))
+	for i, v := range variations {
+		inputs[i] = v.Input
+	}
+	if err := a.check(inputs...); err != nil {
+		return Tornado{}, err
+	}
+
+	base, err := a.evaluate(nil)
+	if err != nil {
+		return Tornado{}, err
+	}
+	tornado := Tornado{Base: base, Bars: make([]Bar, len(variations))}
+	for i, v := range variations {
+		low, high, err := v.values(a.Base[v.Input])
+		if err != nil {
+			return Tornado{}, err
+		}
+		bar := Bar{Input: v.Input, LowValue: low, HighValue: high}
+		if bar.LowOutput, err = a.evaluate(Inputs{v.Input: low}); err != nil {
+			return Tornado{}, err
+		}
+		if bar.HighOutput, err = a.evaluate(Inputs{v.Input: high}); err != nil {
+			return Tornado{}, err
+		}
+		bar.Swing = math.Abs(bar.HighOutput - bar.LowOutput)
+		tornado.Bars[i] = bar
+	}
+
+	sort.SliceStable(tornado.Bars, func(i, j int) bool { return tornado.Bars[i].Swing > tornado.Bars[j].Swing })
+	return tornado, nil
+}
+
+// Sweep is a one-way data table: the model output at each value of one input.
+type Sweep struct {
+	Input   string    `json:"input"`
+	Values  []float64 `json:"values"`
+	Outputs []float64 `json:"outputs"`
+}
+
+// Sweep evaluates the model at each of the given values of one input,
+// holding the others at their base values.
+func (a Analysis) Sweep(input string, values []float64) (Sweep, error) {
+	if err := a.check(input); err != nil {
+		return Sweep{}, err
+	}
+
+	sweep := Sweep{Input: input, Values: values, Outputs: make([]float64, len(values))}
+	for i, value := range values {
+		output, err := a.evaluate(Inputs{input: value})
+		if err != nil {
+			return Sweep{}, err
+		}
+		sweep.Outputs[i] = output
+	}
+	return sweep, nil
+}
+
+// Table is a two-way data table: Outputs[i][j] is the model output with the
+// row input at RowValues[i] and the column input at ColumnValues[j].
+type Table struct {
+	Row          string      `json:"row"`
+	Column       string      `json:"column"`
+	RowValues    []float64   `json:"row_values"`
+	ColumnValues []float64   `json:"column_values"`
+	Outputs      [][]float64 `json:"outputs"`
+}
+
+// Table evaluates the model at every combination of the given values of two
+// inputs, holding the others at their base values.
+func (a Analysis) Table(row, column string, rowValues, columnValues []float64) (Table, error) {
+	if err := a.check(row, column); err != nil {
+		return Table{}, err
+	}
+	if row == column {
+		return Table{}, fmt.Errorf("%w: %q is both the row and the column", ErrInvalidVariation, row)
+	}
+
+	table := Table{Row: row, Column: column, RowValues: rowValues, ColumnValues: columnValues, Outputs: make([][]float64, len(rowValues))}
+	outputs := make([]float64, len(rowValues)*len(columnValues))
+	for i, rowValue := range rowValues {
+		table.Outputs[i] = outputs[i*len(columnValues) : (i+1)*len(columnValues)]
+		for j, columnValue := range columnValues {
+			output, err := a.evaluate(Inputs{row: rowValue, column: columnValue})
+			if err != nil {
+				return Table{}, err
+			}
+			table.Outputs[i][j] = output
+		}
+	}
+	return table, nil
+}
+
+// Scenario is a named set of input values, such as a best or worst case.
+// Inputs it leaves out keep their base values.
+type Scenario struct {
+	Name   string
+	Inputs Inputs
+}
+
+// ScenarioResult is the model output under one scenario.
+type ScenarioResult struct {
+	Name string `json:"name"`
+
+	// Inputs holds every input, base values included.
+	Inputs Inputs  `json:"inputs"`
+	Output float64 `json:"output"`
+
+	// Change is the output less the base output.
+	Change float64 `json:"change"`
+}
+
+// Scenarios holds the results of a set of scenarios.
+type Scenarios struct {
+	// Base is the output at the base inputs.
+	Base    float64          `json:"base"`
+	Results []ScenarioResult `json:"results"`
+}
+
+// Scenarios evaluates the model under each scenario, in order.
+func (a Analysis) Scenarios(scenarios []Scenario) (Scenarios, error) {
+	var inputs []string
+	for _, s := range scenarios {
+		for input := range s.Inputs {
+			inputs = append(inputs, input)
+		}
+	}
+	if err := a.check(inputs...); err != nil {
+		return Scenarios{}, err
+	}
+
+	base, err := a.evaluate(nil)
+	if err != nil {
+		return Scenarios{}, err
+	}
+	results := Scenarios{Base: base, Results: make([]ScenarioResult, len(scenarios))}
+	for i, s := range scenarios {
+		inputs := a.Base.with(s.Inputs)
+		output, err := a.Model(inputs)
+		if err != nil {
+			return Scenarios{}, fmt.Errorf("sensitivity: scenario %q: %w", s.Name, err)
+		}
+		results.Results[i] = ScenarioResult{Name: s.Name, Inputs: inputs, Output: output, Change: output - base}
+	}
+	return results, nil
+}
@@ -0,0 +1,149 @@
+package sensitivity
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/lazarospsa/gofin"
+)
+
+// npv is the NPV of an investment returning the same revenue for three years.
+func npv(in Inputs) (float64, error) {
+	flows := []float64{-in["investment"], in["revenue"], in["revenue"], in["revenue"]}
+	return gofin.NetPresentValueE(in["rate"], len(flows), flows)
+}
+
+var base = Inputs{"rate": 0.08, "investment": 1000, "revenue": 450}
+
+func TestTornado(t *testing.T) {
+	a := Analysis{Model: npv, Base: base}
+	tornado, err := a.Tornado([]Variation{
+		{Input: "rate", Low: -0.02, High: 0.02},
+		{Input: "revenue", Low: -0.1, High: 0.1, Shift: Relative},
+		{Input: "investment", Low: 900, High: 1200, Shift: Replace},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := npv(base)
+	if tornado.Base != expected {
+		t.Errorf("Test failed, expected: '%f', got: '%f'", expected, tornado.Base)
+	}
+	order := []string{"investment", "revenue", "rate"}
+	for i, bar := range tornado.Bars {
+		if bar.Input != order[i] {
+			t.Fatalf("Test failed, expected the order %v, got: %+v", order, tornado.Bars)
+		}
+	}
+
+	revenue := tornado.Bars[1]
+	low, _ := npv(Inputs{"rate": 0.08, "investment": 1000, "revenue": 405})
+	increase := 0.1
+	high, _ := npv(Inputs{"rate": 0.08, "investment": 1000, "revenue": 450 * (1 + increase)})
+	if revenue.LowValue != 405 || revenue.HighValue != 450*(1+increase) || revenue.LowOutput != low || revenue.HighOutput != high || revenue.Swing != high-low {
+		t.Errorf("Test failed, expected: %f to %f, got: %+v", low, high, revenue)
+	}
+	if rate := tornado.Bars[2]; rate.LowValue != 0.06 || rate.LowOutput < rate.HighOutput {
+		t.Errorf("Test failed, got: %+v", rate)
+	}
+	if base["revenue"] != 450 {
+		t.Errorf("Test failed, the base inputs were modified: %v", base)
+	}
+}
+
+func TestTables(t *testing.T) {
+	a := Analysis{Model: func(in Inputs) (float64, error) { return in["x"]*10 + in["y"], nil }, Base: Inputs{"x": 1, "y": 2, "z": 3}}
+
+	steps, err := Variation{Input: "x", Low: -0.5, High: 0.5, Shift: Relative}.Steps(a.Base["x"], 3)
+	if err != nil || len(steps) != 3 || steps[0] != 0.5 || steps[1] != 1 || steps[2] != 1.5 {
+		t.Fatalf("Test failed, expected: [0.5 1 1.5], got: %v (%v)", steps, err)
+	}
+	sweep, err := a.Sweep("x", steps)
+	if err != nil || sweep.Outputs[0] != 7 || sweep.Outputs[2] != 17 {
+		t.Errorf("Test failed, expected: [7 12 17], got: %v (%v)", sweep.Outputs, err)
+	}
+
+	table, err := a.Table("x", "y", []float64{1, 2}, []float64{0, 5, 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]float64{{10, 15, 20}, {20, 25, 30}}
+	for i := range expected {
+		for j := range expected[i] {
+			if table.Outputs[i][j] != expected[i][j] {
+				t.Errorf("Test failed, expected: %v, got: %v", expected, table.Outputs)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := table.WriteCSV(&buf); err != nil || buf.String() != "x\\y,0,5,10\n1,10,15,20\n2,20,25,30\n" {
+		t.Errorf("Test failed, got: %q (%v)", buf.String(), err)
+	}
+	buf.Reset()
+	if err := sweep.WriteCSV(&buf); err != nil || buf.String() != "x,output\n0.5,7\n1,12\n1.5,17\n" {
+		t.Errorf("Test failed, got: %q (%v)", buf.String(), err)
+	}
+}
+
+func TestScenarios(t *testing.T) {
+	a := Analysis{Model: npv, Base: base}
+	scenarios, err := a.Scenarios([]Scenario{
+		{Name: "base"},
+		{Name: "best", Inputs: Inputs{"rate": 0.06, "revenue": 500}},
+		{Name: "worst", Inputs: Inputs{"rate": 0.1, "revenue": 400}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	worst, _ := npv(Inputs{"rate": 0.1, "investment": 1000, "revenue": 400})
+	if result := scenarios.Results[2]; result.Output != worst || result.Change != worst-scenarios.Base || result.Inputs["investment"] != 1000 {
+		t.Errorf("Test failed, expected: '%f', got: %+v", worst, result)
+	}
+	if scenarios.Results[0].Change != 0 || scenarios.Results[1].Change <= 0 {
+		t.Errorf("Test failed, got: %+v", scenarios.Results)
+	}
+
+	var buf bytes.Buffer
+	if err := (Scenarios{Results: []ScenarioResult{{Name: "a", Inputs: Inputs{"y": 2, "x": 1}, Output: 3, Change: -1}}}).WriteCSV(&buf); err != nil || buf.String() != "scenario,x,y,output,change\na,1,2,3,-1\n" {
+		t.Errorf("Test failed, got: %q (%v)", buf.String(), err)
+	}
+
+	encoded, _ := json.Marshal(scenarios)
+	var decoded Scenarios
+	if err := json.Unmarshal(encoded, &decoded); err != nil || decoded.Results[2].Output != worst || decoded.Results[2].Inputs["rate"] != 0.1 {
+		t.Errorf("Test failed, got: %s (%v)", encoded, err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	failing := errors.New("model failed")
+	a := Analysis{Model: npv, Base: base}
+
+	tests := []struct {
+		err      error
+		expected error
+	}{
+		{func() error { _, err := (Analysis{Base: base}).Sweep("rate", []float64{0.1}); return err }(), ErrNoModel},
+		{func() error { _, err := a.Tornado([]Variation{{Input: "growth"}}); return err }(), ErrUnknownInput},
+		{func() error { _, err := a.Tornado([]Variation{{Input: "rate", Shift: Shift(7)}}); return err }(), ErrInvalidVariation},
+		{func() error { _, err := a.Table("rate", "rate", nil, nil); return err }(), ErrInvalidVariation},
+		{func() error { _, err := a.Scenarios([]Scenario{{Name: "x", Inputs: Inputs{"tax": 1}}}); return err }(), ErrUnknownInput},
+		{func() error { _, err := (Variation{}).Steps(1, 1); return err }(), ErrInvalidVariation},
+		{func() error { _, err := a.Sweep("rate", []float64{-1}); return err }(), gofin.ErrInvalidRate},
+		{func() error {
+			_, err := (Analysis{Model: func(Inputs) (float64, error) { return 0, failing }, Base: base}).Scenarios(nil)
+			return err
+		}(), failing},
+	}
+
+	for i, test := range tests {
+		if !errors.Is(test.err, test.expected) {
+			t.Errorf("Test %d failed, expected: '%v', got: '%v'", i, test.expected, test.err)
+		}
+	}
+}
@@ -0,0 +1,568 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lazarospsa/gofin"
+	"github.com/lazarospsa/gofin/daycount"
+	"github.com/lazarospsa/gofin/depreciation"
+)
+
+// endpoints returns every gofin endpoint.
+func endpoints() []Endpoint {
+	return []Endpoint{
+		endpoint("/v1/fv", "Future value of an investment with periodic payments, like the spreadsheet FV function.", fv),
+		endpoint("/v1/pv", "Present value of an investment with periodic payments, like the spreadsheet PV function.", pv),
+		endpoint("/v1/pmt", "Payment per period of a loan or investment, like the spreadsheet PMT function.", pmt),
+		endpoint("/v1/nper", "Number of periods of a loan or investment, like the spreadsheet NPER function.", nper),
+		endpoint("/v1/rate", "Interest rate per period of a loan or investment, like the spreadsheet RATE function.", rate),
+		endpoint("/v1/npv", "Net present value of cash flows one period apart, the first one undiscounted.", npv),
+		endpoint("/v1/xnpv", "Net present value of dated cash flows, discounted to the first date on an Actual/365 basis.", xnpv),
+		endpoint("/v1/irr", "Internal rate of return of cash flows one period apart.", irr),
+		endpoint("/v1/xirr", "Internal rate of return of dated cash flows.", xirr),
+		endpoint("/v1/mirr", "Modified internal rate of return.", mirr),
+		endpoint("/v1/payback", "Payback period of an investment, discounted if a rate is given.", payback),
+		endpoint("/v1/amortize", "Amortization schedule of a loan.", amortize),
+		endpoint("/v1/depreciate", "Depreciation schedule of an asset.", depreciate),
+		endpoint("/v1/convert-rate", "Conversion of an annual rate between compounding frequencies.", convertRate),
+		endpoint("/v1/option", "Black-Scholes-Merton price and Greeks of a European option.", option),
+	}
+}
+
+// maxPeriods caps the length of the schedules a request may ask for, so that
+// one request cannot exhaust the server's memory.
+const maxPeriods = 1200
+
+// errorCodes maps the gofin errors to the codes of Error.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{gofin.ErrZeroRate, "zero_rate"},
+	{gofin.ErrInvalidRate, "invalid_rate"},
+	{gofin.ErrRateBelowGrowth, "rate_below_growth"},
+	{gofin.ErrInvalidPeriods, "invalid_periods"},
+	{gofin.ErrInvalidTiming, "invalid_timing"},
+	{gofin.ErrZeroValue, "zero_value"},
+	{gofin.ErrInvalidReturn, "invalid_return"},
+	{gofin.ErrEmptyInput, "empty_input"},
+	{gofin.ErrLengthMismatch, "length_mismatch"},
+	{gofin.ErrNoPayback, "no_payback"},
+	{gofin.ErrInvalidLoan, "invalid_loan"},
+	{gofin.ErrInvalidOption, "invalid_option"},
+	{gofin.ErrInvalidCompounding, "invalid_compounding"},
+	{gofin.ErrInvalidDate, "invalid_date"},
+	{gofin.ErrNoSignChange, "no_sign_change"},
+	{gofin.ErrNoSolution, "no_solution"},
+	{gofin.ErrNoConvergence, "no_convergence"},
+	{depreciation.ErrInvalidAsset, "invalid_asset"},
+	{depreciation.ErrInvalidMACRS, "invalid_macrs"},
+}
+
+// Value is the response of the endpoints that return a single number.
+type Value struct {
+	Value float64 `json:"value" required:"true"`
+}
+
+// FVRequest is the request of /v1/fv.
+type FVRequest struct {
+	Rate float64 `json:"rate" required:"true"`
+	Nper float64 `json:"nper" required:"true"`
+	Pmt  float64 `json:"pmt"`
+	PV   float64 `json:"pv"`
+
+	// Due puts the payments at the beginning of each period.
+	Due bool `json:"due"`
+}
+
+func fv(req FVRequest) (Value, error) {
+	v, err := gofin.FV(req.Rate, req.Nper, req.Pmt, req.PV, timing(req.Due))
+	return Value{v}, err
+}
+
+// PVRequest is the request of /v1/pv.
+type PVRequest struct {
+	Rate float64 `json:"rate" required:"true"`
+	Nper float64 `json:"nper" required:"true"`
+	Pmt  float64 `json:"pmt"`
+	FV   float64 `json:"fv"`
+	Due  bool    `json:"due"`
+}
+
+func pv(req PVRequest) (Value, error) {
+	v, err := gofin.PV(req.Rate, req.Nper, req.Pmt, req.FV, timing(req.Due))
+	return Value{v}, err
+}
+
+// PMTRequest is the request of /v1/pmt.
+type PMTRequest struct {
+	Rate float64 `json:"rate" required:"true"`
+	Nper float64 `json:"nper" required:"true"`
+	PV   float64 `json:"pv" required:"true"`
+	FV   float64 `json:"fv"`
+	Due  bool    `json:"due"`
+}
+
+func pmt(req PMTRequest) (Value, error) {
+	v, err := gofin.PMT(req.Rate, req.Nper, req.PV, req.FV, timing(req.Due))
+	return Value{v}, err
+}
+
+// NPERRequest is the request of /v1/nper.
+type NPERRequest struct {
+	Rate float64 `json:"rate" required:"true"`
+	Pmt  float64 `json:"pmt" required:"true"`
+	PV   float64 `json:"pv" required:"true"`
+	FV   float64 `json:"fv"`
+	Due  bool    `json:"due"`
+}
+
+func nper(req NPERRequest) (Value, error) {
+	v, err := gofin.NPER(req.Rate, req.Pmt, req.PV, req.FV, timing(req.Due))
+	return Value{v}, err
+}
+
+// RATERequest is the request of /v1/rate.
+type RATERequest struct {
+	Nper float64 `json:"nper" required:"true"`
+	Pmt  float64 `json:"pmt" required:"true"`
+	PV   float64 `json:"pv" required:"true"`
+	FV   float64 `json:"fv"`
+	Due  bool    `json:"due"`
+
+	// Guess is the starting rate of the search. Defaults to 0.1.
+	Guess *float64 `json:"guess"`
+}
+
+func rate(req RATERequest) (Value, error) {
+	v, err := gofin.RATE(req.Nper, req.Pmt, req.PV, req.FV, timing(req.Due), guess(req.Guess))
+	return Value{v}, err
+}
+
+// NPVRequest is the request of /v1/npv.
+type NPVRequest struct {
+	Rate      float64   `json:"rate" required:"true"`
+	CashFlows []float64 `json:"cash_flows" required:"true"`
+}
+
+func npv(req NPVRequest) (Value, error) {
+	v, err := gofin.NetPresentValueE(req.Rate, len(req.CashFlows), req.CashFlows)
+	return Value{v}, err
+}
+
+// DatedCashFlow is a cash flow on a date written as YYYY-MM-DD.
+type DatedCashFlow struct {
+	Date   string  `json:"date" required:"true" format:"date"`
+	Amount float64 `json:"amount" required:"true"`
+}
+
+// XNPVRequest is the request of /v1/xnpv.
+type XNPVRequest struct {
+	Rate      float64         `json:"rate" required:"true"`
+	CashFlows []DatedCashFlow `json:"cash_flows" required:"true"`
+}
+
+func xnpv(req XNPVRequest) (Value, error) {
+	flows, err := datedCashFlows(req.CashFlows)
+	if err != nil {
+		return Value{}, err
+	}
+	v, err := gofin.XNPV(req.Rate, flows)
+	return Value{v}, err
+}
+
+// IRRRequest is the request of /v1/irr.
+type IRRRequest struct {
+	CashFlows []float64 `json:"cash_flows" required:"true"`
+
+	// Guess is the starting rate of the search. Defaults to 0.1.
+	Guess *float64 `json:"guess"`
+}
+
+// IRRResponse is the response of /v1/irr and /v1/xirr.
+type IRRResponse struct {
+	// Rate is the rate closest to the guess.
+	Rate float64 `json:"rate" required:"true"`
+
+	// Roots holds every rate found, for cash flows that change sign more than once.
+	Roots []float64 `json:"roots" required:"true"`
+}
+
+func irr(req IRRRequest) (IRRResponse, error) {
+	result, err := gofin.IRRSolver{Guess: guess(req.Guess), AllRoots: true}.Solve(req.CashFlows)
+	return IRRResponse{result.Rate, result.Roots}, err
+}
+
+// XIRRRequest is the request of /v1/xirr.
+type XIRRRequest struct {
+	CashFlows []DatedCashFlow `json:"cash_flows" required:"true"`
+
+	// Guess is the starting rate of the search. Defaults to 0.1.
+	Guess *float64 `json:"guess"`
+
+	// Basis is the day count basis. Defaults to act365.
+	Basis string `json:"basis" enum:"act365,act360,actact,30360"`
+}
+
+func (req XIRRRequest) validate() error {
+	_, err := dayCount(req.Basis)
+	return err
+}
+
+func xirr(req XIRRRequest) (IRRResponse, error) {
+	flows, err := datedCashFlows(req.CashFlows)
+	if err != nil {
+		return IRRResponse{}, err
+	}
+	basis, _ := dayCount(req.Basis)
+	result, err := gofin.XIRR(flows, gofin.XIRROptions{Solver: gofin.IRRSolver{Guess: guess(req.Guess), AllRoots: true}, DayCount: basis})
+	return IRRResponse{result.Rate, result.Roots}, err
+}
+
+// MIRRRequest is the request of /v1/mirr.
+type MIRRRequest struct {
+	InitialInvestment float64   `json:"initial_investment" required:"true"`
+	CashOutflows      []float64 `json:"cash_outflows" required:"true"`
+	CashInflows       []float64 `json:"cash_inflows" required:"true"`
+	FinanceRate       float64   `json:"finance_rate" required:"true"`
+}
+
+func mirr(req MIRRRequest) (Value, error) {
+	v, err := gofin.ModifiedInternalRateOfReturnE(req.InitialInvestment, req.CashOutflows, req.CashInflows, req.FinanceRate)
+	return Value{v}, err
+}
+
+// PaybackRequest is the request of /v1/payback.
+type PaybackRequest struct {
+	// CashFlows holds the investment, as a negative amount, followed by the inflows.
+	CashFlows []float64 `json:"cash_flows" required:"true"`
+
+	// Rate discounts the inflows for the discounted payback period.
+	Rate float64 `json:"rate"`
+}
+
+// PaybackResponse is the response of /v1/payback.
+type PaybackResponse struct {
+	Period int `json:"period" required:"true"`
+}
+
+func (req PaybackRequest) validate() error {
+	if len(req.CashFlows) < 2 {
+		return invalidField("cash_flows", "cash_flows must hold the investment and at least one inflow")
+	}
+	return nil
+}
+
+func payback(req PaybackRequest) (PaybackResponse, error) {
+	period, err := gofin.DiscountedPaybackPeriodE(-req.CashFlows[0], req.CashFlows[1:], req.Rate)
+	return PaybackResponse{period}, err
+}
+
+// AmortizeRequest is the request of /v1/amortize.
+type AmortizeRequest struct {
+	Principal float64 `json:"principal" required:"true"`
+
+	// Rate is the interest rate per period.
+	Rate    float64 `json:"rate" required:"true"`
+	Periods int     `json:"periods" required:"true"`
+
+	// Method defaults to fixed_payment.
+	Method              string  `json:"method" enum:"fixed_payment,fixed_principal"`
+	InterestOnlyPeriods int     `json:"interest_only_periods"`
+	Balloon             float64 `json:"balloon"`
+
+	ExtraPayments []ExtraPayment `json:"extra_payments"`
+}
+
+// ExtraPayment is an extra principal prepayment in a period, counting from 1.
+type ExtraPayment struct {
+	Period int     `json:"period" required:"true"`
+	Amount float64 `json:"amount" required:"true"`
+}
+
+// AmortizationRow is one period of an amortization schedule.
+type AmortizationRow struct {
+	Period             int     `json:"period" required:"true"`
+	Payment            float64 `json:"payment" required:"true"`
+	Interest           float64 `json:"interest" required:"true"`
+	Principal          float64 `json:"principal" required:"true"`
+	ExtraPrincipal     float64 `json:"extra_principal" required:"true"`
+	Balance            float64 `json:"balance" required:"true"`
+	CumulativeInterest float64 `json:"cumulative_interest" required:"true"`
+}
+
+// AmortizeResponse is the response of /v1/amortize.
+type AmortizeResponse struct {
+	Rows []AmortizationRow `json:"rows" required:"true"`
+}
+
+func (req AmortizeRequest) validate() error {
+	if req.Periods > maxPeriods {
+		return invalidField("periods", "periods must be at most %d", maxPeriods)
+	}
+	if req.InterestOnlyPeriods > maxPeriods {
+		return invalidField("interest_only_periods", "interest_only_periods must be at most %d", maxPeriods)
+	}
+	switch req.Method {
+	case "", "fixed_payment", "fixed_principal":
+		return nil
+	}
+	return invalidField("method", "unknown method %q", req.Method)
+}
+
+func amortize(req AmortizeRequest) (AmortizeResponse, error) {
+	spec := gofin.LoanSpec{
+		Principal:           req.Principal,
+		Rate:                req.Rate,
+		Periods:             req.Periods,
+		InterestOnlyPeriods: req.InterestOnlyPeriods,
+		Balloon:             req.Balloon,
+	}
+	if req.Method == "fixed_principal" {
+		spec.Method = gofin.FixedPrincipal
+	}
+	if len(req.ExtraPayments) > 0 {
+		spec.ExtraPayments = make(map[int]float64, len(req.ExtraPayments))
+		for _, extra := range req.ExtraPayments {
+			spec.ExtraPayments[extra.Period] += extra.Amount
+		}
+	}
+
+	rows, err := gofin.Amortize(spec)
+	if err != nil {
+		return AmortizeResponse{}, err
+	}
+	response := AmortizeResponse{Rows: make([]AmortizationRow, len(rows))}
+	for i, row := range rows {
+		response.Rows[i] = AmortizationRow(row)
+	}
+	return response, nil
+}
+
+// DepreciateRequest is the request of /v1/depreciate.
+type DepreciateRequest struct {
+	Method  string  `json:"method" required:"true" enum:"straight_line,declining_balance,sum_of_years_digits,macrs"`
+	Cost    float64 `json:"cost" required:"true"`
+	Salvage float64 `json:"salvage"`
+
+	// Life is the useful life in years. MACRS uses Class instead.
+	Life            int `json:"life"`
+	FirstYearMonths int `json:"first_year_months"`
+
+	// Factor is the declining balance factor. Defaults to 2.
+	Factor float64 `json:"factor"`
+
+	// Class is the MACRS property class, and Quarter the quarter placed in
+	// service under the mid-quarter convention, or 0 for half-year.
+	Class   int `json:"class"`
+	Quarter int `json:"quarter"`
+}
+
+// DepreciationRow is one period of a depreciation schedule.
+type DepreciationRow struct {
+	Period       int     `json:"period" required:"true"`
+	Depreciation float64 `json:"depreciation" required:"true"`
+	Accumulated  float64 `json:"accumulated" required:"true"`
+	BookValue    float64 `json:"book_value" required:"true"`
+}
+
+// DepreciateResponse is the response of /v1/depreciate.
+type DepreciateResponse struct {
+	Rows []DepreciationRow `json:"rows" required:"true"`
+}
+
+func (req DepreciateRequest) validate() error {
+	if req.Life > maxPeriods {
+		return invalidField("life", "life must be at most %d", maxPeriods)
+	}
+	switch req.Method {
+	case "straight_line", "declining_balance", "sum_of_years_digits", "macrs":
+		return nil
+	}
+	return invalidField("method", "unknown method %q", req.Method)
+}
+
+func depreciate(req DepreciateRequest) (DepreciateResponse, error) {
+	asset := depreciation.Asset{Cost: req.Cost, Salvage: req.Salvage, Life: req.Life, FirstYearMonths: req.FirstYearMonths}
+
+	var rows []depreciation.Row
+	var err error
+	switch req.Method {
+	case "straight_line":
+		rows, err = depreciation.StraightLine(asset)
+	case "declining_balance":
+		factor := req.Factor
+		if factor == 0 {
+			factor = 2
+		}
+		rows, err = depreciation.DecliningBalance(asset, factor)
+	case "sum_of_years_digits":
+		rows, err = depreciation.SumOfYearsDigits(asset)
+	case "macrs":
+		property := depreciation.MACRS{Class: req.Class}
+		if req.Quarter != 0 {
+			property.Convention, property.Quarter = depreciation.MidQuarter, req.Quarter
+		}
+		rows, err = property.Schedule(req.Cost)
+	}
+	if err != nil {
+		return DepreciateResponse{}, err
+	}
+
+	response := DepreciateResponse{Rows: make([]DepreciationRow, len(rows))}
+	for i, row := range rows {
+		response.Rows[i] = DepreciationRow(row)
+	}
+	return response, nil
+}
+
+// ConvertRateRequest is the request of /v1/convert-rate.
+type ConvertRateRequest struct {
+	// Rate is the annual nominal rate compounded at frequency From.
+	Rate float64 `json:"rate" required:"true"`
+	From string  `json:"from" required:"true" enum:"annual,semiannual,quarterly,monthly,weekly,daily,continuous"`
+	To   string  `json:"to" required:"true" enum:"annual,semiannual,quarterly,monthly,weekly,daily,continuous"`
+}
+
+// ConvertRateResponse is the response of /v1/convert-rate.
+type ConvertRateResponse struct {
+	// Rate is the annual nominal rate compounded at frequency To.
+	Rate float64 `json:"rate" required:"true"`
+
+	// Effective is the effective annual rate of both.
+	Effective float64 `json:"effective" required:"true"`
+}
+
+func (req ConvertRateRequest) validate() error {
+	if _, ok := compoundings[req.From]; !ok {
+		return invalidField("from", "unknown compounding frequency %q", req.From)
+	}
+	if _, ok := compoundings[req.To]; !ok {
+		return invalidField("to", "unknown compounding frequency %q", req.To)
+	}
+	return nil
+}
+
+func convertRate(req ConvertRateRequest) (ConvertRateResponse, error) {
+	r := gofin.Rate{Nominal: req.Rate, Compounding: compoundings[req.From]}
+	converted, err := r.Convert(compoundings[req.To])
+	if err != nil {
+		return ConvertRateResponse{}, err
+	}
+	effective, err := r.Effective()
+	return ConvertRateResponse{Rate: converted.Nominal, Effective: effective}, err
+}
+
+// OptionRequest is the request of /v1/option.
+type OptionRequest struct {
+	Type   string  `json:"type" required:"true" enum:"call,put"`
+	Spot   float64 `json:"spot" required:"true"`
+	Strike float64 `json:"strike" required:"true"`
+
+	// Expiry is the time to expiry in years.
+	Expiry float64 `json:"expiry" required:"true"`
+
+	// Rate and DividendYield are continuously compounded.
+	Rate          float64 `json:"rate" required:"true"`
+	DividendYield float64 `json:"dividend_yield"`
+	Volatility    float64 `json:"volatility" required:"true"`
+}
+
+// OptionResponse is the response of /v1/option.
+type OptionResponse struct {
+	Price float64 `json:"price" required:"true"`
+	Delta float64 `json:"delta" required:"true"`
+	Gamma float64 `json:"gamma" required:"true"`
+	Vega  float64 `json:"vega" required:"true"`
+	Theta float64 `json:"theta" required:"true"`
+	Rho   float64 `json:"rho" required:"true"`
+}
+
+func (req OptionRequest) validate() error {
+	if req.Type != "call" && req.Type != "put" {
+		return invalidField("type", "unknown option type %q", req.Type)
+	}
+	return nil
+}
+
+func option(req OptionRequest) (OptionResponse, error) {
+	o := gofin.EuropeanOption{
+		Type:          gofin.Call,
+		Spot:          req.Spot,
+		Strike:        req.Strike,
+		Expiry:        req.Expiry,
+		Rate:          req.Rate,
+		DividendYield: req.DividendYield,
+		Volatility:    req.Volatility,
+	}
+	if req.Type == "put" {
+		o.Type = gofin.Put
+	}
+
+	price, err := o.Price()
+	if err != nil {
+		return OptionResponse{}, err
+	}
+	g, err := o.Greeks()
+	if err != nil {
+		return OptionResponse{}, err
+	}
+	return OptionResponse{Price: price, Delta: g.Delta, Gamma: g.Gamma, Vega: g.Vega, Theta: g.Theta, Rho: g.Rho}, nil
+}
+
+// timing returns the payment timing selected by due.
+func timing(due bool) gofin.PaymentTiming {
+	if due {
+		return gofin.BeginningOfPeriod
+	}
+	return gofin.EndOfPeriod
+}
+
+// guess returns the starting rate of a search, 0.1 unless given.
+func guess(g *float64) float64 {
+	if g == nil {
+		return 0.1
+	}
+	return *g
+}
+
+// datedCashFlows parses the dates of flows.
+func datedCashFlows(flows []DatedCashFlow) ([]gofin.DatedCashFlow, error) {
+	parsed := make([]gofin.DatedCashFlow, len(flows))
+	for i, flow := range flows {
+		date, err := time.Parse("2006-01-02", flow.Date)
+		if err != nil {
+			field := fmt.Sprintf("cash_flows[%d].date", i)
+			return nil, invalidField(field, "%s must be a date written as YYYY-MM-DD", field)
+		}
+		parsed[i] = gofin.DatedCashFlow{Date: date, Amount: flow.Amount}
+	}
+	return parsed, nil
+}
+
+// dayCount returns the day count basis with the given name.
+func dayCount(basis string) (gofin.DayCounter, error) {
+	switch basis {
+	case "", "act365":
+		return gofin.Actual365Fixed{}, nil
+	case "act360":
+		return gofin.Actual360{}, nil
+	case "actact":
+		return daycount.ActualActualISDA{}, nil
+	case "30360":
+		return gofin.Thirty360{}, nil
+	}
+	return nil, invalidField("basis", "unknown day count basis %q", basis)
+}
+
+// compoundings maps the compounding frequency names to frequencies.
+var compoundings = map[string]gofin.Compounding{
+	"annual":     gofin.CompoundAnnually,
+	"semiannual": gofin.CompoundSemiannually,
+	"quarterly":  gofin.CompoundQuarterly,
+	"monthly":    gofin.CompoundMonthly,
+	"weekly":     gofin.CompoundWeekly,
+	"daily":      gofin.CompoundDaily,
+	"continuous": gofin.CompoundContinuously,
+}
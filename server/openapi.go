@@ -0,0 +1,142 @@
+package server
+
+import (
+	"reflect"
+	"strings"
+)
+
+// OpenAPI returns the OpenAPI 3.0 document of the server's endpoints,
+// generated from the request and response types of the registry. Fields
+// tagged required:"true" are required, enum:"a,b" lists the allowed values
+// of a string and format:"date" marks a YYYY-MM-DD date.
+func (s *Server) OpenAPI() map[string]interface{} {
+	schemas := make(map[string]interface{})
+	schemas["Error"] = object(map[string]interface{}{
+		"error": schemaRef(reflect.TypeOf(Error{}), schemas),
+	}, []string{"error"})
+
+	errorResponse := func(description string) map[string]interface{} {
+		return map[string]interface{}{
+			"description": description,
+			"content":     jsonContent(ref("Error")),
+		}
+	}
+
+	paths := make(map[string]interface{})
+	for _, e := range s.endpoints {
+		operationID := strings.ReplaceAll(strings.TrimPrefix(e.Path, "/v1/"), "-", "_")
+		paths[e.Path] = map[string]interface{}{
+			"post": map[string]interface{}{
+				"operationId": operationID,
+				"summary":     e.Summary,
+				"requestBody": map[string]interface{}{
+					"required": true,
+					"content":  jsonContent(schemaRef(e.Request, schemas)),
+				},
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{
+						"description": "The result of the calculation.",
+						"content":     jsonContent(schemaRef(e.Response, schemas)),
+					},
+					"400": errorResponse("The request is malformed or has an invalid field."),
+					"422": errorResponse("The calculation has no result for the given inputs."),
+				},
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "gofin",
+			"version": "1",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+		},
+	}
+}
+
+// schemaRef returns the schema of t, adding named struct types to schemas
+// and referring to them.
+func schemaRef(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": schemaRef(t.Elem(), schemas)}
+	case reflect.Ptr:
+		return schemaRef(t.Elem(), schemas)
+	case reflect.Struct:
+		name := t.Name()
+		if name == "Error" {
+			name = "ErrorDetail"
+		}
+		if _, ok := schemas[name]; !ok {
+			// Reserve the name first so recursive types terminate.
+			schemas[name] = nil
+			schemas[name] = structSchema(t, schemas)
+		}
+		return ref(name)
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// structSchema returns the object schema of the struct type t.
+func structSchema(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := jsonName(f)
+		if name == "" {
+			continue
+		}
+
+		schema := schemaRef(f.Type, schemas)
+		if enum := f.Tag.Get("enum"); enum != "" {
+			schema["enum"] = strings.Split(enum, ",")
+		}
+		if format := f.Tag.Get("format"); format != "" {
+			schema["format"] = format
+		}
+		properties[name] = schema
+		if f.Tag.Get("required") == "true" {
+			required = append(required, name)
+		}
+	}
+	return object(properties, required)
+}
+
+// object returns a closed object schema.
+func object(properties map[string]interface{}, required []string) map[string]interface{} {
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// ref returns a reference to a component schema.
+func ref(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+// jsonContent returns the content map of a JSON body with the given schema.
+func jsonContent(schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{"schema": schema},
+	}
+}
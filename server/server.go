@@ -0,0 +1,289 @@
+// Package server exposes the gofin calculations as JSON endpoints over HTTP.
+//
+// Every calculation is an Endpoint in the server's registry, which drives
+// the routing, the validation of requests and the OpenAPI document served at
+// /openapi.json. Calculations are called with POST and a JSON object body.
+// Failed requests get a JSON Error with a stable code: 400 for malformed or
+// invalid requests, and 422 when the inputs are well formed but the
+// calculation has no answer, for example an IRR of cash flows that never
+// change sign.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// maxBodyBytes caps the size of a request body.
+const maxBodyBytes = 1 << 20
+
+// Endpoint is a calculation exposed by the server.
+type Endpoint struct {
+	// Path is the URL path of the endpoint, for example "/v1/fv".
+	Path string
+
+	// Summary describes the calculation in the OpenAPI document.
+	Summary string
+
+	// Request and Response are the types of the JSON bodies.
+	Request  reflect.Type
+	Response reflect.Type
+
+	handle func(body []byte) (interface{}, error)
+}
+
+// endpoint returns the Endpoint at path that decodes and validates a Req,
+// and answers with the Resp returned by fn.
+func endpoint[Req, Resp any](path, summary string, fn func(Req) (Resp, error)) Endpoint {
+	return Endpoint{
+		Path:     path,
+		Summary:  summary,
+		Request:  reflect.TypeOf((*Req)(nil)).Elem(),
+		Response: reflect.TypeOf((*Resp)(nil)).Elem(),
+		handle: func(body []byte) (interface{}, error) {
+			var req Req
+			if err := decode(body, &req); err != nil {
+				return nil, err
+			}
+			if v, ok := interface{}(req).(interface{ validate() error }); ok {
+				if err := v.validate(); err != nil {
+					return nil, err
+				}
+			}
+			return fn(req)
+		},
+	}
+}
+
+// Server serves the registered endpoints. It implements http.Handler.
+type Server struct {
+	endpoints []Endpoint
+	byPath    map[string]Endpoint
+	spec      []byte
+}
+
+// New returns a Server with every gofin endpoint registered.
+func New() *Server {
+	s := &Server{byPath: make(map[string]Endpoint)}
+	for _, e := range endpoints() {
+		s.endpoints = append(s.endpoints, e)
+		s.byPath[e.Path] = e
+	}
+	sort.Slice(s.endpoints, func(i, j int) bool { return s.endpoints[i].Path < s.endpoints[j].Path })
+
+	spec, err := json.MarshalIndent(s.OpenAPI(), "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	s.spec = spec
+	return s
+}
+
+// Endpoints returns the registered endpoints, sorted by path.
+func (s *Server) Endpoints() []Endpoint {
+	return append([]Endpoint(nil), s.endpoints...)
+}
+
+// ServeHTTP serves a calculation, or the OpenAPI document at /openapi.json.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/openapi.json" {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeError(w, &Error{Code: "method_not_allowed", Message: "use GET", status: http.StatusMethodNotAllowed})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(s.spec)
+		return
+	}
+
+	e, ok := s.byPath[r.URL.Path]
+	if !ok {
+		writeError(w, &Error{Code: "not_found", Message: fmt.Sprintf("no endpoint at %s", r.URL.Path), status: http.StatusNotFound})
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		writeError(w, &Error{Code: "method_not_allowed", Message: "use POST", status: http.StatusMethodNotAllowed})
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		writeError(w, &Error{Code: "request_too_large", Message: fmt.Sprintf("request body exceeds %d bytes", maxBodyBytes), status: http.StatusRequestEntityTooLarge})
+		return
+	}
+	result, err := e.handle(body)
+	if err != nil {
+		writeError(w, toError(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// Error is the body of an error response, under an "error" key.
+type Error struct {
+	// Code identifies the kind of failure, for example "missing_field" or
+	// "no_sign_change". Codes are stable; messages may change.
+	Code string `json:"code" required:"true"`
+
+	Message string `json:"message" required:"true"`
+
+	// Field is the request field at fault, if any.
+	Field string `json:"field,omitempty"`
+
+	status int
+}
+
+// Error returns the message of the error.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// invalidField returns the error for a request field with an invalid value.
+func invalidField(field, format string, args ...interface{}) *Error {
+	return &Error{Code: "invalid_field", Message: fmt.Sprintf(format, args...), Field: field, status: http.StatusBadRequest}
+}
+
+// decode decodes the JSON object body into the struct pointed to by req. It
+// rejects unknown fields and missing required ones.
+func decode(body []byte, req interface{}) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return &Error{Code: "invalid_json", Message: "request body must be a JSON object: " + err.Error(), status: http.StatusBadRequest}
+	}
+	if err := checkRequired(reflect.TypeOf(req).Elem(), fields, ""); err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(body)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(req); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return invalidField(typeErr.Field, "%s must be %s", typeErr.Field, typeName(typeErr.Type))
+		}
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			field = strings.Trim(field, `"`)
+			return &Error{Code: "unknown_field", Message: fmt.Sprintf("unknown field %s", field), Field: field, status: http.StatusBadRequest}
+		}
+		return &Error{Code: "invalid_json", Message: err.Error(), status: http.StatusBadRequest}
+	}
+	return nil
+}
+
+// checkRequired returns an error for the first field of the struct type t
+// tagged required:"true" that is missing from fields, looking into arrays of
+// objects too. prefix is the path of the object in the request.
+func checkRequired(t reflect.Type, fields map[string]json.RawMessage, prefix string) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := jsonName(f)
+		if name == "" {
+			continue
+		}
+		value, ok := fields[name]
+		if !ok || string(value) == "null" {
+			if f.Tag.Get("required") == "true" {
+				return &Error{Code: "missing_field", Message: fmt.Sprintf("%s is required", prefix+name), Field: prefix + name, status: http.StatusBadRequest}
+			}
+			continue
+		}
+
+		if f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() == reflect.Struct {
+			// Type errors are left to the decoder.
+			var elements []map[string]json.RawMessage
+			if json.Unmarshal(value, &elements) != nil {
+				continue
+			}
+			for j, element := range elements {
+				if err := checkRequired(f.Type.Elem(), element, fmt.Sprintf("%s%s[%d].", prefix, name, j)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// typeName describes a Go type in JSON terms.
+func typeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Int, reflect.Int64, reflect.Int32:
+		return "an integer"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
+
+// jsonName returns the JSON name of a struct field, or "" if it is not encoded.
+func jsonName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return f.Name
+	}
+	return name
+}
+
+// toError converts a calculation error into an Error.
+func toError(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			return &Error{Code: c.code, Message: err.Error(), status: http.StatusUnprocessableEntity}
+		}
+	}
+	return &Error{Code: "calculation_failed", Message: err.Error(), status: http.StatusUnprocessableEntity}
+}
+
+// writeError writes an error response.
+func writeError(w http.ResponseWriter, e *Error) {
+	writeJSON(w, e.status, struct {
+		Error *Error `json:"error"`
+	}{e})
+}
+
+// writeJSON writes v as a JSON response with the given status. A value that
+// cannot be encoded is answered with an error instead: 422 for a result that
+// is not a finite number, which JSON cannot represent, and 500 otherwise.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		e := &Error{Code: "internal_error", Message: "the response could not be encoded", status: http.StatusInternalServerError}
+		var unsupported *json.UnsupportedValueError
+		if errors.As(err, &unsupported) {
+			e = &Error{Code: "non_finite_result", Message: "the result is not a finite number", status: http.StatusUnprocessableEntity}
+		}
+		body, _ = json.Marshal(struct {
+			Error *Error `json:"error"`
+		}{e})
+		status = e.status
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(body, '\n'))
+}
@@ -0,0 +1,208 @@
+package server
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// post sends body to the endpoint at path and decodes the JSON response into a map.
+func post(t *testing.T, srv *httptest.Server, path, body string) (int, map[string]interface{}) {
+	t.Helper()
+	resp, err := http.Post(srv.URL+path, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var decoded map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		t.Fatalf("Test failed for %s, invalid JSON response: %v", path, err)
+	}
+	return resp.StatusCode, decoded
+}
+
+func TestEndpoints(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+
+	tests := []struct {
+		path     string
+		body     string
+		field    string
+		expected float64
+	}{
+		{"/v1/fv", `{"rate": 0.05, "nper": 10, "pv": -1000}`, "value", 1628.894626777442},
+		{"/v1/pv", `{"rate": 0.05, "nper": 10, "pmt": -100, "due": true}`, "value", 810.7821675644},
+		{"/v1/pmt", `{"rate": 0.005, "nper": 360, "pv": 200000}`, "value", -1199.101050305},
+		{"/v1/nper", `{"rate": 0.01, "pmt": -100, "pv": 1000}`, "value", 10.588644459},
+		{"/v1/rate", `{"nper": 10, "pmt": -100, "pv": 700}`, "value", 0.0707282084},
+		{"/v1/npv", `{"rate": 0.1, "cash_flows": [-1000, 300, 400, 500]}`, "value", -21.0368144252443},
+		{"/v1/xnpv", `{"rate": 0.1, "cash_flows": [{"date": "2021-01-01", "amount": -1000}, {"date": "2022-01-01", "amount": 1100}]}`, "value", 0},
+		{"/v1/irr", `{"cash_flows": [-100, 110]}`, "rate", 0.1},
+		{"/v1/xirr", `{"cash_flows": [{"date": "2021-01-01", "amount": -1000}, {"date": "2022-01-01", "amount": 1100}]}`, "rate", 0.1},
+		{"/v1/mirr", `{"initial_investment": 1000, "cash_outflows": [100, 100, 100, 100, 100], "cash_inflows": [100, 100, 100, 100, 100], "finance_rate": 0.1}`, "value", -0.0939783899849107},
+		{"/v1/payback", `{"cash_flows": [-1000, 600, 600], "rate": 0.1}`, "period", 2},
+		{"/v1/convert-rate", `{"rate": 0.06, "from": "monthly", "to": "annual"}`, "rate", 0.061677811864497},
+		{"/v1/option", `{"type": "call", "spot": 42, "strike": 40, "expiry": 0.5, "rate": 0.1, "volatility": 0.2}`, "price", 4.759422392871532},
+	}
+
+	for _, test := range tests {
+		status, body := post(t, srv, test.path, test.body)
+		actual, ok := body[test.field].(float64)
+		if status != http.StatusOK || !ok || math.Abs(actual-test.expected) > 1e-6 {
+			t.Errorf("Test failed for %s, expected: '%f', got: %d %v", test.path, test.expected, status, body)
+		}
+	}
+
+	// Cash flows that change sign twice report both rates.
+	status, body := post(t, srv, "/v1/irr", `{"cash_flows": [-100, 230, -132], "guess": 0.19}`)
+	roots, _ := body["roots"].([]interface{})
+	if rate, _ := body["rate"].(float64); status != http.StatusOK || len(roots) != 2 || math.Abs(rate-0.2) > 1e-6 {
+		t.Errorf("Test failed, expected: '%f' of 2 roots, got: %d %v", 0.2, status, body)
+	}
+}
+
+func TestScheduleEndpoints(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+
+	status, body := post(t, srv, "/v1/amortize", `{"principal": 1000, "rate": 0.01, "periods": 3, "extra_payments": [{"period": 1, "amount": 500}]}`)
+	rows, _ := body["rows"].([]interface{})
+	if status != http.StatusOK || len(rows) != 2 {
+		t.Fatalf("Test failed, expected 2 rows, got: %d %v", status, body)
+	}
+	if first := rows[0].(map[string]interface{}); first["extra_principal"] != 500.0 || first["interest"] != 10.0 {
+		t.Errorf("Test failed, got: %v", first)
+	}
+
+	status, body = post(t, srv, "/v1/depreciate", `{"method": "macrs", "cost": 10000, "class": 5}`)
+	rows, _ = body["rows"].([]interface{})
+	if status != http.StatusOK || len(rows) != 6 || rows[1].(map[string]interface{})["depreciation"] != 3200.0 {
+		t.Errorf("Test failed, got: %d %v", status, body)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+
+	tests := []struct {
+		path   string
+		body   string
+		status int
+		code   string
+		field  string
+	}{
+		{"/v1/fv", `not json`, http.StatusBadRequest, "invalid_json", ""},
+		{"/v1/fv", `[1, 2]`, http.StatusBadRequest, "invalid_json", ""},
+		{"/v1/fv", `{"nper": 10}`, http.StatusBadRequest, "missing_field", "rate"},
+		{"/v1/fv", `{"rate": null, "nper": 10}`, http.StatusBadRequest, "missing_field", "rate"},
+		{"/v1/fv", `{"rate": "high", "nper": 10}`, http.StatusBadRequest, "invalid_field", "rate"},
+		{"/v1/fv", `{"rate": 0.1, "nper": 10, "extra": 1}`, http.StatusBadRequest, "unknown_field", "extra"},
+		{"/v1/xirr", `{"cash_flows": [{"date": "2021-01-01"}]}`, http.StatusBadRequest, "missing_field", "cash_flows[0].amount"},
+		{"/v1/xirr", `{"cash_flows": [{"date": "01/01/2021", "amount": 1}]}`, http.StatusBadRequest, "invalid_field", "cash_flows[0].date"},
+		{"/v1/xirr", `{"cash_flows": [], "basis": "act366"}`, http.StatusBadRequest, "invalid_field", "basis"},
+		{"/v1/option", `{"type": "straddle", "spot": 1, "strike": 1, "expiry": 1, "rate": 0, "volatility": 0.2}`, http.StatusBadRequest, "invalid_field", "type"},
+		{"/v1/payback", `{"cash_flows": [-1000]}`, http.StatusBadRequest, "invalid_field", "cash_flows"},
+		{"/v1/amortize", `{"principal": 1000, "rate": 0.01, "periods": 2000000000}`, http.StatusBadRequest, "invalid_field", "periods"},
+		{"/v1/amortize", `{"principal": 1000, "rate": 0.01, "periods": 12, "interest_only_periods": 2000000000}`, http.StatusBadRequest, "invalid_field", "interest_only_periods"},
+		{"/v1/depreciate", `{"method": "straight_line", "cost": 1000, "life": 2000000000}`, http.StatusBadRequest, "invalid_field", "life"},
+		{"/v1/fv", `{"rate": -2, "nper": 10}`, http.StatusUnprocessableEntity, "invalid_rate", ""},
+		{"/v1/fv", `{"rate": 1, "nper": 5000}`, http.StatusUnprocessableEntity, "non_finite_result", ""},
+		{"/v1/npv", `{"rate": 0, "cash_flows": [1e308, 1e308]}`, http.StatusUnprocessableEntity, "non_finite_result", ""},
+		{"/v1/irr", `{"cash_flows": [100, 200]}`, http.StatusUnprocessableEntity, "no_sign_change", ""},
+		{"/v1/payback", `{"cash_flows": [-1000, 100]}`, http.StatusUnprocessableEntity, "no_payback", ""},
+		{"/v1/depreciate", `{"method": "macrs", "cost": 1000, "class": 6}`, http.StatusUnprocessableEntity, "invalid_macrs", ""},
+		{"/v1/nope", `{}`, http.StatusNotFound, "not_found", ""},
+	}
+
+	for _, test := range tests {
+		status, body := post(t, srv, test.path, test.body)
+		e, _ := body["error"].(map[string]interface{})
+		field, _ := e["field"].(string)
+		if status != test.status || e["code"] != test.code || field != test.field || e["message"] == "" {
+			t.Errorf("Test failed for %s %s, expected: %d %s %s, got: %d %v", test.path, test.body, test.status, test.code, test.field, status, body)
+		}
+	}
+
+	resp, err := http.Get(srv.URL + "/v1/fv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed || resp.Header.Get("Allow") != "POST" {
+		t.Errorf("Test failed, expected: %d, got: %d", http.StatusMethodNotAllowed, resp.StatusCode)
+	}
+
+	status, body := post(t, srv, "/v1/npv", `{"rate": 0.1, "cash_flows": [`+strings.Repeat("1,", maxBodyBytes/2)+`1]}`)
+	if e, _ := body["error"].(map[string]interface{}); status != http.StatusRequestEntityTooLarge || e["code"] != "request_too_large" {
+		t.Errorf("Test failed, expected: %d, got: %d %v", http.StatusRequestEntityTooLarge, status, body)
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	s := New()
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var spec struct {
+		OpenAPI string `json:"openapi"`
+		Paths   map[string]struct {
+			Post struct {
+				OperationID string `json:"operationId"`
+				RequestBody struct {
+					Content map[string]struct {
+						Schema map[string]string `json:"schema"`
+					} `json:"content"`
+				} `json:"requestBody"`
+			} `json:"post"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Required   []string                          `json:"required"`
+				Properties map[string]map[string]interface{} `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every registered endpoint is documented.
+	if spec.OpenAPI != "3.0.3" || len(spec.Paths) != len(s.Endpoints()) {
+		t.Fatalf("Test failed, expected %d paths, got: %d", len(s.Endpoints()), len(spec.Paths))
+	}
+	for _, e := range s.Endpoints() {
+		schema := spec.Paths[e.Path].Post.RequestBody.Content["application/json"].Schema["$ref"]
+		if schema != "#/components/schemas/"+e.Request.Name() {
+			t.Errorf("Test failed for %s, got the request schema '%s'", e.Path, schema)
+		}
+		if _, ok := spec.Components.Schemas[e.Response.Name()]; !ok {
+			t.Errorf("Test failed for %s, no schema for %s", e.Path, e.Response.Name())
+		}
+	}
+
+	fv := spec.Components.Schemas["FVRequest"]
+	if strings.Join(fv.Required, ",") != "rate,nper" || fv.Properties["due"]["type"] != "boolean" {
+		t.Errorf("Test failed, got: %+v", fv)
+	}
+	flow := spec.Components.Schemas["DatedCashFlow"]
+	if flow.Properties["date"]["format"] != "date" {
+		t.Errorf("Test failed, got: %+v", flow)
+	}
+	if enum, _ := spec.Components.Schemas["OptionRequest"].Properties["type"]["enum"].([]interface{}); len(enum) != 2 {
+		t.Errorf("Test failed, expected the enum call, put, got: %v", enum)
+	}
+	if spec.Paths["/v1/convert-rate"].Post.OperationID != "convert_rate" {
+		t.Errorf("Test failed, got: '%s'", spec.Paths["/v1/convert-rate"].Post.OperationID)
+	}
+}
@@ -0,0 +1,119 @@
+package gofin
+
+import "math"
+
+// epsilon is the machine epsilon for float64.
+const epsilon = 2.220446049250313e-16
+
+// newton runs a Newton-Raphson iteration from x0 and returns the root and the
+// number of iterations used. It gives up with ErrNoConvergence when the
+// derivative vanishes, an iterate leaves the open interval (lower, upper) or
+// maxIterations is exhausted, so callers can fall back to a bracketing method.
+func newton(f, df func(float64) float64, x0, lower, upper, tolerance float64, maxIterations int) (float64, int, error) {
+	x := x0
+	for i := 1; i <= maxIterations; i++ {
+		fx := f(x)
+		if fx == 0 {
+			return x, i, nil
+		}
+
+		d := df(x)
+		if d == 0 || math.IsNaN(d) || math.IsInf(d, 0) {
+			return x, i, ErrNoConvergence
+		}
+
+		next := x - fx/d
+		if math.IsNaN(next) || next <= lower || next >= upper {
+			return x, i, ErrNoConvergence
+		}
+
+		if math.Abs(next-x) <= tolerance*math.Max(1, math.Abs(next)) {
+			return next, i, nil
+		}
+		x = next
+	}
+
+	return x, maxIterations, ErrNoConvergence
+}
+
+// brent finds a root of f inside [a, b] with Brent's method, which combines
+// inverse quadratic interpolation and the secant method with a bisection
+// safeguard. f(a) and f(b) must have opposite signs.
+func brent(f func(float64) float64, a, b, tolerance float64, maxIterations int) (float64, int, error) {
+	fa, fb := f(a), f(b)
+	if fa == 0 {
+		return a, 0, nil
+	}
+	if fb == 0 {
+		return b, 0, nil
+	}
+	if (fa > 0) == (fb > 0) {
+		return 0, 0, ErrNoConvergence
+	}
+
+	c, fc := a, fa
+	d := b - a
+	e := d
+	for i := 1; i <= maxIterations; i++ {
+		if (fb > 0) == (fc > 0) {
+			// Keep the root bracketed between b and c.
+			c, fc = a, fa
+			d = b - a
+			e = d
+		}
+		if math.Abs(fc) < math.Abs(fb) {
+			a, b, c = b, c, b
+			fa, fb, fc = fb, fc, fb
+		}
+
+		tol := 2*epsilon*math.Abs(b) + 0.5*tolerance
+		m := 0.5 * (c - b)
+		if math.Abs(m) <= tol || fb == 0 {
+			return b, i, nil
+		}
+
+		if math.Abs(e) >= tol && math.Abs(fa) > math.Abs(fb) {
+			// Attempt interpolation.
+			var p, q float64
+			s := fb / fa
+			if a == c {
+				p = 2 * m * s
+				q = 1 - s
+			} else {
+				q = fa / fc
+				r := fb / fc
+				p = s * (2*m*q*(q-r) - (b-a)*(r-1))
+				q = (q - 1) * (r - 1) * (s - 1)
+			}
+			if p > 0 {
+				q = -q
+			} else {
+				p = -p
+			}
+			if 2*p < math.Min(3*m*q-math.Abs(tol*q), math.Abs(e*q)) {
+				e = d
+				d = p / q
+			} else {
+				// Interpolation failed, fall back to bisection.
+				d = m
+				e = d
+			}
+		} else {
+			// Bounds decreasing too slowly, fall back to bisection.
+			d = m
+			e = d
+		}
+
+		a, fa = b, fb
+		if math.Abs(d) > tol {
+			b += d
+		} else if m > 0 {
+			b += tol
+		} else {
+			b -= tol
+		}
+		fb = f(b)
+	}
+
+	return b, maxIterations, ErrNoConvergence
+}
@@ -0,0 +1,238 @@
+package gofin
+
+import "math"
+
+// PaymentTiming says whether payments fall at the end or the beginning of
+// each period. It matches the spreadsheet "type" argument: 0 for the end and
+// 1 for the beginning.
+type PaymentTiming int
+
+const (
+	// EndOfPeriod is an ordinary annuity (spreadsheet type 0).
+	EndOfPeriod PaymentTiming = 0
+
+	// BeginningOfPeriod is an annuity due (spreadsheet type 1).
+	BeginningOfPeriod PaymentTiming = 1
+)
+
+// The functions in this file follow spreadsheet sign conventions: money paid
+// out is negative and money received is positive, and every call satisfies
+// the time value of money equation
+// PV * (1 + r)^n + PMT * (1 + r * type) * ((1 + r)^n - 1) / r + FV = 0
+// PV is the present value,
+// PMT is the payment per period,
+// FV is the future value,
+// r is the interest rate per period,
+// n is the number of periods,
+// type is 0 for payments at the end of a period and 1 for the beginning.
+
+// FV returns the future value of an investment with periodic payments, like the spreadsheet FV function.
+func FV(rate, nper, pmt, pv float64, timing PaymentTiming) (float64, error) {
+	if err := checkTVM(rate, timing); err != nil {
+		return 0.0, err
+	}
+	if rate == 0 {
+		return -(pv + pmt*nper), nil
+	}
+
+	growth := math.Pow(1+rate, nper)
+	return -(pv*growth + pmt*(1+rate*float64(timing))*(growth-1)/rate), nil
+}
+
+// PV returns the present value of an investment with periodic payments, like the spreadsheet PV function.
+func PV(rate, nper, pmt, fv float64, timing PaymentTiming) (float64, error) {
+	if err := checkTVM(rate, timing); err != nil {
+		return 0.0, err
+	}
+	if rate == 0 {
+		return -(fv + pmt*nper), nil
+	}
+
+	growth := math.Pow(1+rate, nper)
+	return -(fv + pmt*(1+rate*float64(timing))*(growth-1)/rate) / growth, nil
+}
+
+// PMT returns the payment per period of a loan or investment, like the spreadsheet PMT function.
+func PMT(rate, nper, pv, fv float64, timing PaymentTiming) (float64, error) {
+	if err := checkTVM(rate, timing); err != nil {
+		return 0.0, err
+	}
+	if nper == 0 {
+		return 0.0, ErrInvalidPeriods
+	}
+	if rate == 0 {
+		return -(pv + fv) / nper, nil
+	}
+
+	growth := math.Pow(1+rate, nper)
+	return -rate * (pv*growth + fv) / ((1 + rate*float64(timing)) * (growth - 1)), nil
+}
+
+// NPER returns the number of periods of a loan or investment, like the spreadsheet NPER function.
+// It returns ErrNoSolution when the payment can never reach the future value.
+func NPER(rate, pmt, pv, fv float64, timing PaymentTiming) (float64, error) {
+	if err := checkTVM(rate, timing); err != nil {
+		return 0.0, err
+	}
+	if rate == 0 {
+		if pmt == 0 {
+			return 0.0, ErrNoSolution
+		}
+		return -(pv + fv) / pmt, nil
+	}
+
+	z := pmt * (1 + rate*float64(timing)) / rate
+	ratio := (z - fv) / (pv + z)
+	if ratio <= 0 || math.IsInf(ratio, 0) || math.IsNaN(ratio) {
+		return 0.0, ErrNoSolution
+	}
+	return math.Log(ratio) / math.Log1p(rate), nil
+}
+
+// RATE returns the interest rate per period of an annuity, like the spreadsheet RATE function.
+// It solves the time value of money equation with the same Newton-Raphson and Brent
+// search used by IRRSolver, starting from guess; the spreadsheet default is 0.1.
+func RATE(nper, pmt, pv, fv float64, timing PaymentTiming, guess float64) (float64, error) {
+	if timing != EndOfPeriod && timing != BeginningOfPeriod {
+		return 0.0, ErrInvalidTiming
+	}
+	if nper <= 0 {
+		return 0.0, ErrInvalidPeriods
+	}
+
+	s := IRRSolver{Guess: guess}.withDefaults()
+	f := func(rate float64) float64 {
+		value, _ := tvmEquation(rate, nper, pmt, pv, fv, timing)
+		return value
+	}
+	df := func(rate float64) float64 {
+		_, derivative := tvmEquation(rate, nper, pmt, pv, fv, timing)
+		return derivative
+	}
+
+	result, err := s.solve(f, df)
+	if err != nil {
+		return 0.0, err
+	}
+	return result.Rate, nil
+}
+
+// IPMT returns the interest portion of the payment in period per, like the spreadsheet IPMT function.
+func IPMT(rate float64, per int, nper, pv, fv float64, timing PaymentTiming) (float64, error) {
+	pmt, err := PMT(rate, nper, pv, fv, timing)
+	if err != nil {
+		return 0.0, err
+	}
+	return interestPortion(rate, per, nper, pmt, pv, timing)
+}
+
+// PPMT returns the principal portion of the payment in period per, like the spreadsheet PPMT function.
+func PPMT(rate float64, per int, nper, pv, fv float64, timing PaymentTiming) (float64, error) {
+	pmt, err := PMT(rate, nper, pv, fv, timing)
+	if err != nil {
+		return 0.0, err
+	}
+	interest, err := interestPortion(rate, per, nper, pmt, pv, timing)
+	if err != nil {
+		return 0.0, err
+	}
+	return pmt - interest, nil
+}
+
+// CUMIPMT returns the cumulative interest paid on a loan between periods start and end inclusive,
+// like the spreadsheet CUMIPMT function.
+func CUMIPMT(rate, nper, pv float64, start, end int, timing PaymentTiming) (float64, error) {
+	interest, _, err := cumulativePayments(rate, nper, pv, start, end, timing)
+	return interest, err
+}
+
+// CUMPRINC returns the cumulative principal paid on a loan between periods start and end inclusive,
+// like the spreadsheet CUMPRINC function.
+func CUMPRINC(rate, nper, pv float64, start, end int, timing PaymentTiming) (float64, error) {
+	_, principal, err := cumulativePayments(rate, nper, pv, start, end, timing)
+	return principal, err
+}
+
+// cumulativePayments sums the interest and principal portions of a loan's
+// payments from period start to period end.
+func cumulativePayments(rate, nper, pv float64, start, end int, timing PaymentTiming) (float64, float64, error) {
+	if rate <= 0 {
+		return 0.0, 0.0, ErrInvalidRate
+	}
+	if nper <= 0 {
+		return 0.0, 0.0, ErrInvalidPeriods
+	}
+	if pv <= 0 {
+		return 0.0, 0.0, ErrZeroValue
+	}
+	if start < 1 || end < start || float64(end) > nper {
+		return 0.0, 0.0, ErrInvalidPeriods
+	}
+
+	pmt, err := PMT(rate, nper, pv, 0, timing)
+	if err != nil {
+		return 0.0, 0.0, err
+	}
+
+	interest, principal := 0.0, 0.0
+	for per := start; per <= end; per++ {
+		ipmt, err := interestPortion(rate, per, nper, pmt, pv, timing)
+		if err != nil {
+			return 0.0, 0.0, err
+		}
+		interest += ipmt
+		principal += pmt - ipmt
+	}
+	return interest, principal, nil
+}
+
+// interestPortion returns the interest part of payment pmt in period per,
+// which is the interest accrued on the balance left after per-1 payments.
+func interestPortion(rate float64, per int, nper, pmt, pv float64, timing PaymentTiming) (float64, error) {
+	if per < 1 || float64(per) > nper {
+		return 0.0, ErrInvalidPeriods
+	}
+	if timing == BeginningOfPeriod && per == 1 {
+		// The first payment of an annuity due is made before any interest accrues.
+		return 0.0, nil
+	}
+
+	balance, err := FV(rate, float64(per-1), pmt, pv, timing)
+	if err != nil {
+		return 0.0, err
+	}
+	interest := balance * rate
+	if timing == BeginningOfPeriod {
+		interest /= 1 + rate
+	}
+	return interest, nil
+}
+
+// tvmEquation returns the left-hand side of the time value of money equation
+// at rate together with its derivative with respect to the rate.
+func tvmEquation(rate, nper, pmt, pv, fv float64, timing PaymentTiming) (float64, float64) {
+	t := float64(timing)
+	if rate == 0 {
+		return pv + pmt*nper + fv, pv*nper + pmt*(nper*(nper-1)/2+t*nper)
+	}
+
+	growth := math.Pow(1+rate, nper)
+	dGrowth := nper * growth / (1 + rate)
+	annuity := (growth - 1) / rate
+	dAnnuity := (dGrowth*rate - (growth - 1)) / (rate * rate)
+
+	value := pv*growth + pmt*(1+rate*t)*annuity + fv
+	derivative := pv*dGrowth + pmt*(t*annuity+(1+rate*t)*dAnnuity)
+	return value, derivative
+}
+
+// checkTVM validates the rate and payment timing shared by the spreadsheet functions.
+func checkTVM(rate float64, timing PaymentTiming) error {
+	if rate <= -1 {
+		return ErrInvalidRate
+	}
+	if timing != EndOfPeriod && timing != BeginningOfPeriod {
+		return ErrInvalidTiming
+	}
+	return nil
+}
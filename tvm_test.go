@@ -0,0 +1,219 @@
+package gofin
+
+import (
+	"errors"
+	"testing"
+)
+
+// The expected values below are the outputs of the spreadsheet formula named
+// in each case, to the precision the spreadsheet documentation shows.
+
+func TestFV(t *testing.T) {
+	tests := []struct {
+		formula             string
+		rate, nper, pmt, pv float64
+		timing              PaymentTiming
+		expected, tolerance float64
+	}{
+		{"=FV(0.06/12, 10, -200, -500, 1)", 0.06 / 12, 10, -200, -500, BeginningOfPeriod, 2581.40, 0.005},
+		{"=FV(0.12/12, 12, -1000)", 0.12 / 12, 12, -1000, 0, EndOfPeriod, 12682.50, 0.005},
+		{"=FV(0.11/12, 35, -2000, 0, 1)", 0.11 / 12, 35, -2000, 0, BeginningOfPeriod, 82846.25, 0.005},
+		{"=FV(0, 10, -100, -1000)", 0, 10, -100, -1000, EndOfPeriod, 2000, 1e-9},
+	}
+
+	for _, test := range tests {
+		actual, err := FV(test.rate, test.nper, test.pmt, test.pv, test.timing)
+		if err != nil || notWithin(actual, test.expected, test.tolerance) {
+			t.Errorf("%s: Test failed, expected: '%.10f', got: '%.10f' (%v)", test.formula, test.expected, actual, err)
+		}
+	}
+}
+
+func TestPV(t *testing.T) {
+	tests := []struct {
+		formula             string
+		rate, nper, pmt, fv float64
+		timing              PaymentTiming
+		expected, tolerance float64
+	}{
+		{"=PV(0.08/12, 12*20, 500)", 0.08 / 12, 240, 500, 0, EndOfPeriod, -59777.15, 0.005},
+		{"=PV(0, 10, -100, -1000)", 0, 10, -100, -1000, EndOfPeriod, 2000, 1e-9},
+	}
+
+	for _, test := range tests {
+		actual, err := PV(test.rate, test.nper, test.pmt, test.fv, test.timing)
+		if err != nil || notWithin(actual, test.expected, test.tolerance) {
+			t.Errorf("%s: Test failed, expected: '%.10f', got: '%.10f' (%v)", test.formula, test.expected, actual, err)
+		}
+	}
+}
+
+func TestPMT(t *testing.T) {
+	tests := []struct {
+		formula             string
+		rate, nper, pv, fv  float64
+		timing              PaymentTiming
+		expected, tolerance float64
+	}{
+		{"=PMT(0.08/12, 10, 10000)", 0.08 / 12, 10, 10000, 0, EndOfPeriod, -1037.03, 0.005},
+		{"=PMT(0.08/12, 10, 10000, 0, 1)", 0.08 / 12, 10, 10000, 0, BeginningOfPeriod, -1030.16, 0.005},
+		{"=PMT(0.06/12, 18*12, 0, 50000)", 0.06 / 12, 216, 0, 50000, EndOfPeriod, -129.08, 0.005},
+		{"=PMT(0, 12, 1200)", 0, 12, 1200, 0, EndOfPeriod, -100, 1e-9},
+	}
+
+	for _, test := range tests {
+		actual, err := PMT(test.rate, test.nper, test.pv, test.fv, test.timing)
+		if err != nil || notWithin(actual, test.expected, test.tolerance) {
+			t.Errorf("%s: Test failed, expected: '%.10f', got: '%.10f' (%v)", test.formula, test.expected, actual, err)
+		}
+	}
+
+	// A balloon loan's PMT matches the payment used by Amortize.
+	actual, _ := PMT(0.01, 12, 10000, -4000, EndOfPeriod)
+	if expected := -levelPayment(10000, 0.01, 12, 4000); notWithin(actual, expected, 1e-9) {
+		t.Errorf("Test failed, expected: '%.10f', got: '%.10f'", expected, actual)
+	}
+}
+
+func TestNPER(t *testing.T) {
+	tests := []struct {
+		formula             string
+		rate, pmt, pv, fv   float64
+		timing              PaymentTiming
+		expected, tolerance float64
+	}{
+		{"=NPER(0.12/12, -100, -1000, 10000, 1)", 0.01, -100, -1000, 10000, BeginningOfPeriod, 59.6738657, 5e-8},
+		{"=NPER(0.01, -100, -1000, 10000)", 0.01, -100, -1000, 10000, EndOfPeriod, 60.0821229, 5e-8},
+		{"=NPER(0.01, -100, -1000)", 0.01, -100, -1000, 0, EndOfPeriod, -9.57859404, 5e-9},
+	}
+
+	for _, test := range tests {
+		actual, err := NPER(test.rate, test.pmt, test.pv, test.fv, test.timing)
+		if err != nil || notWithin(actual, test.expected, test.tolerance) {
+			t.Errorf("%s: Test failed, expected: '%.10f', got: '%.10f' (%v)", test.formula, test.expected, actual, err)
+		}
+	}
+
+	if _, err := NPER(0.1, -10, 1000, 0, EndOfPeriod); !errors.Is(err, ErrNoSolution) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrNoSolution, err)
+	}
+}
+
+func TestRATE(t *testing.T) {
+	tests := []struct {
+		formula             string
+		nper, pmt, pv, fv   float64
+		timing              PaymentTiming
+		expected, tolerance float64
+	}{
+		{"=RATE(4*12, -200, 8000)", 48, -200, 8000, 0, EndOfPeriod, 0.0077014725, 5e-11},
+		{"=RATE(10, -100, 1000, 0, 1)", 10, -100, 1000, 0, BeginningOfPeriod, 0, 1e-10},
+		{"=RATE(10, 0, -1000, 2593.742460100002)", 10, 0, -1000, 2593.742460100002, EndOfPeriod, 0.1, 1e-10},
+	}
+
+	for _, test := range tests {
+		actual, err := RATE(test.nper, test.pmt, test.pv, test.fv, test.timing, 0)
+		if err != nil || notWithin(actual, test.expected, test.tolerance) {
+			t.Errorf("%s: Test failed, expected: '%.12f', got: '%.12f' (%v)", test.formula, test.expected, actual, err)
+		}
+	}
+}
+
+func TestIPMTAndPPMT(t *testing.T) {
+	tests := []struct {
+		formula             string
+		fn                  func(float64, int, float64, float64, float64, PaymentTiming) (float64, error)
+		rate                float64
+		per                 int
+		nper, pv            float64
+		expected, tolerance float64
+	}{
+		{"=IPMT(0.1/12, 1, 3*12, 8000)", IPMT, 0.1 / 12, 1, 36, 8000, -66.67, 0.005},
+		{"=IPMT(0.1, 3, 3, 8000)", IPMT, 0.1, 3, 3, 8000, -292.45, 0.005},
+		{"=PPMT(0.1/12, 1, 2*12, 2000)", PPMT, 0.1 / 12, 1, 24, 2000, -75.62, 0.005},
+		{"=PPMT(0.08, 10, 10, 200000)", PPMT, 0.08, 10, 10, 200000, -27598.05, 0.005},
+	}
+
+	for _, test := range tests {
+		actual, err := test.fn(test.rate, test.per, test.nper, test.pv, 0, EndOfPeriod)
+		if err != nil || notWithin(actual, test.expected, test.tolerance) {
+			t.Errorf("%s: Test failed, expected: '%.10f', got: '%.10f' (%v)", test.formula, test.expected, actual, err)
+		}
+	}
+
+	if actual, _ := IPMT(0.01, 1, 12, 1000, 0, BeginningOfPeriod); actual != 0 {
+		t.Errorf("Test failed, expected no interest in the first period of an annuity due, got: '%f'", actual)
+	}
+}
+
+func TestCUMIPMTAndCUMPRINC(t *testing.T) {
+	tests := []struct {
+		formula             string
+		fn                  func(float64, float64, float64, int, int, PaymentTiming) (float64, error)
+		start, end          int
+		expected, tolerance float64
+	}{
+		{"=CUMIPMT(0.09/12, 30*12, 125000, 13, 24, 0)", CUMIPMT, 13, 24, -11135.23, 0.005},
+		{"=CUMIPMT(0.09/12, 30*12, 125000, 1, 1, 0)", CUMIPMT, 1, 1, -937.5, 1e-9},
+		{"=CUMPRINC(0.09/12, 30*12, 125000, 13, 24, 0)", CUMPRINC, 13, 24, -934.1071234, 5e-8},
+		{"=CUMPRINC(0.09/12, 30*12, 125000, 1, 1, 0)", CUMPRINC, 1, 1, -68.27827118, 5e-9},
+	}
+
+	for _, test := range tests {
+		actual, err := test.fn(0.09/12, 360, 125000, test.start, test.end, EndOfPeriod)
+		if err != nil || notWithin(actual, test.expected, test.tolerance) {
+			t.Errorf("%s: Test failed, expected: '%.10f', got: '%.10f' (%v)", test.formula, test.expected, actual, err)
+		}
+	}
+
+	errorTests := []struct {
+		rate, nper, pv float64
+		start, end     int
+		expected       error
+	}{
+		{0.01, 12, 1000, 6, 3, ErrInvalidPeriods},
+		{0, 12, 1000, 1, 3, ErrInvalidRate},
+		{0.01, 0, 1000, 1, 3, ErrInvalidPeriods},
+		{0.01, 12, 0, 1, 3, ErrZeroValue},
+	}
+	for _, test := range errorTests {
+		if _, err := CUMIPMT(test.rate, test.nper, test.pv, test.start, test.end, EndOfPeriod); !errors.Is(err, test.expected) {
+			t.Errorf("Test failed, expected: '%v', got: '%v'", test.expected, err)
+		}
+		if _, err := CUMPRINC(test.rate, test.nper, test.pv, test.start, test.end, EndOfPeriod); !errors.Is(err, test.expected) {
+			t.Errorf("Test failed, expected: '%v', got: '%v'", test.expected, err)
+		}
+	}
+	if _, err := PMT(0.01, 12, 1000, 0, 2); !errors.Is(err, ErrInvalidTiming) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidTiming, err)
+	}
+}
+
+func TestTVMRoundTrip(t *testing.T) {
+	var rate, nper, pv, fv float64 = 0.045 / 12, 180, 250000, -50000
+
+	for _, timing := range []PaymentTiming{EndOfPeriod, BeginningOfPeriod} {
+		pmt, _ := PMT(rate, nper, pv, fv, timing)
+
+		if actual, _ := PV(rate, nper, pmt, fv, timing); notWithin(actual, pv, 1e-7) {
+			t.Errorf("Test failed, expected: '%f', got: '%f'", pv, actual)
+		}
+		if actual, _ := FV(rate, nper, pmt, pv, timing); notWithin(actual, fv, 1e-7) {
+			t.Errorf("Test failed, expected: '%f', got: '%f'", fv, actual)
+		}
+		if actual, _ := NPER(rate, pmt, pv, fv, timing); notWithin(actual, nper, 1e-9) {
+			t.Errorf("Test failed, expected: '%f', got: '%f'", nper, actual)
+		}
+		if actual, err := RATE(nper, pmt, pv, fv, timing, 0); err != nil || notWithin(actual, rate, 1e-12) {
+			t.Errorf("Test failed, expected: '%f', got: '%f' (%v)", rate, actual, err)
+		}
+
+		// The cumulative functions assume the loan is fully repaid.
+		pmt, _ = PMT(rate, nper, pv, 0, timing)
+		interest, _ := CUMIPMT(rate, nper, pv, 1, 180, timing)
+		principal, _ := CUMPRINC(rate, nper, pv, 1, 180, timing)
+		if notWithin(interest+principal, pmt*nper, 1e-6) || notWithin(principal, -pv, 1e-6) {
+			t.Errorf("Test failed, expected interest and principal to sum to the payments, got: '%f' + '%f'", interest, principal)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package gofin
+
+import (
+	"math"
+	"time"
+)
+
+// DatedCashFlow is a cash flow paid on a specific date.
+type DatedCashFlow struct {
+	Date   time.Time
+	Amount float64
+}
+
+// XIRROptions configures XIRR.
+type XIRROptions struct {
+	// Solver controls the guess, tolerance, iterations and bounds of the
+	// root search. The zero value uses the IRRSolver defaults.
+	Solver IRRSolver
+
+	// DayCount is the basis used to convert dates into years. Defaults to
+	// Actual365Fixed, which matches spreadsheet XIRR.
+	DayCount DayCounter
+}
+
+// XNPV returns the net present value of irregularly dated cash flows.
+// Each cash flow is discounted to the date of the first one using an
+// Actual/365 year fraction, like spreadsheet XNPV.
+// XNPV = sum(C_i / (1 + r)^((d_i - d_0) / 365))
+// C_i is the cash flow on date d_i,
+// d_0 is the date of the first cash flow,
+// r is the annual discount rate.
+func XNPV(rate float64, cashFlows []DatedCashFlow) (float64, error) {
+	return XNPVWithBasis(rate, cashFlows, Actual365Fixed{})
+}
+
+// XNPVWithBasis is like XNPV but converts dates into years with dayCount.
+func XNPVWithBasis(rate float64, cashFlows []DatedCashFlow, dayCount DayCounter) (float64, error) {
+	if rate <= -1 {
+		return 0.0, ErrInvalidRate
+	}
+	times, err := yearFractions(cashFlows, dayCount)
+	if err != nil {
+		return 0.0, err
+	}
+
+	npv, _ := xnpvAndDerivative(rate, cashFlows, times)
+	return npv, nil
+}
+
+// XIRR returns the internal rate of return of irregularly dated cash flows,
+// the annual rate at which their XNPV is zero. Like IRRSolver.Solve it
+// reports every root between the solver's bounds when the solver has
+// AllRoots set.
+func XIRR(cashFlows []DatedCashFlow, opts XIRROptions) (IRRResult, error) {
+	s := opts.Solver.withDefaults()
+
+	times, err := yearFractions(cashFlows, opts.DayCount)
+	if err != nil {
+		return IRRResult{}, err
+	}
+	if len(cashFlows) < 2 {
+		return IRRResult{}, ErrEmptyInput
+	}
+	amounts := make([]float64, len(cashFlows))
+	for i, cashFlow := range cashFlows {
+		amounts[i] = cashFlow.Amount
+	}
+	if !hasSignChange(amounts) {
+		return IRRResult{}, ErrNoSignChange
+	}
+	if err := s.checkBounds(); err != nil {
+		return IRRResult{}, err
+	}
+
+	f := func(rate float64) float64 {
+		npv, _ := xnpvAndDerivative(rate, cashFlows, times)
+		return npv
+	}
+	df := func(rate float64) float64 {
+		_, d := xnpvAndDerivative(rate, cashFlows, times)
+		return d
+	}
+
+	return s.solve(f, df)
+}
+
+// yearFractions returns the time in years from the first cash flow's date to
+// each cash flow's date. A nil dayCount means Actual365Fixed.
+func yearFractions(cashFlows []DatedCashFlow, dayCount DayCounter) ([]float64, error) {
+	if len(cashFlows) == 0 {
+		return nil, ErrEmptyInput
+	}
+	if dayCount == nil {
+		dayCount = Actual365Fixed{}
+	}
+
+	start := cashFlows[0].Date
+	times := make([]float64, len(cashFlows))
+	for i, cashFlow := range cashFlows {
+		if cashFlow.Date.Before(start) {
+			return nil, ErrInvalidDate
+		}
+		times[i] = dayCount.YearFraction(start, cashFlow.Date)
+	}
+	return times, nil
+}
+
+// xnpvAndDerivative returns the XNPV at rate and its derivative with respect to the rate.
+func xnpvAndDerivative(rate float64, cashFlows []DatedCashFlow, times []float64) (float64, float64) {
+	logGrowth := math.Log1p(rate)
+	npv, derivative := 0.0, 0.0
+	for i, cashFlow := range cashFlows {
+		discounted := cashFlow.Amount * math.Exp(-times[i]*logGrowth)
+		npv += discounted
+		derivative -= times[i] * discounted / (1 + rate)
+	}
+	return npv, derivative
+}
@@ -0,0 +1,91 @@
+package gofin
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// spreadsheetCashFlows is the worked example from the spreadsheet XNPV and XIRR documentation.
+var spreadsheetCashFlows = []DatedCashFlow{
+	{date(2008, 1, 1), -10000},
+	{date(2008, 3, 1), 2750},
+	{date(2008, 10, 30), 4250},
+	{date(2009, 2, 15), 3250},
+	{date(2009, 4, 1), 2750},
+}
+
+func TestXNPV(t *testing.T) {
+	tests := []struct {
+		name      string
+		rate      float64
+		cashFlows []DatedCashFlow
+		expected  float64
+	}{
+		{"spreadsheet example", 0.09, spreadsheetCashFlows, 2086.6476020315},
+		{"leap year", 0.1, []DatedCashFlow{{date(2020, 1, 1), -1000}, {date(2021, 1, 1), 1100}}, -1000 + 1100/math.Pow(1.1, 366.0/365)},
+		{"same day", 0.05, []DatedCashFlow{{date(2020, 6, 30), -500}, {date(2020, 6, 30), 200}}, -300},
+		{"time of day ignored", 0.1, []DatedCashFlow{{date(2021, 1, 1).Add(23 * time.Hour), -1000}, {date(2022, 1, 1), 1100}}, 0},
+	}
+
+	for _, test := range tests {
+		actual, err := XNPV(test.rate, test.cashFlows)
+		if err != nil || notWithin(actual, test.expected, 1e-9) {
+			t.Errorf("%s: Test failed, expected: '%.10f', got: '%.10f' (%v)", test.name, test.expected, actual, err)
+		}
+	}
+}
+
+func TestXIRR(t *testing.T) {
+	tests := []struct {
+		name      string
+		cashFlows []DatedCashFlow
+		expected  float64
+	}{
+		// Spreadsheets display 0.373362535 after stopping at a looser tolerance.
+		{"spreadsheet example", spreadsheetCashFlows, 0.3733625335},
+		{"leap year", []DatedCashFlow{{date(2020, 1, 1), -1000}, {date(2021, 1, 1), 1100}}, math.Pow(1.1, 365.0/366) - 1},
+		{"two years", []DatedCashFlow{{date(2021, 1, 1), -1000}, {date(2022, 1, 1), 100}, {date(2023, 1, 1), 1100}}, 0.1},
+		{"loss", []DatedCashFlow{{date(2021, 3, 1), -1000}, {date(2021, 9, 1), 900}}, math.Pow(0.9, 365.0/184) - 1},
+	}
+
+	for _, test := range tests {
+		actual, err := XIRR(test.cashFlows, XIRROptions{})
+		if err != nil || notWithin(actual.Rate, test.expected, 1e-9) {
+			t.Errorf("%s: Test failed, expected: '%.10f', got: '%.10f' (%v)", test.name, test.expected, actual.Rate, err)
+		}
+		npv, _ := XNPV(actual.Rate, test.cashFlows)
+		if notWithin(npv, 0, 1e-9) {
+			t.Errorf("%s: Test failed, expected XNPV at XIRR to be zero, got: '%g'", test.name, npv)
+		}
+	}
+}
+
+func TestXIRRDayCount(t *testing.T) {
+	cashFlows := []DatedCashFlow{{date(2021, 1, 1), -1000}, {date(2022, 1, 1), 1100}}
+	var expected float64 = math.Pow(1.1, 360.0/365) - 1
+
+	actual, err := XIRR(cashFlows, XIRROptions{DayCount: Actual360{}})
+	if err != nil || notWithin(actual.Rate, expected, 1e-9) {
+		t.Errorf("Test failed, expected: '%.10f', got: '%.10f' (%v)", expected, actual.Rate, err)
+	}
+}
+
+func TestXIRRErrors(t *testing.T) {
+	outOfOrder := []DatedCashFlow{{date(2021, 1, 1), -1000}, {date(2020, 1, 1), 1100}}
+	if _, err := XIRR(outOfOrder, XIRROptions{}); !errors.Is(err, ErrInvalidDate) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrInvalidDate, err)
+	}
+	if _, err := XNPV(0.1, nil); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrEmptyInput, err)
+	}
+	allPositive := []DatedCashFlow{{date(2021, 1, 1), 1000}, {date(2022, 1, 1), 1100}}
+	if _, err := XIRR(allPositive, XIRROptions{}); !errors.Is(err, ErrNoSignChange) {
+		t.Errorf("Test failed, expected: '%v', got: '%v'", ErrNoSignChange, err)
+	}
+}